@@ -0,0 +1,111 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/ais"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/helpers"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/middlewares"
+)
+
+type AisHandler struct {
+	ingestor *ais.Ingestor
+	listener *ais.Listener
+}
+
+func NewAisHandler(ingestor *ais.Ingestor, listener *ais.Listener) *AisHandler {
+	return &AisHandler{
+		ingestor: ingestor,
+		listener: listener,
+	}
+}
+
+// IngestNmea godoc
+// @Summary Ingest raw NMEA sentences
+// @Description Decode raw !AIVDM/!AIVDO sentences (request body or multipart "file") into the ais_* collections
+// @Tags ais
+// @Accept plain
+// @Accept mpfd
+// @Produce json
+// @Param file formData file false "Captured NMEA log file"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/ais/nmea [post]
+func (h *AisHandler) IngestNmea(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	user := middlewares.ForContext(ctx)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Tidak diizinkan: Harap login",
+		})
+		return
+	}
+	if !helpers.Contains(user.Roles, "admin") {
+		c.JSON(http.StatusForbidden, gin.H{
+			"status":  "error",
+			"message": "Izin tidak cukup: Diperlukan peran admin",
+		})
+		return
+	}
+
+	var reader io.Reader = c.Request.Body
+	if file, err := c.FormFile("file"); err == nil {
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Failed to open uploaded file",
+				"error":   err.Error(),
+			})
+			return
+		}
+		defer f.Close()
+		reader = f
+	}
+
+	result, err := h.ingestor.IngestReader(ctx, reader)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to ingest NMEA sentences",
+			"error":   err.Error(),
+			"data":    result,
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   result,
+	})
+}
+
+// GetStations godoc
+// @Summary Get AIS receiver stations
+// @Description Per-station connection state, messages/sec, decode errors and last-message time
+// @Tags ais
+// @Produce json
+// @Success 200 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/ais/stations [get]
+func (h *AisHandler) GetStations(c *gin.Context) {
+	if middlewares.ForContext(c.Request.Context()) == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Tidak diizinkan: Harap login",
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   h.listener.Statuses(),
+	})
+}
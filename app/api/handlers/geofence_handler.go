@@ -0,0 +1,182 @@
+package handlers
+
+import (
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khoirulhasin/untirta_api/app/domains/geofances"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/helpers"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/middlewares"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+type GeofenceHandler struct {
+	transfer *geofences.Transfer
+}
+
+func NewGeofenceHandler(transfer *geofences.Transfer) *GeofenceHandler {
+	return &GeofenceHandler{
+		transfer: transfer,
+	}
+}
+
+// ExportGeofences godoc
+// @Summary Export geofences
+// @Description Download geofences as GeoJSON FeatureCollection, KML or KMZ
+// @Tags geofences
+// @Produce octet-stream
+// @Param format query string false "geojson (default), kml or kmz"
+// @Param ids query string false "Comma separated geofence IDs, empty for all"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/geofences/export [get]
+func (h *GeofenceHandler) ExportGeofences(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if middlewares.ForContext(ctx) == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Tidak diizinkan: Harap login",
+		})
+		return
+	}
+
+	format, ok := parseGeofenceFormat(c.DefaultQuery("format", "geojson"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid format, use geojson, kml or kmz",
+		})
+		return
+	}
+
+	var ids []int32
+	if raw := c.Query("ids"); raw != "" {
+		for _, part := range strings.Split(raw, ",") {
+			id, err := strconv.ParseInt(strings.TrimSpace(part), 10, 32)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{
+					"status":  "error",
+					"message": "Invalid ids parameter",
+					"error":   err.Error(),
+				})
+				return
+			}
+			ids = append(ids, int32(id))
+		}
+	}
+
+	file, err := h.transfer.ExportFile(ctx, format, ids)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"status":  "error",
+			"message": "Failed to export geofences",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Disposition", `attachment; filename="`+file.Filename+`"`)
+	c.Data(http.StatusOK, file.ContentType, file.Data)
+}
+
+// ImportGeofences godoc
+// @Summary Import geofences
+// @Description Import a GeoJSON, KML or KMZ file (multipart "file" or request body). dryRun=true only returns the preview; duplicates are matched by name
+// @Tags geofences
+// @Accept mpfd
+// @Accept json
+// @Produce json
+// @Param file formData file false "GeoJSON, KML or KMZ file"
+// @Param format query string false "geojson, kml or kmz; detected from the file when empty"
+// @Param dryRun query bool false "Preview without saving"
+// @Param onDuplicate query string false "skip (default) or overwrite"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 403 {object} map[string]interface{}
+// @Router /api/v1/geofences/import [post]
+func (h *GeofenceHandler) ImportGeofences(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	user := middlewares.ForContext(ctx)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Tidak diizinkan: Harap login",
+		})
+		return
+	}
+	if !helpers.Contains(user.Roles, "admin") {
+		c.JSON(http.StatusForbidden, gin.H{
+			"status":  "error",
+			"message": "Izin tidak cukup: Diperlukan peran admin",
+		})
+		return
+	}
+
+	var format *models.GeofenceFileFormat
+	if raw := c.Query("format"); raw != "" {
+		parsed, ok := parseGeofenceFormat(raw)
+		if !ok {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Invalid format, use geojson, kml or kmz",
+			})
+			return
+		}
+		format = &parsed
+	}
+
+	dryRun, _ := strconv.ParseBool(c.DefaultQuery("dryRun", "false"))
+	onDuplicate := models.GeofenceDuplicateStrategy(strings.ToUpper(c.DefaultQuery("onDuplicate", "skip")))
+	if !onDuplicate.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid onDuplicate, use skip or overwrite",
+		})
+		return
+	}
+
+	var reader io.Reader = c.Request.Body
+	filename := ""
+	if file, err := c.FormFile("file"); err == nil {
+		f, err := file.Open()
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Failed to open uploaded file",
+				"error":   err.Error(),
+			})
+			return
+		}
+		defer f.Close()
+		reader = f
+		filename = file.Filename
+	}
+
+	result, err := h.transfer.Import(ctx, reader, filename, format, dryRun, onDuplicate, user.ID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Failed to import geofences",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": "success",
+		"data":   result,
+	})
+}
+
+func parseGeofenceFormat(raw string) (models.GeofenceFileFormat, bool) {
+	format := models.GeofenceFileFormat(strings.ToUpper(raw))
+	return format, format.IsValid()
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 	"strings"
@@ -8,32 +9,53 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/khoirulhasin/untirta_api/app/domains/markers"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/middlewares"
 	"github.com/khoirulhasin/untirta_api/app/models"
 )
 
 type MarkerHandler struct {
-	markerRepo markers.MarkerRepository
+	markerRepo    markers.MarkerRepository
+	markerLocator *markers.Locator
 }
 
-func NewMarkerHandler(markerRepo markers.MarkerRepository) *MarkerHandler {
+func NewMarkerHandler(markerRepo markers.MarkerRepository, markerLocator *markers.Locator) *MarkerHandler {
 	return &MarkerHandler{
-		markerRepo: markerRepo,
+		markerRepo:    markerRepo,
+		markerLocator: markerLocator,
 	}
 }
 
 // GetAllMarkers godoc
 // @Summary Get all markers
-// @Description Get all markers with optional preloading
+// @Description Get all markers with optional preloading; activeAt keeps only markers active at that time, including RRULE occurrences
 // @Tags markers
 // @Accept json
 // @Produce json
+// @Param activeAt query int false "Epoch seconds"
 // @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
 // @Failure 500 {object} map[string]interface{}
 // @Router /api/v1/markers [get]
 func (h *MarkerHandler) GetAllMarkers(c *gin.Context) {
 	ctx := c.Request.Context()
 
-	markers, err := h.markerRepo.GetAllMarkers(ctx)
+	var activeAt *time.Time
+	if activeAtStr := c.Query("activeAt"); activeAtStr != "" {
+		unix, err := strconv.ParseInt(activeAtStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Invalid activeAt parameter",
+				"error":   err.Error(),
+			})
+			return
+		}
+		at := time.Unix(unix, 0)
+		activeAt = &at
+	}
+
+	markers, err := h.markerRepo.GetAllMarkers(ctx, activeAt)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{
 			"status":  "error",
@@ -155,7 +177,7 @@ func (h *MarkerHandler) CreateMarker(c *gin.Context) {
 
 	createdMarker, err := h.markerRepo.CreateMarker(ctx, &marker)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(markerErrorStatus(err), gin.H{
 			"status":  "error",
 			"message": "Failed to create marker",
 			"error":   err.Error(),
@@ -208,7 +230,7 @@ func (h *MarkerHandler) UpdateMarker(c *gin.Context) {
 
 	updatedMarker, err := h.markerRepo.UpdateMarker(ctx, int32(id), &marker)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(markerErrorStatus(err), gin.H{
 			"status":  "error",
 			"message": "Failed to update marker",
 			"error":   err.Error(),
@@ -259,7 +281,7 @@ func (h *MarkerHandler) UpdateMarkerByUUID(c *gin.Context) {
 
 	updatedMarker, err := h.markerRepo.UpdateMarkerByUUID(ctx, uuid, &marker)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
+		c.JSON(markerErrorStatus(err), gin.H{
 			"status":  "error",
 			"message": "Failed to update marker",
 			"error":   err.Error(),
@@ -443,51 +465,133 @@ func (h *MarkerHandler) GetMarkersWithPagination(c *gin.Context) {
 	c.JSON(http.StatusOK, result)
 }
 
+// GetNearestMarkers godoc
+// @Summary Get nearest active markers
+// @Description Nearest markers from lat/lng, or from the device's last AIS position when only imei is given
+// @Tags markers
+// @Produce json
+// @Param imei query string false "Device IMEI; its last position is used when lat/lng are empty"
+// @Param lat query number false "Origin latitude"
+// @Param lng query number false "Origin longitude"
+// @Param limit query int false "Max markers (default 10, max 50)"
+// @Param markerTypeIds query string false "Comma separated marker type IDs"
+// @Param radiusNm query number false "Search radius in nautical miles"
+// @Param maxAgeMinutes query int false "Max age of the device position (default 1440)"
+// @Param activeAt query int false "Epoch seconds at which markers must be active (default now)"
+// @Success 200 {object} map[string]interface{}
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Router /api/v1/markers/nearest [get]
 func (h *MarkerHandler) GetNearestMarkers(c *gin.Context) {
-	imei := c.Query("imei")
-	if imei == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "IMEI is required"})
+	if middlewares.ForContext(c.Request.Context()) == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Tidak diizinkan: Harap login",
+		})
 		return
 	}
 
-	latStr := c.Query("lat")
-	lngStr := c.Query("lng")
+	query := markers.NearestMarkerQuery{}
 
-	lat, err := strconv.ParseFloat(latStr, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid latitude"})
-		return
+	if imei := c.Query("imei"); imei != "" {
+		query.Imei = &imei
 	}
 
-	lng, err := strconv.ParseFloat(lngStr, 64)
-	if err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid longitude"})
+	if latStr := c.Query("lat"); latStr != "" {
+		lat, err := strconv.ParseFloat(latStr, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid latitude"})
+			return
+		}
+		query.Lat = &lat
+	}
+
+	if lngStr := c.Query("lng"); lngStr != "" {
+		lng, err := strconv.ParseFloat(lngStr, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid longitude"})
+			return
+		}
+		query.Lng = &lng
+	}
+
+	if query.Imei == nil && (query.Lat == nil || query.Lng == nil) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "IMEI or lat/lng is required"})
 		return
 	}
 
 	limitStr := c.DefaultQuery("limit", "10")
 	limit, err := strconv.Atoi(limitStr)
-	if err != nil || limit > 10 {
+	if err != nil {
 		limit = 10
 	}
+	query.Limit = limit
+
+	if typesStr := c.Query("markerTypeIds"); typesStr != "" {
+		for _, part := range strings.Split(typesStr, ",") {
+			id, err := strconv.Atoi(strings.TrimSpace(part))
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid markerTypeIds"})
+				return
+			}
+			query.MarkerTypeIDs = append(query.MarkerTypeIDs, id)
+		}
+	}
+
+	if radiusStr := c.Query("radiusNm"); radiusStr != "" {
+		radius, err := strconv.ParseFloat(radiusStr, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid radiusNm"})
+			return
+		}
+		query.RadiusNm = &radius
+	}
 
-	markers, err := h.markerRepo.GetNearestMarkers(c.Request.Context(), imei, lat, lng, limit)
+	if maxAgeStr := c.Query("maxAgeMinutes"); maxAgeStr != "" {
+		maxAge, err := strconv.Atoi(maxAgeStr)
+		if err != nil || maxAge <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid maxAgeMinutes"})
+			return
+		}
+		query.MaxAge = time.Duration(maxAge) * time.Minute
+	}
+
+	if activeAtStr := c.Query("activeAt"); activeAtStr != "" {
+		unix, err := strconv.ParseInt(activeAtStr, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid activeAt"})
+			return
+		}
+		query.ActiveAt = time.Unix(unix, 0)
+	}
+
+	result, err := h.markerLocator.Nearest(c.Request.Context(), query)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
 
 	// Response dengan informasi tambahan
 	response := gin.H{
-		"data":      markers,
-		"count":     len(markers),
+		"data":      result.Markers,
+		"count":     len(result.Markers),
 		"timestamp": time.Now().Unix(),
 		"coordinates": gin.H{
-			"lat": lat,
-			"lng": lng,
+			"lat": result.Origin.Lat,
+			"lng": result.Origin.Lng,
 		},
-		"imei": imei,
+		"origin": result.Origin,
+		"imei":   result.Imei,
 	}
 
 	c.JSON(http.StatusOK, response)
 }
+
+// markerErrorStatus — kesalahan validasi jadwal (RRULE/timezone) adalah 400
+func markerErrorStatus(err error) int {
+	var validationErrors error_handlers.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/khoirulhasin/untirta_api/app/domains/ships"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/middlewares"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+type TrackHandler struct {
+	exporter *ships.TrackExporter
+}
+
+func NewTrackHandler(exporter *ships.TrackExporter) *TrackHandler {
+	return &TrackHandler{
+		exporter: exporter,
+	}
+}
+
+// ExportTrack godoc
+// @Summary Export vessel track
+// @Description Stream the track of a vessel (by MMSI or device IMEI) for a time range as GPX 1.1, KML gx:Track, GeoJSON or CSV, with speed and course per point
+// @Tags tracks
+// @Produce octet-stream
+// @Param mmsi query int false "Vessel MMSI"
+// @Param imei query string false "Device IMEI, used when mmsi is empty"
+// @Param start query int true "Start time (unix seconds)"
+// @Param end query int true "End time (unix seconds)"
+// @Param format query string false "geojson (default), gpx, kml or csv"
+// @Success 200 {file} file
+// @Failure 400 {object} map[string]interface{}
+// @Failure 401 {object} map[string]interface{}
+// @Failure 500 {object} map[string]interface{}
+// @Router /api/v1/tracks/export [get]
+func (h *TrackHandler) ExportTrack(c *gin.Context) {
+	ctx := c.Request.Context()
+
+	if middlewares.ForContext(ctx) == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{
+			"status":  "error",
+			"message": "Tidak diizinkan: Harap login",
+		})
+		return
+	}
+
+	query := ships.TrackExportQuery{
+		Format: models.TrackFileFormat(strings.ToUpper(c.DefaultQuery("format", "geojson"))),
+	}
+	if raw := c.Query("mmsi"); raw != "" {
+		mmsi, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"status":  "error",
+				"message": "Invalid mmsi parameter",
+				"error":   err.Error(),
+			})
+			return
+		}
+		query.Mmsi = &mmsi
+	}
+	if raw := c.Query("imei"); raw != "" {
+		query.Imei = &raw
+	}
+
+	start, err := strconv.ParseInt(c.Query("start"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid start parameter",
+			"error":   err.Error(),
+		})
+		return
+	}
+	end, err := strconv.ParseInt(c.Query("end"), 10, 64)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid end parameter",
+			"error":   err.Error(),
+		})
+		return
+	}
+	query.Start = time.Unix(start, 0)
+	query.End = time.Unix(end, 0)
+
+	if err := query.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"status":  "error",
+			"message": "Invalid export request",
+			"error":   err.Error(),
+		})
+		return
+	}
+
+	c.Header("Content-Type", query.ContentType())
+	c.Header("Content-Disposition", `attachment; filename="`+query.Filename()+`"`)
+
+	// Exporter belum menulis apa pun sebelum titik pertama, jadi error query
+	// masih bisa dikirim sebagai JSON. Setelah streaming dimulai header sudah
+	// terkirim dan koneksi hanya bisa diputus.
+	count, err := h.exporter.Write(ctx, query, c.Writer)
+	if err != nil {
+		if !c.Writer.Written() {
+			c.Header("Content-Disposition", "")
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"status":  "error",
+				"message": "Failed to export track",
+				"error":   err.Error(),
+			})
+			return
+		}
+		log.Printf("Track export %s terputus setelah %d titik: %v", query.Filename(), count, err)
+		c.Abort()
+	}
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/khoirulhasin/untirta_api/app/api/handlers"
+)
+
+func SetupAisRoutes(api *gin.RouterGroup, aisHandler *handlers.AisHandler) {
+	ais := api.Group("/ais")
+	{
+		ais.POST("/nmea", aisHandler.IngestNmea)
+		ais.GET("/stations", aisHandler.GetStations)
+	}
+}
@@ -0,0 +1,14 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/khoirulhasin/untirta_api/app/api/handlers"
+)
+
+func SetupGeofenceRoutes(api *gin.RouterGroup, geofenceHandler *handlers.GeofenceHandler) {
+	geofences := api.Group("/geofences")
+	{
+		geofences.GET("/export", geofenceHandler.ExportGeofences)
+		geofences.POST("/import", geofenceHandler.ImportGeofences)
+	}
+}
@@ -11,5 +11,14 @@ func SetupAllRoutes(r *gin.Engine, handlers *dependencies.Handlers) {
 		// Setup marker routes
 		SetupMarkerRoutes(api, handlers.MarkerHandler)
 
+		// Setup AIS ingestion routes
+		SetupAisRoutes(api, handlers.AisHandler)
+
+		// Setup geofence import/export routes
+		SetupGeofenceRoutes(api, handlers.GeofenceHandler)
+
+		// Setup vessel track export routes
+		SetupTrackRoutes(api, handlers.TrackHandler)
+
 	}
 }
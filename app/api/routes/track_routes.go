@@ -0,0 +1,13 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/khoirulhasin/untirta_api/app/api/handlers"
+)
+
+func SetupTrackRoutes(api *gin.RouterGroup, trackHandler *handlers.TrackHandler) {
+	tracks := api.Group("/tracks")
+	{
+		tracks.GET("/export", trackHandler.ExportTrack)
+	}
+}
@@ -4,28 +4,39 @@ import (
 	"context"
 	"log"
 	"net/http"
+	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
 	"github.com/99designs/gqlgen/graphql/handler/extension"
 	"github.com/99designs/gqlgen/graphql/handler/lru"
 	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
 	"github.com/khoirulhasin/untirta_api/app/api/handlers"
+	"github.com/khoirulhasin/untirta_api/app/domains/alerts"
+	"github.com/khoirulhasin/untirta_api/app/domains/anomalies"
 	"github.com/khoirulhasin/untirta_api/app/domains/cams"
+	"github.com/khoirulhasin/untirta_api/app/domains/collisions"
 	"github.com/khoirulhasin/untirta_api/app/domains/devices"
 	"github.com/khoirulhasin/untirta_api/app/domains/drivers"
 	"github.com/khoirulhasin/untirta_api/app/domains/drives"
+	"github.com/khoirulhasin/untirta_api/app/domains/gaps"
 	geofences "github.com/khoirulhasin/untirta_api/app/domains/geofances"
+	"github.com/khoirulhasin/untirta_api/app/domains/hazards"
+	"github.com/khoirulhasin/untirta_api/app/domains/incidents"
 	"github.com/khoirulhasin/untirta_api/app/domains/marker_types"
 	"github.com/khoirulhasin/untirta_api/app/domains/markers"
 	"github.com/khoirulhasin/untirta_api/app/domains/menus"
 	"github.com/khoirulhasin/untirta_api/app/domains/menus2roles"
 	"github.com/khoirulhasin/untirta_api/app/domains/profiles"
 	"github.com/khoirulhasin/untirta_api/app/domains/roles"
+	"github.com/khoirulhasin/untirta_api/app/domains/sar"
 	"github.com/khoirulhasin/untirta_api/app/domains/ships"
 	"github.com/khoirulhasin/untirta_api/app/domains/users"
 	"github.com/khoirulhasin/untirta_api/app/domains/users2roles"
+	"github.com/khoirulhasin/untirta_api/app/domains/voyages"
 	"github.com/khoirulhasin/untirta_api/app/generated"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/ais"
 	"github.com/khoirulhasin/untirta_api/app/infrastructures/dbs/mongodb"
 	"github.com/khoirulhasin/untirta_api/app/infrastructures/dbs/mongodis"
 	"github.com/khoirulhasin/untirta_api/app/infrastructures/dbs/postgres"
@@ -49,9 +60,20 @@ func (e *MyError) Error() string {
 // Global variable untuk menyimpan handlers agar bisa diakses dari main.go
 var GlobalHandlers *Handlers
 
+// Worker adalah proses background yang dijalankan dari main.go
+type Worker interface {
+	Start(ctx context.Context)
+}
+
+// Global variable untuk menyimpan background workers
+var GlobalWorkers []Worker
+
 // Struct untuk menyimpan semua REST handlers
 type Handlers struct {
-	MarkerHandler *handlers.MarkerHandler
+	MarkerHandler   *handlers.MarkerHandler
+	AisHandler      *handlers.AisHandler
+	GeofenceHandler *handlers.GeofenceHandler
+	TrackHandler    *handlers.TrackHandler
 	// Tambahkan handler lain sesuai kebutuhan
 }
 
@@ -75,34 +97,144 @@ func Init(r *gin.Engine) http.Handler {
 	camRepository := cams.NewCamRepository(connPostgres)
 	markerTypeRepository := marker_types.NewMarkerTypeRepository(connPostgres)
 	geofenceRepository := geofences.NewGeofenceRepository(connPostgres)
+	geofenceEventRepository := geofences.NewGeofenceEventRepository(connPostgres)
+	geofenceAlertRuleRepository := geofences.NewGeofenceAlertRuleRepository(connPostgres)
 	shipMongodistory := ships.NewShipMongodistory(connMongodis)
 	shipMongotory := ships.NewShipMongotory(connMongo)
+	anomalyMongotory := anomalies.NewAnomalyMongotory(connMongo)
+	markerRedistory := markers.NewMarkerRedistory(connPostgres, connMongodis.Redis)
+	markerLocator := markers.NewLocator(markerRepository, shipMongotory)
+	voyageRepository := voyages.NewVoyageRepository(connPostgres)
+	alertRepository := alerts.NewAlertRepository(connPostgres)
+	incidentRepository := incidents.NewIncidentRepository(connPostgres)
+
+	// Decoder NMEA mentah -> koleksi ais_*
+	aisIngestor := ais.NewIngestor(shipMongotory)
+	trackExporter := ships.NewTrackExporter(shipMongotory)
+	shipAisLinker := ships.NewAisLinker(shipRepository, shipMongotory)
+
+	// Receiver AIS (UDP/TCP) dari env AIS_STATIONS
+	aisStations, err := ais.LoadStationConfigs()
+	if err != nil {
+		log.Printf("AIS stations disabled: %v", err)
+	}
+	aisListener := ais.NewListener(aisIngestor, aisStations)
+	GlobalWorkers = append(GlobalWorkers, aisListener)
+
+	// Event ENTER/EXIT/DWELL geofence dari posisi realtime, sekaligus alert rule per geofence
+	geofenceEngineConfig, err := geofences.LoadEngineConfig()
+	if err != nil {
+		log.Printf("geofence engine uses defaults: %v", err)
+	}
+	geofenceRuleEvaluator := geofences.NewRuleEvaluator(geofenceAlertRuleRepository, alertRepository, deviceRepository, shipRepository)
+	GlobalWorkers = append(GlobalWorkers, geofences.NewEngine(geofenceRepository, geofenceEventRepository, aisIngestor, geofenceRuleEvaluator, geofenceEngineConfig))
+
+	// Query spasial geofence terhadap posisi AIS
+	geofenceLocator := geofences.NewLocator(geofenceRepository, shipMongotory, shipMongodistory)
+	geofenceTransfer := geofences.NewTransfer(geofenceRepository)
+
+	// Segmentasi voyage berkala dari env VOYAGE_DETECT_*
+	voyageDetector := voyages.NewDetector(voyageRepository, shipMongotory, geofenceRepository)
+	voyageWorkerConfig, err := voyages.LoadWorkerConfig()
+	if err != nil {
+		log.Printf("voyage detection worker disabled: %v", err)
+		voyageWorkerConfig = voyages.WorkerConfig{}
+	}
+	GlobalWorkers = append(GlobalWorkers, voyages.NewWorker(voyageDetector, shipMongotory, voyageWorkerConfig))
+
+	// Penanda anomali ais_dynamic berkala dari env AIS_ANOMALY_*
+	anomalyDetector := anomalies.NewDetector(anomalyMongotory, shipMongotory)
+	anomalyWorkerConfig, err := anomalies.LoadWorkerConfig()
+	if err != nil {
+		log.Printf("ais anomaly worker uses defaults: %v", err)
+		anomalyWorkerConfig = anomalies.DefaultWorkerConfig()
+	}
+	GlobalWorkers = append(GlobalWorkers, anomalies.NewWorker(anomalyDetector, anomalyWorkerConfig))
+
+	// Jeda transmisi AIS, sekaligus alert AIS_GAP own-fleet dari env AIS_GAP_*
+	gapMonitorConfig, err := gaps.LoadMonitorConfig()
+	if err != nil {
+		log.Printf("ais gap monitor uses defaults: %v", err)
+		gapMonitorConfig = gaps.DefaultMonitorConfig()
+	}
+	gapDetector := gaps.NewDetector(shipMongotory, gapMonitorConfig.Thresholds)
+	GlobalWorkers = append(GlobalWorkers, gaps.NewMonitor(deviceRepository, shipMongotory, alertRepository, gapMonitorConfig))
+
+	// Evaluator CPA/TCPA own-fleet dari env COLLISION_*
+	collisionAssessor := collisions.NewAssessor(deviceRepository, shipMongotory, shipMongodistory)
+	collisionConfig, err := collisions.LoadEvaluatorConfig()
+	if err != nil {
+		log.Printf("collision evaluator uses defaults: %v", err)
+		collisionConfig = collisions.EvaluatorConfig{Interval: 30 * time.Second, Limits: collisions.DefaultLimits()}
+	}
+	GlobalWorkers = append(GlobalWorkers, collisions.NewEvaluator(collisionAssessor, deviceRepository, alertRepository, collisionConfig))
+
+	// Monitor proximity marker hazard dari env HAZARD_*
+	hazardConfig, err := hazards.LoadMonitorConfig()
+	if err != nil {
+		log.Printf("hazard monitor uses defaults: %v", err)
+		hazardConfig = hazards.DefaultMonitorConfig()
+	}
+	GlobalWorkers = append(GlobalWorkers, hazards.NewMonitor(deviceRepository, markerRepository, markerTypeRepository, shipMongotory, alertRepository, hazardConfig))
+
+	// Insiden MOB/SART/EPIRB dari ais_mob, konfigurasi dari env INCIDENT_*
+	incidentHub := incidents.NewHub()
+	incidentConfig, err := incidents.LoadMonitorConfig()
+	if err != nil {
+		log.Printf("incident monitor uses defaults: %v", err)
+		incidentConfig = incidents.DefaultMonitorConfig()
+	}
+	GlobalWorkers = append(GlobalWorkers, incidents.NewMonitor(incidentRepository, shipMongotory, aisIngestor, incidentHub, incidentConfig))
+	sarAssistant := sar.NewAssistant(incidentRepository, shipMongodistory)
 
 	// Initialize REST API handlers dan simpan ke global variable
 	GlobalHandlers = &Handlers{
-		MarkerHandler: handlers.NewMarkerHandler(markerRepository),
+		MarkerHandler:   handlers.NewMarkerHandler(markerRepository, markerLocator),
+		AisHandler:      handlers.NewAisHandler(aisIngestor, aisListener),
+		GeofenceHandler: handlers.NewGeofenceHandler(geofenceTransfer),
+		TrackHandler:    handlers.NewTrackHandler(trackExporter),
 		// Initialize handler lain
 	}
 
 	// GraphQL Configuration (sama seperti sebelumnya)
 	c := generated.Config{
 		Resolvers: &interfaces.Resolver{
-			ProfileRepository:    profileRepository,
-			RoleRepository:       roleRepository,
-			UserRepository:       userRepository,
-			Users2roleRepository: users2roleRepository,
-			MenuRepository:       menuRepository,
-			Menus2roleRepository: menus2roleRepository,
-			DeviceRepository:     deviceRepository,
-			MarkerRepository:     markerRepository,
-			ShipRepository:       shipRepository,
-			DriverRepository:     driverRepository,
-			DriveRepository:      driveRepository,
-			CamRepository:        camRepository,
-			MarkerTypeRepository: markerTypeRepository,
-			GeofenceRepository:   geofenceRepository,
-			ShipMongodistory:     shipMongodistory,
-			ShipMongotory:        shipMongotory,
+			ProfileRepository:           profileRepository,
+			RoleRepository:              roleRepository,
+			UserRepository:              userRepository,
+			Users2roleRepository:        users2roleRepository,
+			MenuRepository:              menuRepository,
+			Menus2roleRepository:        menus2roleRepository,
+			DeviceRepository:            deviceRepository,
+			MarkerRepository:            markerRepository,
+			MarkerRedistory:             markerRedistory,
+			MarkerLocator:               markerLocator,
+			ShipRepository:              shipRepository,
+			DriverRepository:            driverRepository,
+			DriveRepository:             driveRepository,
+			CamRepository:               camRepository,
+			MarkerTypeRepository:        markerTypeRepository,
+			GeofenceRepository:          geofenceRepository,
+			GeofenceEventRepository:     geofenceEventRepository,
+			GeofenceAlertRuleRepository: geofenceAlertRuleRepository,
+			GeofenceLocator:             geofenceLocator,
+			GeofenceTransfer:            geofenceTransfer,
+			VoyageRepository:            voyageRepository,
+			VoyageDetector:              voyageDetector,
+			AlertRepository:             alertRepository,
+			IncidentRepository:          incidentRepository,
+			IncidentHub:                 incidentHub,
+			SarAssistant:                sarAssistant,
+			AnomalyMongotory:            anomalyMongotory,
+			AnomalyDetector:             anomalyDetector,
+			GapDetector:                 gapDetector,
+			CollisionAssessor:           collisionAssessor,
+			ShipMongodistory:            shipMongodistory,
+			ShipMongotory:               shipMongotory,
+			TrackExporter:               trackExporter,
+			ShipAisLinker:               shipAisLinker,
+			AisIngestor:                 aisIngestor,
+			AisListener:                 aisListener,
 		},
 	}
 
@@ -110,7 +242,7 @@ func Init(r *gin.Engine) http.Handler {
 	c.Directives.HasRole = directives.HasRoleDirective
 	c.Directives.Validate = directives.ValidateDirective
 
-	h := handler.NewDefaultServer(generated.NewExecutableSchema(c))
+	h := handler.New(generated.NewExecutableSchema(c))
 
 	h.SetRecoverFunc(func(ctx context.Context, err interface{}) error {
 		log.Printf("🔥 Panic caught in resolver: %v", err)
@@ -118,8 +250,19 @@ func Init(r *gin.Engine) http.Handler {
 	})
 
 	h.AddTransport(transport.Options{})
+	// Websocket untuk subscription (graphql-ws & graphql-transport-ws)
+	h.AddTransport(transport.Websocket{
+		KeepAlivePingInterval: 10 * time.Second,
+		Upgrader: websocket.Upgrader{
+			CheckOrigin: func(r *http.Request) bool {
+				return true
+			},
+		},
+		InitFunc: middlewares.WebsocketInitFunc(connPostgres),
+	})
 	h.AddTransport(transport.GET{})
 	h.AddTransport(transport.POST{})
+	h.AddTransport(transport.MultipartForm{})
 
 	h.SetQueryCache(lru.New[*ast.QueryDocument](1000))
 
@@ -146,3 +289,8 @@ func Init(r *gin.Engine) http.Handler {
 func GetHandlers() *Handlers {
 	return GlobalHandlers
 }
+
+// Function untuk mendapatkan background workers dari luar package
+func GetWorkers() []Worker {
+	return GlobalWorkers
+}
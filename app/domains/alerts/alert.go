@@ -0,0 +1,23 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+type AlertRepository interface {
+	GetAlertByID(ctx context.Context, id int32) (*models.Alert, error)
+	PageAlert(ctx context.Context, pagination models.Pagination) (models.Pagination, error)
+	// RaiseAlert membuat alert OPEN baru, atau memperbarui alert aktif (OPEN/ACKNOWLEDGED) dengan dedupKey yang sama
+	RaiseAlert(ctx context.Context, alert *models.Alert) (*models.Alert, error)
+	// ResolveStaleAlerts menutup alert aktif jenis kind yang dedupKey-nya tidak ada di activeKeys
+	ResolveStaleAlerts(ctx context.Context, kind models.AlertKind, activeKeys []string) (int64, error)
+	// GetActiveDedupKeys mengembalikan dedupKey alert aktif jenis kind milik kapal shipIDs
+	// atau perangkat imeis
+	GetActiveDedupKeys(ctx context.Context, kind models.AlertKind, shipIDs []int, imeis []string) ([]string, error)
+	// AcknowledgeAlert menandai alert OPEN sudah ditangani oleh userID
+	AcknowledgeAlert(ctx context.Context, id int32, userID int) (*models.Alert, error)
+	// ResolveAlert menutup alert OPEN/ACKNOWLEDGED secara manual
+	ResolveAlert(ctx context.Context, id int32, userID int) (*models.Alert, error)
+}
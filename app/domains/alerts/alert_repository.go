@@ -0,0 +1,190 @@
+package alerts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/pkg"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"gorm.io/gorm"
+)
+
+// alert yang belum RESOLVED masih dianggap aktif untuk dedupKey-nya
+var activeStatuses = []models.AlertStatus{models.AlertStatusOpen, models.AlertStatusAcknowledged}
+
+type alertRepository struct {
+	db *gorm.DB
+}
+
+func NewAlertRepository(db *gorm.DB) *alertRepository {
+	return &alertRepository{
+		db,
+	}
+}
+
+var _ AlertRepository = &alertRepository{}
+
+func (r *alertRepository) GetAlertByID(ctx context.Context, id int32) (*models.Alert, error) {
+
+	var alert = &models.Alert{}
+
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&alert).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+func (r *alertRepository) PageAlert(ctx context.Context, pagination models.Pagination) (models.Pagination, error) {
+	var alerts []models.Alert
+
+	var err = r.db.WithContext(ctx).
+		Scopes(pkg.Paginate(&models.Alert{}, &pagination, r.db)).
+		Find(&alerts).Error
+	pagination.Rows = make([]any, len(alerts))
+	for i, alert := range alerts {
+		pagination.Rows[i] = alert
+	}
+
+	if err != nil {
+		return pagination, err
+	}
+
+	return pagination, nil
+
+}
+
+func (r *alertRepository) RaiseAlert(ctx context.Context, alert *models.Alert) (*models.Alert, error) {
+	now := time.Now().UnixMilli()
+	if alert.LastSeenAt == 0 {
+		alert.LastSeenAt = now
+	}
+
+	var existing = &models.Alert{}
+	err := r.db.WithContext(ctx).
+		Where("dedup_key = ? AND status IN ?", alert.DedupKey, activeStatuses).
+		Take(&existing).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		alert.Status = models.AlertStatusOpen
+		if alert.FirstSeenAt == 0 {
+			alert.FirstSeenAt = alert.LastSeenAt
+		}
+		if err := r.db.WithContext(ctx).Create(&alert).Error; err != nil {
+			return nil, err
+		}
+		log.Printf("alert %s raised: %s", alert.Kind, alert.Message)
+		return alert, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	err = r.db.WithContext(ctx).Model(&existing).Updates(map[string]any{
+		"severity":            alert.Severity,
+		"message":             alert.Message,
+		"latitude":            alert.Latitude,
+		"longitude":           alert.Longitude,
+		"cpa_nm":              alert.CpaNm,
+		"tcpa_minutes":        alert.TcpaMinutes,
+		"closest_approach_at": alert.ClosestApproachAt,
+		"last_seen_at":        alert.LastSeenAt,
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+func (r *alertRepository) ResolveStaleAlerts(ctx context.Context, kind models.AlertKind, activeKeys []string) (int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&models.Alert{}).
+		Where("kind = ? AND status IN ?", kind, activeStatuses)
+	if len(activeKeys) > 0 {
+		query = query.Where("dedup_key NOT IN ?", activeKeys)
+	}
+
+	result := query.Updates(map[string]any{
+		"status":      models.AlertStatusResolved,
+		"resolved_at": time.Now().UnixMilli(),
+	})
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	return result.RowsAffected, nil
+}
+
+func (r *alertRepository) GetActiveDedupKeys(ctx context.Context, kind models.AlertKind, shipIDs []int, imeis []string) ([]string, error) {
+	keys := []string{}
+	if len(shipIDs) == 0 && len(imeis) == 0 {
+		return keys, nil
+	}
+
+	// IN () kosong tidak valid di Postgres; -1 dan "" tidak pernah cocok
+	if len(shipIDs) == 0 {
+		shipIDs = []int{-1}
+	}
+	if len(imeis) == 0 {
+		imeis = []string{""}
+	}
+
+	err := r.db.WithContext(ctx).
+		Model(&models.Alert{}).
+		Where("kind = ? AND status IN ?", kind, activeStatuses).
+		Where("(ship_id IN ? OR (ship_id IS NULL AND device_imei IN ?))", shipIDs, imeis).
+		Pluck("dedup_key", &keys).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return keys, nil
+}
+
+func (r *alertRepository) AcknowledgeAlert(ctx context.Context, id int32, userID int) (*models.Alert, error) {
+	alert, err := r.GetAlertByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if alert.Status != models.AlertStatusOpen {
+		return nil, fmt.Errorf("alert berstatus %s tidak dapat di-acknowledge", alert.Status)
+	}
+
+	now := time.Now().UnixMilli()
+	err = r.db.WithContext(ctx).Model(&alert).Updates(map[string]any{
+		"status":          models.AlertStatusAcknowledged,
+		"acknowledged_at": now,
+		"acknowledged_by": userID,
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return alert, nil
+}
+
+func (r *alertRepository) ResolveAlert(ctx context.Context, id int32, userID int) (*models.Alert, error) {
+	alert, err := r.GetAlertByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if alert.Status == models.AlertStatusResolved {
+		return nil, errors.New("alert sudah RESOLVED")
+	}
+
+	now := time.Now().UnixMilli()
+	err = r.db.WithContext(ctx).Model(&alert).Updates(map[string]any{
+		"status":      models.AlertStatusResolved,
+		"resolved_at": now,
+		"resolved_by": userID,
+	}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return alert, nil
+}
@@ -0,0 +1,75 @@
+package alerts
+
+import (
+	"context"
+
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+// Sweep mengumpulkan dedupKey alert yang masih terjadi selama satu putaran
+// evaluator background. Kapal yang gagal dinilai dicatat dengan Fail (atau
+// FailDevice untuk perangkat tanpa kapal): alert aktifnya dibiarkan terbuka,
+// sementara alert kapal lain tetap ditutup oleh Resolve.
+type Sweep struct {
+	kind          models.AlertKind
+	activeKeys    []string
+	failedShips   map[int]bool
+	failedDevices map[string]bool
+}
+
+func NewSweep(kind models.AlertKind) *Sweep {
+	return &Sweep{
+		kind:          kind,
+		activeKeys:    []string{},
+		failedShips:   make(map[int]bool),
+		failedDevices: make(map[string]bool),
+	}
+}
+
+// Keep menandai alert dedupKey masih terjadi
+func (s *Sweep) Keep(dedupKey string) {
+	s.activeKeys = append(s.activeKeys, dedupKey)
+}
+
+// Fail menandai kapal shipID gagal dinilai pada putaran ini
+func (s *Sweep) Fail(shipID int) {
+	s.failedShips[shipID] = true
+}
+
+// Failed memeriksa apakah kapal shipID sudah ditandai gagal
+func (s *Sweep) Failed(shipID int) bool {
+	return s.failedShips[shipID]
+}
+
+// FailDevice menandai perangkat imei (yang tidak terpasang di kapal) gagal dinilai
+func (s *Sweep) FailDevice(imei string) {
+	s.failedDevices[imei] = true
+}
+
+// FailedDevice memeriksa apakah perangkat imei sudah ditandai gagal
+func (s *Sweep) FailedDevice(imei string) bool {
+	return s.failedDevices[imei]
+}
+
+// Resolve menutup alert aktif jenis kind yang tidak ditandai Keep, kecuali
+// alert milik kapal atau perangkat yang gagal dinilai
+func (s *Sweep) Resolve(ctx context.Context, alertRepository AlertRepository) (int64, error) {
+	activeKeys := s.activeKeys
+	if len(s.failedShips) > 0 || len(s.failedDevices) > 0 {
+		shipIDs := make([]int, 0, len(s.failedShips))
+		for shipID := range s.failedShips {
+			shipIDs = append(shipIDs, shipID)
+		}
+		imeis := make([]string, 0, len(s.failedDevices))
+		for imei := range s.failedDevices {
+			imeis = append(imeis, imei)
+		}
+		keys, err := alertRepository.GetActiveDedupKeys(ctx, s.kind, shipIDs, imeis)
+		if err != nil {
+			return 0, err
+		}
+		activeKeys = append(activeKeys, keys...)
+	}
+
+	return alertRepository.ResolveStaleAlerts(ctx, s.kind, activeKeys)
+}
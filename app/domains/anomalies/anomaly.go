@@ -0,0 +1,55 @@
+package anomalies
+
+import (
+	"context"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+type AnomalyMongotory interface {
+	// SaveAnomalies menyimpan anomali ke ais_anomaly (idempoten per titik dan
+	// jenis) lalu menandai dokumen ais_dynamic-nya. Mengembalikan jumlah anomali baru.
+	SaveAnomalies(ctx context.Context, anomalies []*AisAnomaly) (int, error)
+	// ClearAnomalies menghapus anomali jenis kinds milik mmsi dengan ts dalam
+	// [start, end] kecuali keepIDs, beserta tandanya di ais_dynamic. Mengembalikan
+	// jumlah anomali yang dihapus.
+	ClearAnomalies(ctx context.Context, mmsi int64, kinds []models.AisAnomalyKind, start, end time.Time, keepIDs []string) (int, error)
+	// GetAnomalies mengembalikan anomali dalam rentang waktu, terbaru dulu
+	GetAnomalies(ctx context.Context, query AnomalyQuery) ([]*AisAnomaly, error)
+}
+
+// AnomalyQuery memilih anomali per vessel (opsional) dan jenis dalam rentang waktu
+type AnomalyQuery struct {
+	Mmsi  *int64
+	Kinds []models.AisAnomalyKind
+	Start time.Time
+	End   time.Time
+	Limit int
+}
+
+// AisAnomaly — satu titik ais_dynamic yang ditandai. Ref* adalah titik
+// pembanding pada track utama untuk POSITION_JUMP dan DUPLICATE_MMSI.
+type AisAnomaly struct {
+	ID                string                `json:"id"`
+	PositionID        string                `json:"positionId"`
+	Kind              models.AisAnomalyKind `json:"kind"`
+	Mmsi              int64                 `json:"mmsi"`
+	Ts                int64                 `json:"ts"`
+	TsIso             string                `json:"tsIso"`
+	Latitude          float64               `json:"latitude"`
+	Longitude         float64               `json:"longitude"`
+	Sog               *float64              `json:"sog,omitempty"`
+	RefTs             *int64                `json:"refTs,omitempty"`
+	RefLatitude       *float64              `json:"refLatitude,omitempty"`
+	RefLongitude      *float64              `json:"refLongitude,omitempty"`
+	DistanceNm        *float64              `json:"distanceNm,omitempty"`
+	ImpliedSpeedKnots *float64              `json:"impliedSpeedKnots,omitempty"`
+	Detail            string                `json:"detail"`
+	DetectedAt        int64                 `json:"detectedAt"`
+}
+
+// AnomalyID — satu anomali per titik ais_dynamic dan jenis
+func AnomalyID(positionID string, kind models.AisAnomalyKind) string {
+	return positionID + ":" + string(kind)
+}
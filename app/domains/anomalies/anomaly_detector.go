@@ -0,0 +1,310 @@
+package anomalies
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"sort"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/domains/ships"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/ais"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+const (
+	defaultMaxSpeedKnots     = 60.0
+	defaultMinJumpNm         = 1.0
+	defaultMinDuplicateFixes = 3
+	// jumlah track yang masih dibandingkan dengan titik berikutnya
+	maxActiveTracks = 8
+	// titik dengan timestamp (hampir) sama dari beberapa receiver
+	minLegDuration = time.Second
+)
+
+// kinematicKinds bergantung pada titik lain di window, jadi hasil lama di
+// window yang dianalisis ulang diganti hasil baru
+var kinematicKinds = []models.AisAnomalyKind{
+	models.AisAnomalyKindPositionJump,
+	models.AisAnomalyKindDuplicateMmsi,
+}
+
+// DetectionOptions adalah batas kewajaran pergerakan satu MMSI
+type DetectionOptions struct {
+	// kecepatan tersirat (knot) di atas ini tidak wajar
+	MaxSpeedKnots float64
+	// perpindahan lebih pendek dari ini tidak pernah dianggap lompatan (noise GPS)
+	MinJumpNm float64
+	// titik minimal track kedua yang berjalan bersamaan agar dianggap MMSI ganda
+	MinDuplicateFixes int
+	// titik setelah waktu ini hanya menjadi konteks dan tidak ditandai (zero = semua)
+	FlagUntil time.Time
+}
+
+func DefaultDetectionOptions() DetectionOptions {
+	return DetectionOptions{
+		MaxSpeedKnots:     defaultMaxSpeedKnots,
+		MinJumpNm:         defaultMinJumpNm,
+		MinDuplicateFixes: defaultMinDuplicateFixes,
+	}
+}
+
+// NewDetectionOptions mengisi nilai default untuk field input yang kosong
+func NewDetectionOptions(input *models.AisAnomalyDetectionInput) DetectionOptions {
+	opts := DefaultDetectionOptions()
+	if input == nil {
+		return opts
+	}
+	if input.MaxSpeedKnots != nil {
+		opts.MaxSpeedKnots = *input.MaxSpeedKnots
+	}
+	if input.MinJumpNm != nil {
+		opts.MinJumpNm = *input.MinJumpNm
+	}
+	if input.MinDuplicateFixes != nil {
+		opts.MinDuplicateFixes = *input.MinDuplicateFixes
+	}
+	return opts
+}
+
+// Validate mengembalikan error_handlers.ValidationErrors untuk batas yang tidak masuk akal
+func (o DetectionOptions) Validate() error {
+	var errs error_handlers.ValidationErrors
+	if o.MaxSpeedKnots <= 0 {
+		errs.Add("maxSpeedKnots", "harus lebih dari 0")
+	}
+	if o.MinJumpNm < 0 {
+		errs.Add("minJumpNm", "tidak boleh negatif")
+	}
+	if o.MinDuplicateFixes < 2 {
+		errs.Add("minDuplicateFixes", "minimal 2")
+	}
+	return errs.Err()
+}
+
+// Detector membaca history ais_dynamic per MMSI, menjalankan Analyze dan menyimpan hasilnya
+type Detector struct {
+	anomalyMongotory AnomalyMongotory
+	shipMongotory    ships.ShipMongotory
+}
+
+func NewDetector(anomalyMongotory AnomalyMongotory, shipMongotory ships.ShipMongotory) *Detector {
+	return &Detector{
+		anomalyMongotory: anomalyMongotory,
+		shipMongotory:    shipMongotory,
+	}
+}
+
+// Detect menganalisis rentang [start, end]; mmsiList kosong berarti semua MMSI
+// yang mengirim posisi dalam rentang tersebut. POSITION_JUMP/DUPLICATE_MMSI
+// lama di rentang itu yang tidak terdeteksi lagi dihapus.
+func (d *Detector) Detect(ctx context.Context, mmsiList []int64, start, end time.Time, opts DetectionOptions) (*models.AisAnomalyScan, error) {
+	if len(mmsiList) == 0 {
+		var err error
+		mmsiList, err = d.shipMongotory.GetMmsiByDatetime(models.DurationTimeInput{Start: start.Unix(), End: end.Unix()})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	scan := &models.AisAnomalyScan{VesselCount: len(mmsiList)}
+	detectedAt := time.Now()
+	flagEnd := end
+	if !opts.FlagUntil.IsZero() && opts.FlagUntil.Before(end) {
+		flagEnd = opts.FlagUntil
+	}
+	for _, mmsi := range mmsiList {
+		var fixes []*ships.AisFix
+		err := d.shipMongotory.StreamFixes(ctx, mmsi, start, end, func(fix *ships.AisFix) error {
+			fixes = append(fixes, fix)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		anomalies := Analyze(mmsi, fixes, opts, detectedAt)
+		keepIDs := []string{}
+		for _, anomaly := range anomalies {
+			if slices.Contains(kinematicKinds, anomaly.Kind) {
+				keepIDs = append(keepIDs, anomaly.ID)
+			}
+		}
+		if _, err := d.anomalyMongotory.ClearAnomalies(ctx, mmsi, kinematicKinds, start, flagEnd, keepIDs); err != nil {
+			return nil, err
+		}
+		saved, err := d.anomalyMongotory.SaveAnomalies(ctx, anomalies)
+		if err != nil {
+			return nil, err
+		}
+		scan.PositionCount += len(fixes)
+		scan.AnomalyCount += len(anomalies)
+		scan.NewAnomalyCount += saved
+	}
+
+	return scan, nil
+}
+
+// track adalah rangkaian titik satu MMSI yang saling wajar secara kinematis (urut waktu naik)
+type track struct {
+	fixes []*ships.AisFix
+}
+
+func (t *track) first() *ships.AisFix { return t.fixes[0] }
+
+func (t *track) last() *ships.AisFix { return t.fixes[len(t.fixes)-1] }
+
+func (t *track) overlaps(other *track) bool {
+	return !t.first().Ts.After(other.last().Ts) && !t.last().Ts.Before(other.first().Ts)
+}
+
+// nearest mengembalikan titik track yang waktunya paling dekat dengan fix
+func (t *track) nearest(fix *ships.AisFix) *ships.AisFix {
+	i := sort.Search(len(t.fixes), func(i int) bool { return !t.fixes[i].Ts.Before(fix.Ts) })
+	switch {
+	case i == len(t.fixes):
+		return t.last()
+	case i > 0 && fix.Ts.Sub(t.fixes[i-1].Ts) < t.fixes[i].Ts.Sub(fix.Ts):
+		return t.fixes[i-1]
+	}
+	return t.fixes[i]
+}
+
+// leg adalah perpindahan antara dua titik
+type leg struct {
+	distanceNm float64
+	speedKnots float64
+}
+
+func newLeg(from, to *ships.AisFix) leg {
+	distanceNm := geo.DistanceMeters(from.Point(), to.Point()) / geo.MetersPerNauticalMile
+	hours := math.Max(math.Abs(to.Ts.Sub(from.Ts).Hours()), minLegDuration.Hours())
+	return leg{distanceNm: distanceNm, speedKnots: distanceNm / hours}
+}
+
+func (o DetectionOptions) plausible(l leg) bool {
+	return l.distanceNm <= o.MinJumpNm || l.speedKnots <= o.MaxSpeedKnots
+}
+
+// Analyze menandai titik satu MMSI (urut waktu naik):
+//
+//   - INVALID_MMSI pada semua titik bila MMSI tidak valid (lihat ais.ValidShipMmsi)
+//   - POSITION_NOT_AVAILABLE dan SOG_NOT_AVAILABLE untuk nilai "not available"
+//   - titik lain dikelompokkan ke track yang kecepatan tersiratnya wajar; track
+//     dengan titik terbanyak adalah track utama. Track lain yang berjalan bersamaan
+//     dengan track utama dan berisi minimal MinDuplicateFixes titik menjadi
+//     DUPLICATE_MMSI, selebihnya POSITION_JUMP.
+//
+// Pesawat SAR (111MID) tidak diperiksa kecepatannya.
+func Analyze(mmsi int64, fixes []*ships.AisFix, opts DetectionOptions, detectedAt time.Time) []*AisAnomaly {
+	var anomalies []*AisAnomaly
+	flag := func(fix *ships.AisFix, kind models.AisAnomalyKind, detail string) *AisAnomaly {
+		if !opts.FlagUntil.IsZero() && fix.Ts.After(opts.FlagUntil) {
+			return nil
+		}
+		anomaly := &AisAnomaly{
+			ID:         AnomalyID(fix.ID.Hex(), kind),
+			PositionID: fix.ID.Hex(),
+			Kind:       kind,
+			Mmsi:       mmsi,
+			Ts:         fix.Ts.UnixMilli(),
+			TsIso:      fix.Ts.UTC().Format(time.RFC3339Nano),
+			Latitude:   fix.Latitude,
+			Longitude:  fix.Longitude,
+			Sog:        fix.Sog,
+			Detail:     detail,
+			DetectedAt: detectedAt.UnixMilli(),
+		}
+		anomalies = append(anomalies, anomaly)
+		return anomaly
+	}
+
+	validMmsi := ais.ValidShipMmsi(mmsi)
+	kinematic := mmsi/1000000 != 111
+
+	var tracks, active []*track
+	for _, fix := range fixes {
+		if !validMmsi {
+			flag(fix, models.AisAnomalyKindInvalidMmsi, fmt.Sprintf("MMSI %d tidak valid untuk laporan posisi kapal", mmsi))
+		}
+		if fix.SogNotAvailable() {
+			flag(fix, models.AisAnomalyKindSogNotAvailable, "SOG 102.3 (not available)")
+		}
+		if !fix.PositionAvailable() {
+			flag(fix, models.AisAnomalyKindPositionNotAvailable, fmt.Sprintf("posisi %.4f, %.4f tidak tersedia", fix.Latitude, fix.Longitude))
+			continue
+		}
+		if !kinematic {
+			continue
+		}
+
+		var best *track
+		var bestLeg leg
+		for _, t := range active {
+			l := newLeg(t.last(), fix)
+			if opts.plausible(l) && (best == nil || l.distanceNm < bestLeg.distanceNm) {
+				best, bestLeg = t, l
+			}
+		}
+		if best != nil {
+			best.fixes = append(best.fixes, fix)
+			continue
+		}
+
+		tracks = append(tracks, &track{fixes: []*ships.AisFix{fix}})
+		active = append(active, tracks[len(tracks)-1])
+		if len(active) > maxActiveTracks {
+			// track yang paling lama tidak melapor tidak lagi dibandingkan
+			oldest := 0
+			for i, t := range active {
+				if t.last().Ts.Before(active[oldest].last().Ts) {
+					oldest = i
+				}
+			}
+			active = append(active[:oldest], active[oldest+1:]...)
+		}
+	}
+	if len(tracks) < 2 {
+		return anomalies
+	}
+
+	main := tracks[0]
+	for _, t := range tracks[1:] {
+		if len(t.fixes) > len(main.fixes) {
+			main = t
+		}
+	}
+	for _, t := range tracks {
+		if t == main {
+			continue
+		}
+		duplicate := len(t.fixes) >= opts.MinDuplicateFixes && t.overlaps(main)
+		for _, fix := range t.fixes {
+			ref := main.nearest(fix)
+			l := newLeg(ref, fix)
+
+			kind := models.AisAnomalyKindPositionJump
+			detail := fmt.Sprintf("lompatan %.1f NM dari track utama (%.0f knot)", l.distanceNm, l.speedKnots)
+			if duplicate {
+				kind = models.AisAnomalyKindDuplicateMmsi
+				detail = fmt.Sprintf("MMSI yang sama melapor dari posisi lain berjarak %.1f NM", l.distanceNm)
+			}
+			anomaly := flag(fix, kind, detail)
+			if anomaly == nil {
+				continue
+			}
+			refTs := ref.Ts.UnixMilli()
+			refLatitude, refLongitude := ref.Latitude, ref.Longitude
+			anomaly.RefTs = &refTs
+			anomaly.RefLatitude = &refLatitude
+			anomaly.RefLongitude = &refLongitude
+			anomaly.DistanceNm = &l.distanceNm
+			anomaly.ImpliedSpeedKnots = &l.speedKnots
+		}
+	}
+
+	return anomalies
+}
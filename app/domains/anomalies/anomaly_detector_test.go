@@ -0,0 +1,213 @@
+package anomalies
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/domains/ships"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var analyzeStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// newFix membuat titik pada menit ke-minutes, latNm/lonNm mil laut dari titik
+// acuan dekat ekuator (1 NM ≈ 1/60 derajat); 0,0 sendiri dianggap tidak tersedia
+func newFix(minutes float64, latNm, lonNm float64) *ships.AisFix {
+	sog := 10.0
+	return &ships.AisFix{
+		ID:        primitive.NewObjectID(),
+		Latitude:  (latNm + 1) / 60,
+		Longitude: (lonNm + 1) / 60,
+		Sog:       &sog,
+		Ts:        analyzeStart.Add(time.Duration(minutes * float64(time.Minute))),
+	}
+}
+
+// steaming — count titik tiap menit dengan kecepatan 10 knot ke timur
+func steaming(count int, latNm float64) []*ships.AisFix {
+	fixes := make([]*ships.AisFix, count)
+	for i := range fixes {
+		fixes[i] = newFix(float64(i), latNm, float64(i)*10/60)
+	}
+	return fixes
+}
+
+type flagged struct {
+	index int
+	kind  models.AisAnomalyKind
+}
+
+func TestAnalyze(t *testing.T) {
+	withJump := steaming(5, 0)
+	withJump[2] = newFix(2, 30, 2*10.0/60)
+
+	// track kedua 50 NM di utara melapor di sela track utama
+	var withDuplicate []*ships.AisFix
+	for i, fix := range steaming(10, 0) {
+		withDuplicate = append(withDuplicate, fix)
+		if i == 0 || i == 2 || i == 4 {
+			withDuplicate = append(withDuplicate, newFix(float64(i)+0.5, 50, float64(i)*10/60))
+		}
+	}
+
+	notAvailable := steaming(3, 0)
+	notAvailable[1].Latitude, notAvailable[1].Longitude = 91, 181
+	sogNotAvailable := 102.3
+	notAvailable[1].Sog = &sogNotAvailable
+
+	// dua laporan berjarak 0,5 NM dalam 1 detik
+	jitter := []*ships.AisFix{newFix(0, 0, 0), newFix(1.0/60, 0.5, 0)}
+
+	// pesawat SAR bolak-balik 5 NM tiap menit
+	aircraft := []*ships.AisFix{newFix(0, 0, 0), newFix(1, 0, 5), newFix(2, 0, 0), newFix(3, 0, 5)}
+
+	defaults := DefaultDetectionOptions()
+	flagUntil := defaults
+	flagUntil.FlagUntil = analyzeStart.Add(time.Minute)
+	strictJump := defaults
+	strictJump.MinJumpNm = 0.1
+
+	tests := []struct {
+		name  string
+		mmsi  int64
+		fixes []*ships.AisFix
+		opts  DetectionOptions
+		want  []flagged
+	}{
+		{
+			name:  "clean track",
+			mmsi:  525005123,
+			fixes: steaming(10, 0),
+			opts:  defaults,
+		},
+		{
+			name:  "no fixes",
+			mmsi:  525005123,
+			fixes: nil,
+			opts:  defaults,
+		},
+		{
+			name:  "single position jump",
+			mmsi:  525005123,
+			fixes: withJump,
+			opts:  defaults,
+			want:  []flagged{{2, models.AisAnomalyKindPositionJump}},
+		},
+		{
+			name:  "jump after FlagUntil is only context",
+			mmsi:  525005123,
+			fixes: withJump,
+			opts:  flagUntil,
+		},
+		{
+			name:  "duplicate MMSI",
+			mmsi:  525005123,
+			fixes: withDuplicate,
+			opts:  defaults,
+			want: []flagged{
+				{1, models.AisAnomalyKindDuplicateMmsi},
+				{4, models.AisAnomalyKindDuplicateMmsi},
+				{7, models.AisAnomalyKindDuplicateMmsi},
+			},
+		},
+		{
+			name:  "invalid MMSI flags every fix",
+			mmsi:  123456789,
+			fixes: steaming(3, 0),
+			opts:  defaults,
+			want: []flagged{
+				{0, models.AisAnomalyKindInvalidMmsi},
+				{1, models.AisAnomalyKindInvalidMmsi},
+				{2, models.AisAnomalyKindInvalidMmsi},
+			},
+		},
+		{
+			name:  "position and SOG not available",
+			mmsi:  525005123,
+			fixes: notAvailable,
+			opts:  defaults,
+			want: []flagged{
+				{1, models.AisAnomalyKindSogNotAvailable},
+				{1, models.AisAnomalyKindPositionNotAvailable},
+			},
+		},
+		{
+			name:  "jitter shorter than MinJumpNm",
+			mmsi:  525005123,
+			fixes: jitter,
+			opts:  defaults,
+		},
+		{
+			name:  "jitter longer than MinJumpNm",
+			mmsi:  525005123,
+			fixes: jitter,
+			opts:  strictJump,
+			want:  []flagged{{1, models.AisAnomalyKindPositionJump}},
+		},
+		{
+			name:  "SAR aircraft speed is not checked",
+			mmsi:  111525001,
+			fixes: aircraft,
+			opts:  defaults,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			detectedAt := analyzeStart.Add(time.Hour)
+			anomalies := Analyze(tt.mmsi, tt.fixes, tt.opts, detectedAt)
+
+			want := make(map[string]bool)
+			for _, f := range tt.want {
+				want[AnomalyID(tt.fixes[f.index].ID.Hex(), f.kind)] = true
+			}
+			if len(anomalies) != len(want) {
+				t.Fatalf("Analyze() returned %d anomalies, want %d: %v", len(anomalies), len(want), kinds(anomalies))
+			}
+			for _, anomaly := range anomalies {
+				if !want[anomaly.ID] {
+					t.Errorf("unexpected anomaly %s", anomaly.ID)
+				}
+				if anomaly.Mmsi != tt.mmsi || anomaly.DetectedAt != detectedAt.UnixMilli() {
+					t.Errorf("anomaly %s mmsi/detectedAt = %d/%d", anomaly.ID, anomaly.Mmsi, anomaly.DetectedAt)
+				}
+			}
+		})
+	}
+}
+
+func TestAnalyzePositionJumpReference(t *testing.T) {
+	fixes := steaming(5, 0)
+	fixes[2] = newFix(2, 30, 2*10.0/60)
+
+	anomalies := Analyze(525005123, fixes, DefaultDetectionOptions(), analyzeStart)
+	if len(anomalies) != 1 {
+		t.Fatalf("Analyze() returned %d anomalies, want 1", len(anomalies))
+	}
+	anomaly := anomalies[0]
+
+	// titik track utama terdekat: menit 1 dan 3 sama jauh, yang sesudahnya dipakai
+	if anomaly.RefTs == nil || *anomaly.RefTs != fixes[3].Ts.UnixMilli() {
+		t.Errorf("RefTs = %v, want %d", anomaly.RefTs, fixes[3].Ts.UnixMilli())
+	}
+	if anomaly.DistanceNm == nil || math.Abs(*anomaly.DistanceNm-30) > 0.1 {
+		t.Errorf("DistanceNm = %v, want ~30", anomaly.DistanceNm)
+	}
+	// 30 NM dalam 1 menit
+	if anomaly.ImpliedSpeedKnots == nil || math.Abs(*anomaly.ImpliedSpeedKnots-1800) > 5 {
+		t.Errorf("ImpliedSpeedKnots = %v, want ~1800", anomaly.ImpliedSpeedKnots)
+	}
+	if anomaly.PositionID != fixes[2].ID.Hex() || anomaly.Ts != fixes[2].Ts.UnixMilli() {
+		t.Errorf("position/ts = %s/%d, want %s/%d", anomaly.PositionID, anomaly.Ts, fixes[2].ID.Hex(), fixes[2].Ts.UnixMilli())
+	}
+}
+
+func kinds(anomalies []*AisAnomaly) []models.AisAnomalyKind {
+	result := make([]models.AisAnomalyKind, len(anomalies))
+	for i, anomaly := range anomalies {
+		result[i] = anomaly.Kind
+	}
+	return result
+}
@@ -0,0 +1,237 @@
+package anomalies
+
+import (
+	"context"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/domains/ships"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const (
+	anomalyCollection = "ais_anomaly"
+	// jumlah _id per UpdateMany saat menandai ais_dynamic
+	markBatchSize = 1000
+)
+
+type anomalyMongotory struct {
+	db *mongo.Database
+}
+
+func NewAnomalyMongotory(db *mongo.Database) *anomalyMongotory {
+	return &anomalyMongotory{
+		db,
+	}
+}
+
+var _ AnomalyMongotory = &anomalyMongotory{}
+
+// anomalyDocument — bentuk AisAnomaly di koleksi ais_anomaly; ts disimpan
+// sebagai Date seperti koleksi ais_* lainnya
+type anomalyDocument struct {
+	ID                string                `bson:"_id,omitempty"`
+	PositionID        primitive.ObjectID    `bson:"position_id"`
+	Kind              models.AisAnomalyKind `bson:"kind"`
+	Mmsi              int64                 `bson:"mmsi"`
+	Ts                time.Time             `bson:"ts"`
+	Latitude          float64               `bson:"latitude"`
+	Longitude         float64               `bson:"longitude"`
+	Sog               *float64              `bson:"sog,omitempty"`
+	RefTs             *time.Time            `bson:"ref_ts,omitempty"`
+	RefLatitude       *float64              `bson:"ref_latitude,omitempty"`
+	RefLongitude      *float64              `bson:"ref_longitude,omitempty"`
+	DistanceNm        *float64              `bson:"distance_nm,omitempty"`
+	ImpliedSpeedKnots *float64              `bson:"implied_speed_knots,omitempty"`
+	Detail            string                `bson:"detail"`
+	DetectedAt        time.Time             `bson:"detected_at"`
+}
+
+func (r *anomalyMongotory) SaveAnomalies(ctx context.Context, anomalies []*AisAnomaly) (int, error) {
+	if len(anomalies) == 0 {
+		return 0, nil
+	}
+	timeoutCtx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+	defer cancel()
+
+	writes := make([]mongo.WriteModel, 0, len(anomalies))
+	marks := make(map[models.AisAnomalyKind][]primitive.ObjectID)
+	for _, anomaly := range anomalies {
+		positionID, err := primitive.ObjectIDFromHex(anomaly.PositionID)
+		if err != nil {
+			return 0, err
+		}
+		doc := &anomalyDocument{
+			PositionID:        positionID,
+			Kind:              anomaly.Kind,
+			Mmsi:              anomaly.Mmsi,
+			Ts:                time.UnixMilli(anomaly.Ts).UTC(),
+			Latitude:          anomaly.Latitude,
+			Longitude:         anomaly.Longitude,
+			Sog:               anomaly.Sog,
+			RefLatitude:       anomaly.RefLatitude,
+			RefLongitude:      anomaly.RefLongitude,
+			DistanceNm:        anomaly.DistanceNm,
+			ImpliedSpeedKnots: anomaly.ImpliedSpeedKnots,
+			Detail:            anomaly.Detail,
+			DetectedAt:        time.UnixMilli(anomaly.DetectedAt).UTC(),
+		}
+		if anomaly.RefTs != nil {
+			refTs := time.UnixMilli(*anomaly.RefTs).UTC()
+			doc.RefTs = &refTs
+		}
+
+		// deteksi ulang pada window yang bertumpuk tidak menimpa anomali lama
+		writes = append(writes, mongo.NewUpdateOneModel().
+			SetFilter(bson.M{"_id": anomaly.ID}).
+			SetUpdate(bson.M{"$setOnInsert": doc}).
+			SetUpsert(true))
+		marks[anomaly.Kind] = append(marks[anomaly.Kind], positionID)
+	}
+
+	// Unordered agar satu dokumen gagal tidak menghentikan sisa batch
+	result, err := r.db.Collection(anomalyCollection).BulkWrite(timeoutCtx, writes, options.BulkWrite().SetOrdered(false))
+	if err != nil {
+		return 0, err
+	}
+
+	for kind, ids := range marks {
+		for from := 0; from < len(ids); from += markBatchSize {
+			to := min(from+markBatchSize, len(ids))
+			_, err := r.db.Collection("ais_dynamic").UpdateMany(
+				timeoutCtx,
+				bson.M{"_id": bson.M{"$in": ids[from:to]}},
+				bson.M{"$addToSet": bson.M{ships.AnomaliesField: kind}},
+			)
+			if err != nil {
+				return int(result.UpsertedCount), err
+			}
+		}
+	}
+
+	return int(result.UpsertedCount), nil
+}
+
+func (r *anomalyMongotory) ClearAnomalies(ctx context.Context, mmsi int64, kinds []models.AisAnomalyKind, start, end time.Time, keepIDs []string) (int, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+	defer cancel()
+
+	if keepIDs == nil {
+		keepIDs = []string{}
+	}
+	filter := bson.M{
+		"mmsi": mmsi,
+		"kind": bson.M{"$in": kinds},
+		"ts":   bson.M{"$gte": start.UTC(), "$lte": end.UTC()},
+		"_id":  bson.M{"$nin": keepIDs},
+	}
+
+	opts := options.Find().SetProjection(bson.M{"position_id": 1, "kind": 1})
+	cursor, err := r.db.Collection(anomalyCollection).Find(timeoutCtx, filter, opts)
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(timeoutCtx)
+
+	var ids []string
+	marks := make(map[models.AisAnomalyKind][]primitive.ObjectID)
+	for cursor.Next(timeoutCtx) {
+		var doc anomalyDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return 0, err
+		}
+		ids = append(ids, doc.ID)
+		marks[doc.Kind] = append(marks[doc.Kind], doc.PositionID)
+	}
+	if err := cursor.Err(); err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	// tanda ais_dynamic dilepas dulu; bila gagal, anomali tetap ada dan
+	// dihapus lagi pada putaran berikutnya
+	for kind, positionIDs := range marks {
+		for from := 0; from < len(positionIDs); from += markBatchSize {
+			to := min(from+markBatchSize, len(positionIDs))
+			_, err := r.db.Collection("ais_dynamic").UpdateMany(
+				timeoutCtx,
+				bson.M{"_id": bson.M{"$in": positionIDs[from:to]}},
+				bson.M{"$pull": bson.M{ships.AnomaliesField: kind}},
+			)
+			if err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	result, err := r.db.Collection(anomalyCollection).DeleteMany(timeoutCtx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return 0, err
+	}
+
+	return int(result.DeletedCount), nil
+}
+
+func (r *anomalyMongotory) GetAnomalies(ctx context.Context, query AnomalyQuery) ([]*AisAnomaly, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"ts": bson.M{"$gte": query.Start.UTC(), "$lte": query.End.UTC()},
+	}
+	if query.Mmsi != nil {
+		filter["mmsi"] = *query.Mmsi
+	}
+	if len(query.Kinds) > 0 {
+		filter["kind"] = bson.M{"$in": query.Kinds}
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "ts", Value: -1}, {Key: "_id", Value: 1}}).
+		SetLimit(int64(query.Limit))
+	cursor, err := r.db.Collection(anomalyCollection).Find(timeoutCtx, filter, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(timeoutCtx)
+
+	results := []*AisAnomaly{}
+	for cursor.Next(timeoutCtx) {
+		var doc anomalyDocument
+		if err := cursor.Decode(&doc); err != nil {
+			return nil, err
+		}
+		anomaly := &AisAnomaly{
+			ID:                doc.ID,
+			PositionID:        doc.PositionID.Hex(),
+			Kind:              doc.Kind,
+			Mmsi:              doc.Mmsi,
+			Ts:                doc.Ts.UnixMilli(),
+			TsIso:             doc.Ts.UTC().Format(time.RFC3339Nano),
+			Latitude:          doc.Latitude,
+			Longitude:         doc.Longitude,
+			Sog:               doc.Sog,
+			RefLatitude:       doc.RefLatitude,
+			RefLongitude:      doc.RefLongitude,
+			DistanceNm:        doc.DistanceNm,
+			ImpliedSpeedKnots: doc.ImpliedSpeedKnots,
+			Detail:            doc.Detail,
+			DetectedAt:        doc.DetectedAt.UnixMilli(),
+		}
+		if doc.RefTs != nil {
+			refTs := doc.RefTs.UnixMilli()
+			anomaly.RefTs = &refTs
+		}
+		results = append(results, anomaly)
+	}
+	if err := cursor.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,126 @@
+package anomalies
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// WorkerConfig dibaca dari environment:
+//
+//	AIS_ANOMALY_INTERVAL         interval deteksi, default "5m" ("0" = nonaktif)
+//	AIS_ANOMALY_LOOKBACK         panjang window per putaran, default "1h"
+//	AIS_ANOMALY_SETTLE           titik yang lebih baru dari ini hanya menjadi konteks
+//	                             dan ditandai pada putaran berikutnya, default "10m"
+//	AIS_ANOMALY_MAX_SPEED_KNOTS  kecepatan tersirat maksimal, default 60
+//	AIS_ANOMALY_MIN_JUMP_NM      perpindahan minimal yang dianggap lompatan, default 1
+type WorkerConfig struct {
+	Interval time.Duration
+	Lookback time.Duration
+	Settle   time.Duration
+	Options  DetectionOptions
+}
+
+func DefaultWorkerConfig() WorkerConfig {
+	return WorkerConfig{
+		Interval: 5 * time.Minute,
+		Lookback: time.Hour,
+		Settle:   10 * time.Minute,
+		Options:  DefaultDetectionOptions(),
+	}
+}
+
+// LoadWorkerConfig membaca konfigurasi worker anomali AIS dari environment
+func LoadWorkerConfig() (WorkerConfig, error) {
+	config := DefaultWorkerConfig()
+
+	durations := map[string]*time.Duration{
+		"AIS_ANOMALY_INTERVAL": &config.Interval,
+		"AIS_ANOMALY_LOOKBACK": &config.Lookback,
+		"AIS_ANOMALY_SETTLE":   &config.Settle,
+	}
+	for key, target := range durations {
+		if raw := strings.TrimSpace(os.Getenv(key)); raw != "" {
+			value, err := time.ParseDuration(raw)
+			if err != nil || value < 0 {
+				return config, fmt.Errorf("invalid %s: %q", key, raw)
+			}
+			*target = value
+		}
+	}
+
+	floats := map[string]*float64{
+		"AIS_ANOMALY_MAX_SPEED_KNOTS": &config.Options.MaxSpeedKnots,
+		"AIS_ANOMALY_MIN_JUMP_NM":     &config.Options.MinJumpNm,
+	}
+	for key, target := range floats {
+		if raw := strings.TrimSpace(os.Getenv(key)); raw != "" {
+			value, err := strconv.ParseFloat(raw, 64)
+			if err != nil || value <= 0 {
+				return config, fmt.Errorf("invalid %s: %q", key, raw)
+			}
+			*target = value
+		}
+	}
+
+	if config.Settle >= config.Lookback {
+		return config, fmt.Errorf("AIS_ANOMALY_SETTLE (%s) harus lebih kecil dari AIS_ANOMALY_LOOKBACK (%s)", config.Settle, config.Lookback)
+	}
+
+	return config, nil
+}
+
+// Worker menandai anomali posisi terbaru secara berkala. Window antar putaran
+// bertumpuk sehingga setiap titik dinilai dengan konteks minimal Settle sesudahnya.
+type Worker struct {
+	detector *Detector
+	config   WorkerConfig
+}
+
+func NewWorker(detector *Detector, config WorkerConfig) *Worker {
+	return &Worker{
+		detector: detector,
+		config:   config,
+	}
+}
+
+func (w *Worker) Start(ctx context.Context) {
+	if w.config.Interval <= 0 {
+		log.Printf("ais anomaly worker disabled (AIS_ANOMALY_INTERVAL=0)")
+		return
+	}
+
+	log.Printf("ais anomaly worker started: interval %s, lookback %s, settle %s", w.config.Interval, w.config.Lookback, w.config.Settle)
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.run(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) run(ctx context.Context) {
+	end := time.Now()
+	start := end.Add(-w.config.Lookback)
+
+	opts := w.config.Options
+	opts.FlagUntil = end.Add(-w.config.Settle)
+
+	scan, err := w.detector.Detect(ctx, nil, start, end, opts)
+	if err != nil {
+		log.Printf("ais anomaly detection failed: %v", err)
+		return
+	}
+	if scan.NewAnomalyCount > 0 {
+		log.Printf("ais anomaly detection: %d new anomaly(ies) from %d position(s) of %d vessel(s)", scan.NewAnomalyCount, scan.PositionCount, scan.VesselCount)
+	}
+}
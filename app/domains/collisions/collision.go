@@ -0,0 +1,158 @@
+package collisions
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/domains/devices"
+	"github.com/khoirulhasin/untirta_api/app/domains/ships"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+// CollisionRisk — CPA/TCPA own ship terhadap satu target AIS
+type CollisionRisk struct {
+	Target      *ships.VesselSnapshot `json:"target"`
+	DistanceNm  float64               `json:"distanceNm"`
+	Bearing     float64               `json:"bearing"`
+	CpaNm       float64               `json:"cpaNm"`
+	TcpaMinutes float64               `json:"tcpaMinutes"`
+	Dangerous   bool                  `json:"dangerous"`
+}
+
+// CollisionAssessment — semua target di sekitar satu kapal own-fleet
+type CollisionAssessment struct {
+	ShipID int                   `json:"shipId"`
+	Imei   *string               `json:"imei,omitempty"`
+	Own    *ships.VesselPosition `json:"own,omitempty"`
+	Risks  []*CollisionRisk      `json:"risks"`
+}
+
+const (
+	defaultRadiusNm    = 6.0
+	defaultCpaNm       = 0.5
+	defaultTcpaMinutes = 12.0
+	defaultMaxAge      = 10 * time.Minute
+)
+
+// Limits adalah batas CPA/TCPA; target di luar RadiusNm tidak dinilai
+type Limits struct {
+	RadiusNm    float64
+	CpaNm       float64
+	TcpaMinutes float64
+	// umur posisi maksimal (own maupun target)
+	MaxAge time.Duration
+}
+
+func DefaultLimits() Limits {
+	return Limits{
+		RadiusNm:    defaultRadiusNm,
+		CpaNm:       defaultCpaNm,
+		TcpaMinutes: defaultTcpaMinutes,
+		MaxAge:      defaultMaxAge,
+	}
+}
+
+// NewLimits mengisi nilai default untuk field input yang kosong
+func NewLimits(input *models.CollisionLimitsInput) Limits {
+	limits := DefaultLimits()
+	if input == nil {
+		return limits
+	}
+	if input.RadiusNm != nil {
+		limits.RadiusNm = *input.RadiusNm
+	}
+	if input.CpaNm != nil {
+		limits.CpaNm = *input.CpaNm
+	}
+	if input.TcpaMinutes != nil {
+		limits.TcpaMinutes = *input.TcpaMinutes
+	}
+	if input.MaxAgeSeconds != nil {
+		limits.MaxAge = time.Duration(*input.MaxAgeSeconds) * time.Second
+	}
+	return limits
+}
+
+// Assessor menghitung CPA/TCPA kapal own-fleet (posisi dari perangkat IMEI
+// yang terpasang lewat Device.ShipID) terhadap posisi AIS terakhir di sekitarnya
+type Assessor struct {
+	deviceRepository devices.DeviceRepository
+	shipMongotory    ships.ShipMongotory
+	shipMongodistory ships.ShipMongodistory
+}
+
+func NewAssessor(deviceRepository devices.DeviceRepository, shipMongotory ships.ShipMongotory, shipMongodistory ships.ShipMongodistory) *Assessor {
+	return &Assessor{
+		deviceRepository: deviceRepository,
+		shipMongotory:    shipMongotory,
+		shipMongodistory: shipMongodistory,
+	}
+}
+
+func (a *Assessor) Assess(ctx context.Context, shipID int, limits Limits) (*CollisionAssessment, error) {
+	shipDevices, err := a.deviceRepository.GetDevicesByShipID(ctx, int32(shipID))
+	if err != nil {
+		return nil, err
+	}
+	return a.assess(ctx, shipID, shipDevices, limits, time.Now())
+}
+
+func (a *Assessor) assess(ctx context.Context, shipID int, shipDevices []*models.Device, limits Limits, now time.Time) (*CollisionAssessment, error) {
+	assessment := &CollisionAssessment{ShipID: shipID, Risks: []*CollisionRisk{}}
+
+	// Kapal bisa punya lebih dari satu perangkat: pakai posisi yang paling baru
+	for _, device := range shipDevices {
+		position, err := a.shipMongotory.GetLatestPositionByImei(ctx, device.Imei, now.Add(-limits.MaxAge))
+		if err != nil {
+			return nil, err
+		}
+		if position != nil && (assessment.Own == nil || position.Ts > assessment.Own.Ts) {
+			imei := device.Imei
+			assessment.Own = position
+			assessment.Imei = &imei
+		}
+	}
+	if assessment.Own == nil {
+		return assessment, nil
+	}
+
+	own := assessment.Own.Motion().DeadReckon(now)
+	radiusMeters := limits.RadiusNm * geo.MetersPerNauticalMile
+
+	snapshots, err := a.shipMongodistory.GetVesselSnapshot(ctx, geo.BoundingBoxAround(own.Point, radiusMeters), now, limits.MaxAge)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, snapshot := range snapshots {
+		if snapshot.Position == nil || snapshot.Mmsi == assessment.Own.Mmsi {
+			continue
+		}
+		approach := geo.ClosestApproach(own, snapshot.Position.Motion())
+		if approach.DistanceMeters > radiusMeters {
+			continue
+		}
+
+		risk := &CollisionRisk{
+			Target:      snapshot,
+			DistanceNm:  approach.DistanceMeters / geo.MetersPerNauticalMile,
+			Bearing:     approach.Bearing,
+			CpaNm:       approach.CpaMeters / geo.MetersPerNauticalMile,
+			TcpaMinutes: approach.Tcpa.Minutes(),
+		}
+		risk.Dangerous = risk.CpaNm <= limits.CpaNm && risk.TcpaMinutes >= 0 && risk.TcpaMinutes <= limits.TcpaMinutes
+		assessment.Risks = append(assessment.Risks, risk)
+	}
+
+	// Target berbahaya di depan, lalu CPA terkecil
+	sort.SliceStable(assessment.Risks, func(i, j int) bool {
+		if assessment.Risks[i].Dangerous != assessment.Risks[j].Dangerous {
+			return assessment.Risks[i].Dangerous
+		}
+		return assessment.Risks[i].CpaNm < assessment.Risks[j].CpaNm
+	})
+
+	return assessment, nil
+}
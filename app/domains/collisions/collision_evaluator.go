@@ -0,0 +1,181 @@
+package collisions
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/domains/alerts"
+	"github.com/khoirulhasin/untirta_api/app/domains/devices"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+const defaultEvaluatorInterval = 30 * time.Second
+
+// EvaluatorConfig dibaca dari environment:
+//
+//	COLLISION_EVAL_INTERVAL  interval evaluasi, default "30s" ("0" = nonaktif)
+//	COLLISION_RADIUS_NM      radius trafik yang dinilai, default 6
+//	COLLISION_CPA_NM         batas CPA, default 0.5
+//	COLLISION_TCPA_MINUTES   batas TCPA, default 12
+//	COLLISION_MAX_AGE        umur posisi maksimal, default "10m"
+type EvaluatorConfig struct {
+	Interval time.Duration
+	Limits   Limits
+}
+
+// LoadEvaluatorConfig membaca konfigurasi evaluator collision dari environment
+func LoadEvaluatorConfig() (EvaluatorConfig, error) {
+	config := EvaluatorConfig{Interval: defaultEvaluatorInterval, Limits: DefaultLimits()}
+
+	durations := map[string]*time.Duration{
+		"COLLISION_EVAL_INTERVAL": &config.Interval,
+		"COLLISION_MAX_AGE":       &config.Limits.MaxAge,
+	}
+	for key, target := range durations {
+		if raw := strings.TrimSpace(os.Getenv(key)); raw != "" {
+			value, err := time.ParseDuration(raw)
+			if err != nil {
+				return config, fmt.Errorf("invalid %s: %w", key, err)
+			}
+			*target = value
+		}
+	}
+
+	floats := map[string]*float64{
+		"COLLISION_RADIUS_NM":    &config.Limits.RadiusNm,
+		"COLLISION_CPA_NM":       &config.Limits.CpaNm,
+		"COLLISION_TCPA_MINUTES": &config.Limits.TcpaMinutes,
+	}
+	for key, target := range floats {
+		if raw := strings.TrimSpace(os.Getenv(key)); raw != "" {
+			value, err := strconv.ParseFloat(raw, 64)
+			if err != nil || value <= 0 {
+				return config, fmt.Errorf("invalid %s: %q", key, raw)
+			}
+			*target = value
+		}
+	}
+
+	return config, nil
+}
+
+// Evaluator menilai semua kapal own-fleet secara berkala dan membuat alert
+// COLLISION_RISK selama CPA dan TCPA berada di bawah batas
+type Evaluator struct {
+	assessor         *Assessor
+	deviceRepository devices.DeviceRepository
+	alertRepository  alerts.AlertRepository
+	config           EvaluatorConfig
+}
+
+func NewEvaluator(assessor *Assessor, deviceRepository devices.DeviceRepository, alertRepository alerts.AlertRepository, config EvaluatorConfig) *Evaluator {
+	return &Evaluator{
+		assessor:         assessor,
+		deviceRepository: deviceRepository,
+		alertRepository:  alertRepository,
+		config:           config,
+	}
+}
+
+func (e *Evaluator) Start(ctx context.Context) {
+	if e.config.Interval <= 0 {
+		log.Printf("collision evaluator disabled (COLLISION_EVAL_INTERVAL=0)")
+		return
+	}
+
+	log.Printf("collision evaluator started: interval %s, CPA %.2f NM, TCPA %.1f min", e.config.Interval, e.config.Limits.CpaNm, e.config.Limits.TcpaMinutes)
+	ticker := time.NewTicker(e.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := e.evaluate(ctx); err != nil {
+			log.Printf("collision evaluator: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *Evaluator) evaluate(ctx context.Context) error {
+	allDevices, err := e.deviceRepository.GetAllDevices(ctx)
+	if err != nil {
+		return err
+	}
+
+	byShip := make(map[int][]*models.Device)
+	for _, device := range allDevices {
+		if device.ShipID != nil {
+			byShip[*device.ShipID] = append(byShip[*device.ShipID], device)
+		}
+	}
+
+	now := time.Now()
+	sweep := alerts.NewSweep(models.AlertKindCollisionRisk)
+	for shipID, shipDevices := range byShip {
+		if err := e.evaluateShip(ctx, shipID, shipDevices, sweep, now); err != nil {
+			log.Printf("collision evaluator: ship %d: %v", shipID, err)
+			sweep.Fail(shipID)
+		}
+	}
+
+	_, err = sweep.Resolve(ctx, e.alertRepository)
+	return err
+}
+
+func (e *Evaluator) evaluateShip(ctx context.Context, shipID int, shipDevices []*models.Device, sweep *alerts.Sweep, now time.Time) error {
+	assessment, err := e.assessor.assess(ctx, shipID, shipDevices, e.config.Limits, now)
+	if err != nil {
+		return err
+	}
+
+	for _, risk := range assessment.Risks {
+		if !risk.Dangerous {
+			continue
+		}
+		alert := newCollisionAlert(assessment, risk, e.config.Limits, now)
+		if _, err := e.alertRepository.RaiseAlert(ctx, alert); err != nil {
+			return err
+		}
+		sweep.Keep(alert.DedupKey)
+	}
+	return nil
+}
+
+func newCollisionAlert(assessment *CollisionAssessment, risk *CollisionRisk, limits Limits, now time.Time) *models.Alert {
+	severity := models.AlertSeverityWarning
+	if risk.CpaNm <= limits.CpaNm/2 {
+		severity = models.AlertSeverityCritical
+	}
+
+	shipID := assessment.ShipID
+	targetMmsi := risk.Target.Mmsi
+	latitude, longitude := assessment.Own.Latitude, assessment.Own.Longitude
+	cpaNm, tcpaMinutes := risk.CpaNm, risk.TcpaMinutes
+
+	alert := &models.Alert{
+		Kind:        models.AlertKindCollisionRisk,
+		Severity:    severity,
+		DedupKey:    fmt.Sprintf("collision:%d:%d", shipID, targetMmsi),
+		Message:     fmt.Sprintf("Risiko tabrakan dengan MMSI %d: CPA %.2f NM dalam %.1f menit", targetMmsi, cpaNm, tcpaMinutes),
+		ShipID:      &shipID,
+		TargetMmsi:  &targetMmsi,
+		Latitude:    &latitude,
+		Longitude:   &longitude,
+		CpaNm:       &cpaNm,
+		TcpaMinutes: &tcpaMinutes,
+		LastSeenAt:  now.UnixMilli(),
+	}
+	if assessment.Own.Mmsi != 0 {
+		mmsi := assessment.Own.Mmsi
+		alert.Mmsi = &mmsi
+	}
+	return alert
+}
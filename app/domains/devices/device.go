@@ -15,5 +15,6 @@ type DeviceRepository interface {
 	GetDeviceByID(ctx context.Context, id int32) (*models.Device, error)
 	GetDeviceByUUID(ctx context.Context, uuid string) (*models.Device, error)
 	GetAllDevices(ctx context.Context) ([]*models.Device, error)
+	GetDevicesByShipID(ctx context.Context, shipID int32) ([]*models.Device, error)
 	PageDevice(ctx context.Context, pagination models.Pagination) (models.Pagination, error)
 }
@@ -113,6 +113,19 @@ func (r *deviceRepository) GetAllDevices(ctx context.Context) ([]*models.Device,
 
 }
 
+func (r *deviceRepository) GetDevicesByShipID(ctx context.Context, shipID int32) ([]*models.Device, error) {
+
+	var devices []*models.Device
+
+	err := r.db.WithContext(ctx).Where("ship_id = ?", shipID).Find(&devices).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return devices, nil
+
+}
+
 func (r *deviceRepository) PageDevice(ctx context.Context, pagination models.Pagination) (models.Pagination, error) {
 	var devices []models.Device
 
@@ -0,0 +1,186 @@
+package gaps
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/domains/ships"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+// AisGap — satu jeda transmisi; FirstPosition nil selama jeda masih berlangsung
+type AisGap struct {
+	Mmsi               int64                      `json:"mmsi"`
+	StartTime          int64                      `json:"startTime"`
+	EndTime            *int64                     `json:"endTime,omitempty"`
+	DurationMinutes    float64                    `json:"durationMinutes"`
+	ThresholdMinutes   float64                    `json:"thresholdMinutes"`
+	NavigationalStatus *models.NavigationalStatus `json:"navigationalStatus,omitempty"`
+	LastPosition       *ships.VesselPosition      `json:"lastPosition"`
+	FirstPosition      *ships.VesselPosition      `json:"firstPosition,omitempty"`
+	DistanceNm         *float64                   `json:"distanceNm,omitempty"`
+	ImpliedSpeedKnots  *float64                   `json:"impliedSpeedKnots,omitempty"`
+	Ongoing            bool                       `json:"ongoing"`
+}
+
+// Thresholds adalah batas jeda per navigational status. Class A melapor tiap
+// 2-10 detik saat berlayar dan tiap 3 menit saat berlabuh/tambat; Class B
+// (tanpa status) memakai Default.
+type Thresholds struct {
+	Default  time.Duration
+	ByStatus map[models.NavigationalStatus]time.Duration
+}
+
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		Default: 15 * time.Minute,
+		ByStatus: map[models.NavigationalStatus]time.Duration{
+			models.NavigationalStatusAtAnchor: 45 * time.Minute,
+			models.NavigationalStatusMoored:   45 * time.Minute,
+			models.NavigationalStatusAground:  45 * time.Minute,
+		},
+	}
+}
+
+// LoadThresholds membaca env AIS_GAP_THRESHOLDS, mis. "DEFAULT=15m,MOORED=1h,AT_ANCHOR=45m".
+// Status yang tidak disebut memakai nilai bawaan.
+func LoadThresholds() (Thresholds, error) {
+	thresholds := DefaultThresholds()
+
+	for _, raw := range strings.Split(os.Getenv("AIS_GAP_THRESHOLDS"), ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(raw, "=")
+		if !ok {
+			return thresholds, fmt.Errorf("invalid AIS_GAP_THRESHOLDS entry: %q", raw)
+		}
+		duration, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil || duration <= 0 {
+			return thresholds, fmt.Errorf("invalid AIS_GAP_THRESHOLDS duration: %q", raw)
+		}
+
+		key = strings.ToUpper(strings.TrimSpace(key))
+		if key == "DEFAULT" {
+			thresholds.Default = duration
+			continue
+		}
+		status := models.NavigationalStatus(key)
+		if !status.IsValid() {
+			return thresholds, fmt.Errorf("invalid AIS_GAP_THRESHOLDS status: %q", key)
+		}
+		thresholds.ByStatus[status] = duration
+	}
+
+	return thresholds, nil
+}
+
+// For mengembalikan batas jeda setelah posisi berstatus status (nil = Class B / tidak diketahui)
+func (t Thresholds) For(status *models.NavigationalStatus) time.Duration {
+	if status != nil {
+		if threshold, ok := t.ByStatus[*status]; ok {
+			return threshold
+		}
+	}
+	return t.Default
+}
+
+// NewAisGap membuat jeda setelah posisi last. first nil berarti jeda masih
+// berlangsung sampai end.
+func NewAisGap(last, first *ships.VesselPosition, end time.Time, threshold time.Duration) *AisGap {
+	gap := &AisGap{
+		Mmsi:               last.Mmsi,
+		StartTime:          last.Ts,
+		DurationMinutes:    end.Sub(time.UnixMilli(last.Ts)).Minutes(),
+		ThresholdMinutes:   threshold.Minutes(),
+		NavigationalStatus: last.NavigationalStatus,
+		LastPosition:       last,
+		FirstPosition:      first,
+		Ongoing:            first == nil,
+	}
+	if first == nil {
+		return gap
+	}
+
+	endTime := first.Ts
+	gap.EndTime = &endTime
+	from := geo.Point{Lat: last.Latitude, Lon: last.Longitude}
+	to := geo.Point{Lat: first.Latitude, Lon: first.Longitude}
+	if geo.ValidPosition(from) && geo.ValidPosition(to) {
+		distanceNm := geo.DistanceMeters(from, to) / geo.MetersPerNauticalMile
+		gap.DistanceNm = &distanceNm
+		if hours := gap.DurationMinutes / 60; hours > 0 {
+			speed := distanceNm / hours
+			gap.ImpliedSpeedKnots = &speed
+		}
+	}
+	return gap
+}
+
+// Detector menghitung jeda transmisi dari history ais_dynamic
+type Detector struct {
+	shipMongotory ships.ShipMongotory
+	thresholds    Thresholds
+}
+
+func NewDetector(shipMongotory ships.ShipMongotory, thresholds Thresholds) *Detector {
+	return &Detector{
+		shipMongotory: shipMongotory,
+		thresholds:    thresholds,
+	}
+}
+
+// Gaps mengembalikan jeda per MMSI (urut MMSI lalu waktu) di rentang [start, end].
+// Jeda yang belum berakhir dihitung sampai end atau sekarang, mana yang lebih dulu.
+// mmsiList kosong berarti semua MMSI yang mengirim posisi dalam rentang.
+func (d *Detector) Gaps(ctx context.Context, mmsiList []int64, start, end time.Time, minGap time.Duration) ([]*AisGap, error) {
+	if len(mmsiList) == 0 {
+		var err error
+		mmsiList, err = d.shipMongotory.GetMmsiByDatetime(models.DurationTimeInput{Start: start.Unix(), End: end.Unix()})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if now := time.Now(); end.After(now) {
+		end = now
+	}
+
+	results := []*AisGap{}
+	for _, mmsi := range mmsiList {
+		var last *ships.VesselPosition
+		query := ships.TrackQuery{Mmsi: &mmsi, Start: start, End: end}
+		err := d.shipMongotory.StreamTrack(ctx, query, func(position *ships.VesselPosition) error {
+			if last != nil {
+				if gap := d.gap(last, position, time.UnixMilli(position.Ts), minGap); gap != nil {
+					results = append(results, gap)
+				}
+			}
+			last = position
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		if last != nil {
+			if gap := d.gap(last, nil, end, minGap); gap != nil {
+				results = append(results, gap)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+func (d *Detector) gap(last, first *ships.VesselPosition, end time.Time, minGap time.Duration) *AisGap {
+	threshold := d.thresholds.For(last.NavigationalStatus)
+	silence := end.Sub(time.UnixMilli(last.Ts))
+	if silence < threshold || silence < minGap {
+		return nil
+	}
+	return NewAisGap(last, first, end, threshold)
+}
@@ -0,0 +1,183 @@
+package gaps
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/domains/alerts"
+	"github.com/khoirulhasin/untirta_api/app/domains/devices"
+	"github.com/khoirulhasin/untirta_api/app/domains/ships"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+const (
+	defaultMonitorInterval = time.Minute
+	// criticalFactor: jeda sepanjang kelipatan ini dari batasnya menjadi CRITICAL
+	criticalFactor = 3
+)
+
+// MonitorConfig dibaca dari environment:
+//
+//	AIS_GAP_EVAL_INTERVAL  interval pemeriksaan own-fleet, default "1m" ("0" = nonaktif)
+//	AIS_GAP_THRESHOLDS     batas jeda per navigational status, lihat LoadThresholds
+type MonitorConfig struct {
+	Interval   time.Duration
+	Thresholds Thresholds
+}
+
+func DefaultMonitorConfig() MonitorConfig {
+	return MonitorConfig{
+		Interval:   defaultMonitorInterval,
+		Thresholds: DefaultThresholds(),
+	}
+}
+
+// LoadMonitorConfig membaca konfigurasi monitor jeda AIS dari environment
+func LoadMonitorConfig() (MonitorConfig, error) {
+	config := DefaultMonitorConfig()
+
+	thresholds, err := LoadThresholds()
+	if err != nil {
+		return config, err
+	}
+	config.Thresholds = thresholds
+
+	if raw := strings.TrimSpace(os.Getenv("AIS_GAP_EVAL_INTERVAL")); raw != "" {
+		value, err := time.ParseDuration(raw)
+		if err != nil || value < 0 {
+			return config, fmt.Errorf("invalid AIS_GAP_EVAL_INTERVAL: %q", raw)
+		}
+		config.Interval = value
+	}
+
+	return config, nil
+}
+
+// Monitor memeriksa kapal own-fleet secara berkala dan membuat alert AIS_GAP
+// selama perangkat yang terpasang di kapal tidak mengirim posisi melebihi
+// batas jeda status terakhirnya. Alert tertutup otomatis saat posisi masuk lagi.
+type Monitor struct {
+	deviceRepository devices.DeviceRepository
+	shipMongotory    ships.ShipMongotory
+	alertRepository  alerts.AlertRepository
+	config           MonitorConfig
+}
+
+func NewMonitor(deviceRepository devices.DeviceRepository, shipMongotory ships.ShipMongotory, alertRepository alerts.AlertRepository, config MonitorConfig) *Monitor {
+	return &Monitor{
+		deviceRepository: deviceRepository,
+		shipMongotory:    shipMongotory,
+		alertRepository:  alertRepository,
+		config:           config,
+	}
+}
+
+func (m *Monitor) Start(ctx context.Context) {
+	if m.config.Interval <= 0 {
+		log.Printf("AIS gap monitor disabled (AIS_GAP_EVAL_INTERVAL=0)")
+		return
+	}
+
+	log.Printf("AIS gap monitor started: interval %s, default threshold %s", m.config.Interval, m.config.Thresholds.Default)
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.evaluate(ctx); err != nil {
+			log.Printf("AIS gap monitor: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (m *Monitor) evaluate(ctx context.Context) error {
+	allDevices, err := m.deviceRepository.GetAllDevices(ctx)
+	if err != nil {
+		return err
+	}
+
+	// posisi terbaru per kapal dari semua perangkatnya; perangkat yang belum
+	// pernah mengirim posisi tidak dinilai. Kapal yang salah satu perangkatnya
+	// gagal dibaca dilewati dan alert-nya dibiarkan terbuka.
+	sweep := alerts.NewSweep(models.AlertKindAisGap)
+	latest := make(map[int]shipPosition)
+	for _, device := range allDevices {
+		if device.ShipID == nil {
+			continue
+		}
+		position, err := m.shipMongotory.GetLatestPositionByImei(ctx, device.Imei, time.Time{})
+		if err != nil {
+			log.Printf("AIS gap monitor: device %s: %v", device.Imei, err)
+			sweep.Fail(*device.ShipID)
+			continue
+		}
+		if position == nil {
+			continue
+		}
+		if current, ok := latest[*device.ShipID]; !ok || position.Ts > current.position.Ts {
+			latest[*device.ShipID] = shipPosition{imei: device.Imei, position: position}
+		}
+	}
+
+	now := time.Now()
+	for shipID, latest := range latest {
+		if sweep.Failed(shipID) {
+			continue
+		}
+		threshold := m.config.Thresholds.For(latest.position.NavigationalStatus)
+		if now.Sub(time.UnixMilli(latest.position.Ts)) < threshold {
+			continue
+		}
+		alert := newGapAlert(shipID, latest.imei, NewAisGap(latest.position, nil, now, threshold), now)
+		if _, err := m.alertRepository.RaiseAlert(ctx, alert); err != nil {
+			log.Printf("AIS gap monitor: ship %d: %v", shipID, err)
+			sweep.Fail(shipID)
+			continue
+		}
+		sweep.Keep(alert.DedupKey)
+	}
+
+	_, err = sweep.Resolve(ctx, m.alertRepository)
+	return err
+}
+
+// shipPosition — posisi terbaru kapal beserta perangkat pengirimnya
+type shipPosition struct {
+	imei     string
+	position *ships.VesselPosition
+}
+
+func newGapAlert(shipID int, imei string, gap *AisGap, now time.Time) *models.Alert {
+	severity := models.AlertSeverityWarning
+	if gap.DurationMinutes >= criticalFactor*gap.ThresholdMinutes {
+		severity = models.AlertSeverityCritical
+	}
+
+	position := gap.LastPosition
+	latitude, longitude := position.Latitude, position.Longitude
+
+	alert := &models.Alert{
+		Kind:       models.AlertKindAisGap,
+		Severity:   severity,
+		DedupKey:   fmt.Sprintf("ais_gap:%d", shipID),
+		Message:    fmt.Sprintf("Perangkat %s tidak mengirim posisi selama %.0f menit (batas %.0f menit)", imei, gap.DurationMinutes, gap.ThresholdMinutes),
+		ShipID:     &shipID,
+		DeviceImei: &imei,
+		Latitude:   &latitude,
+		Longitude:  &longitude,
+		LastSeenAt: now.UnixMilli(),
+	}
+	if position.Mmsi != 0 {
+		mmsi := position.Mmsi
+		alert.Mmsi = &mmsi
+	}
+	return alert
+}
@@ -3,6 +3,7 @@ package geofences
 import (
 	"context"
 
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
 	"github.com/khoirulhasin/untirta_api/app/models"
 	"gorm.io/plugin/soft_delete"
 )
@@ -41,5 +42,7 @@ type GeofenceRepository interface {
 	GetGeofenceByID(ctx context.Context, id int32) (*GeofenceDB, error)
 	GetGeofenceByUUID(ctx context.Context, uuid string) (*GeofenceDB, error)
 	GetAllGeofences(ctx context.Context) ([]*GeofenceDB, error)
+	// GetGeofencesContainingPoint mengembalikan geofence aktif yang memuat titik p
+	GetGeofencesContainingPoint(ctx context.Context, p geo.Point) ([]*GeofenceDB, error)
 	PageGeofence(ctx context.Context, pagination models.Pagination) (models.Pagination, error)
 }
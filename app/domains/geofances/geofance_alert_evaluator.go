@@ -0,0 +1,228 @@
+package geofences
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/domains/alerts"
+	"github.com/khoirulhasin/untirta_api/app/domains/devices"
+	"github.com/khoirulhasin/untirta_api/app/domains/ships"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+type raisedRule struct {
+	dedupKey string
+	at       time.Time
+	cooldown time.Duration
+}
+
+// RuleEvaluator mencocokkan hasil evaluasi Engine dengan GeofenceAlertRule aktif
+// dan membuat Alert kind GEOFENCE. ENTER/EXIT menghasilkan satu alert per event,
+// DWELL/SPEEDING satu alert per kunjungan; cooldown berlaku per aturan dan vessel.
+// Dijalankan di goroutine Engine sehingga state tidak perlu dikunci.
+// Target OWNER mencakup perangkat milik owner (IMEI) dan MMSI kapal tempat
+// perangkat tersebut terpasang, karena posisi AIS kapal hanya membawa MMSI.
+type RuleEvaluator struct {
+	ruleRepository   GeofenceAlertRuleRepository
+	alertRepository  alerts.AlertRepository
+	deviceRepository devices.DeviceRepository
+	shipRepository   ships.ShipRepository
+
+	rules       map[int32][]*GeofenceAlertRuleDB
+	devices     map[string]*models.Device
+	shipsByMmsi map[int64]*models.Ship
+	ownerImeis  map[int32]map[string]bool
+	ownerMmsis  map[int32]map[int64]bool
+	raised      map[string]raisedRule
+}
+
+func NewRuleEvaluator(ruleRepository GeofenceAlertRuleRepository, alertRepository alerts.AlertRepository, deviceRepository devices.DeviceRepository, shipRepository ships.ShipRepository) *RuleEvaluator {
+	return &RuleEvaluator{
+		ruleRepository:   ruleRepository,
+		alertRepository:  alertRepository,
+		deviceRepository: deviceRepository,
+		shipRepository:   shipRepository,
+		rules:            make(map[int32][]*GeofenceAlertRuleDB),
+		devices:          make(map[string]*models.Device),
+		shipsByMmsi:      make(map[int64]*models.Ship),
+		ownerImeis:       make(map[int32]map[string]bool),
+		ownerMmsis:       make(map[int32]map[int64]bool),
+		raised:           make(map[string]raisedRule),
+	}
+}
+
+// refresh memuat ulang aturan aktif, kepemilikan perangkat dan MMSI kapal
+func (r *RuleEvaluator) refresh(ctx context.Context) error {
+	list, err := r.ruleRepository.GetAllGeofenceAlertRules(ctx, nil)
+	if err != nil {
+		return err
+	}
+	allDevices, err := r.deviceRepository.GetAllDevices(ctx)
+	if err != nil {
+		return err
+	}
+	allShips, err := r.shipRepository.GetAllShips(ctx)
+	if err != nil {
+		return err
+	}
+
+	rules := make(map[int32][]*GeofenceAlertRuleDB)
+	for _, rule := range list {
+		if rule.IsActive {
+			rules[rule.GeofenceID] = append(rules[rule.GeofenceID], rule)
+		}
+	}
+
+	shipMmsis := make(map[int]int64)
+	byMmsi := make(map[int64]*models.Ship)
+	for _, ship := range allShips {
+		if ship.Mmsi != nil {
+			shipMmsis[ship.ID] = *ship.Mmsi
+			byMmsi[*ship.Mmsi] = ship
+		}
+	}
+
+	byImei := make(map[string]*models.Device, len(allDevices))
+	ownerImeis := make(map[int32]map[string]bool)
+	ownerMmsis := make(map[int32]map[int64]bool)
+	for _, device := range allDevices {
+		byImei[device.Imei] = device
+		if device.OwnerID == nil {
+			continue
+		}
+		owner := int32(*device.OwnerID)
+		if ownerImeis[owner] == nil {
+			ownerImeis[owner] = make(map[string]bool)
+			ownerMmsis[owner] = make(map[int64]bool)
+		}
+		ownerImeis[owner][device.Imei] = true
+		if device.ShipID != nil {
+			if mmsi, ok := shipMmsis[*device.ShipID]; ok {
+				ownerMmsis[owner][mmsi] = true
+			}
+		}
+	}
+
+	r.rules, r.devices, r.shipsByMmsi = rules, byImei, byMmsi
+	r.ownerImeis, r.ownerMmsis = ownerImeis, ownerMmsis
+
+	for key, last := range r.raised {
+		if since := time.Since(last.at); since > lastSeenRetention && since > last.cooldown {
+			delete(r.raised, key)
+		}
+	}
+
+	return nil
+}
+
+func (r *RuleEvaluator) evaluate(ctx context.Context, obs *observation) {
+	for _, event := range obs.events {
+		for _, rule := range r.rules[int32(event.GeofenceID)] {
+			switch {
+			case rule.Trigger == models.GeofenceRuleTriggerEnter && event.Kind == models.GeofenceEventKindEnter:
+				r.raise(ctx, rule, obs, fmt.Sprint(event.OccurredAt),
+					fmt.Sprintf("%s masuk geofence %s", subjectLabel(obs), event.GeofenceName))
+			case rule.Trigger == models.GeofenceRuleTriggerExit && event.Kind == models.GeofenceEventKindExit:
+				r.raise(ctx, rule, obs, fmt.Sprint(event.OccurredAt),
+					fmt.Sprintf("%s keluar dari geofence %s", subjectLabel(obs), event.GeofenceName))
+			}
+		}
+	}
+
+	for geofenceID, v := range obs.inside {
+		for _, rule := range r.rules[geofenceID] {
+			visitKey := fmt.Sprint(v.enteredAt.UnixMilli())
+			switch rule.Trigger {
+			case models.GeofenceRuleTriggerDwell:
+				dwell := obs.at.Sub(v.enteredAt)
+				if rule.DwellMinutes != nil && dwell >= time.Duration(*rule.DwellMinutes)*time.Minute {
+					r.raise(ctx, rule, obs, visitKey,
+						fmt.Sprintf("%s berada di geofence %s selama %d menit", subjectLabel(obs), v.geofence.Name, int(dwell.Minutes())))
+				}
+			case models.GeofenceRuleTriggerSpeeding:
+				if rule.SpeedKnots != nil && obs.sog != nil && *obs.sog > *rule.SpeedKnots {
+					r.raise(ctx, rule, obs, visitKey,
+						fmt.Sprintf("%s melaju %.1f knot di geofence %s (batas %.1f knot)", subjectLabel(obs), *obs.sog, v.geofence.Name, *rule.SpeedKnots))
+				}
+			}
+		}
+	}
+}
+
+// raise membuat alert bila vessel termasuk target, aturan sedang aktif, dan
+// occurrence ini belum pernah dilaporkan serta di luar cooldown
+func (r *RuleEvaluator) raise(ctx context.Context, rule *GeofenceAlertRuleDB, obs *observation, occurrence string, message string) {
+	if !r.targets(rule, obs) || !rule.ActiveAt(obs.at) {
+		return
+	}
+
+	key := fmt.Sprintf("%d|%s", rule.ID, obs.subject)
+	dedupKey := fmt.Sprintf("geofence:%d:%s:%s", rule.ID, obs.subject, occurrence)
+	cooldown := time.Duration(rule.CooldownMinutes) * time.Minute
+	if last, ok := r.raised[key]; ok && (last.dedupKey == dedupKey || obs.at.Sub(last.at) < cooldown) {
+		return
+	}
+
+	geofenceID, ruleID := int(rule.GeofenceID), int(rule.ID)
+	latitude, longitude := obs.point.Lat, obs.point.Lon
+	alert := &models.Alert{
+		Kind:        models.AlertKindGeofence,
+		Severity:    rule.Severity,
+		DedupKey:    dedupKey,
+		Message:     fmt.Sprintf("[%s] %s", rule.Name, message),
+		Mmsi:        obs.mmsi,
+		DeviceImei:  obs.imei,
+		GeofenceID:  &geofenceID,
+		RuleID:      &ruleID,
+		Latitude:    &latitude,
+		Longitude:   &longitude,
+		FirstSeenAt: obs.at.UnixMilli(),
+		LastSeenAt:  obs.at.UnixMilli(),
+	}
+	if obs.imei != nil {
+		if device, ok := r.devices[*obs.imei]; ok {
+			alert.ShipID = device.ShipID
+		}
+	}
+	if alert.ShipID == nil && obs.mmsi != nil {
+		if ship, ok := r.shipsByMmsi[*obs.mmsi]; ok {
+			shipID := ship.ID
+			alert.ShipID = &shipID
+		}
+	}
+
+	if _, err := r.alertRepository.RaiseAlert(ctx, alert); err != nil {
+		log.Printf("geofence alert rule %d: raise alert failed: %v", rule.ID, err)
+		return
+	}
+	r.raised[key] = raisedRule{dedupKey: dedupKey, at: obs.at, cooldown: cooldown}
+}
+
+// targets memeriksa apakah vessel/perangkat termasuk target aturan
+func (r *RuleEvaluator) targets(rule *GeofenceAlertRuleDB, obs *observation) bool {
+	switch rule.Target {
+	case models.GeofenceRuleTargetAll:
+		return true
+	case models.GeofenceRuleTargetMmsiList:
+		return obs.mmsi != nil && rule.MmsiList.Contains(*obs.mmsi)
+	case models.GeofenceRuleTargetOwner:
+		if rule.OwnerID == nil {
+			return false
+		}
+		return (obs.imei != nil && r.ownerImeis[*rule.OwnerID][*obs.imei]) ||
+			(obs.mmsi != nil && r.ownerMmsis[*rule.OwnerID][*obs.mmsi])
+	}
+	return false
+}
+
+func subjectLabel(obs *observation) string {
+	if obs.mmsi != nil && *obs.mmsi > 0 {
+		return fmt.Sprintf("MMSI %d", *obs.mmsi)
+	}
+	if obs.imei != nil {
+		return "Perangkat IMEI " + *obs.imei
+	}
+	return obs.subject
+}
@@ -0,0 +1,184 @@
+package geofences
+
+import (
+	"context"
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"gorm.io/plugin/soft_delete"
+)
+
+const (
+	defaultRuleTimezone = "Asia/Jakarta"
+	defaultRuleCooldown = 30
+)
+
+// GeofenceAlertRuleDB adalah struct GORM — ditulis manual seperti GeofenceDB
+// karena mmsiList dan activeDays disimpan sebagai JSONB
+type GeofenceAlertRuleDB struct {
+	ID              int32                      `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	UUID            string                     `json:"uuid" gorm:"column:uuid;uniqueIndex;type:uuid;default:uuid_generate_v4()"`
+	Name            string                     `json:"name" gorm:"column:name;not null"`
+	Description     *string                    `json:"description" gorm:"column:description"`
+	GeofenceID      int32                      `json:"geofenceId" gorm:"column:geofence_id;not null;index"`
+	Trigger         models.GeofenceRuleTrigger `json:"trigger" gorm:"column:trigger;not null"`
+	DwellMinutes    *int32                     `json:"dwellMinutes" gorm:"column:dwell_minutes"`
+	SpeedKnots      *float64                   `json:"speedKnots" gorm:"column:speed_knots"`
+	Target          models.GeofenceRuleTarget  `json:"target" gorm:"column:target;not null"`
+	MmsiList        JSONInt64s                 `json:"mmsiList" gorm:"column:mmsi_list;type:jsonb;default:'[]'"`
+	OwnerID         *int32                     `json:"ownerId" gorm:"column:owner_id"`
+	ActiveFrom      *string                    `json:"activeFrom" gorm:"column:active_from"`
+	ActiveTo        *string                    `json:"activeTo" gorm:"column:active_to"`
+	ActiveDays      JSONInt64s                 `json:"activeDays" gorm:"column:active_days;type:jsonb;default:'[]'"`
+	Timezone        string                     `json:"timezone" gorm:"column:timezone;default:'Asia/Jakarta'"`
+	CooldownMinutes int32                      `json:"cooldownMinutes" gorm:"column:cooldown_minutes;default:30"`
+	Severity        models.AlertSeverity       `json:"severity" gorm:"column:severity;default:'WARNING'"`
+	IsActive        bool                       `json:"isActive" gorm:"column:is_active;default:true"`
+	CreatedBy       int                        `json:"createdBy" gorm:"column:created_by"`
+	UpdatedBy       *int                       `json:"updatedBy" gorm:"column:updated_by"`
+	CreatedAt       int64                      `json:"createdAt" gorm:"column:created_at;type:bigint;autoCreateTime:milli"`
+	UpdatedAt       int64                      `json:"updatedAt" gorm:"column:updated_at;type:bigint;autoUpdateTime:milli"`
+	DeletedAt       soft_delete.DeletedAt      `json:"deletedAt" gorm:"column:deleted_at;type:bigint;softDelete:milli;default:0"`
+	DeletedBy       *int                       `json:"deletedBy,omitempty" gorm:"column:deleted_by"`
+}
+
+func (GeofenceAlertRuleDB) TableName() string { return "geofence_alert_rules" }
+
+type GeofenceAlertRuleRepository interface {
+	CreateGeofenceAlertRule(ctx context.Context, input *models.CreateGeofenceAlertRuleInput, createdBy int) (*GeofenceAlertRuleDB, error)
+	UpdateGeofenceAlertRule(ctx context.Context, id int32, input *models.UpdateGeofenceAlertRuleInput) (*GeofenceAlertRuleDB, error)
+	DeleteGeofenceAlertRule(ctx context.Context, id int32) error
+	GetGeofenceAlertRuleByID(ctx context.Context, id int32) (*GeofenceAlertRuleDB, error)
+	// geofenceID opsional
+	GetAllGeofenceAlertRules(ctx context.Context, geofenceID *int32) ([]*GeofenceAlertRuleDB, error)
+	PageGeofenceAlertRule(ctx context.Context, pagination models.Pagination) (models.Pagination, error)
+}
+
+// JSONInt64s — custom type untuk daftar angka di kolom JSONB
+type JSONInt64s []int64
+
+func (l JSONInt64s) Value() (driver.Value, error) {
+	if l == nil {
+		return "[]", nil
+	}
+	b, err := json.Marshal(l)
+	return string(b), err
+}
+
+func (l *JSONInt64s) Scan(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		*l = JSONInt64s{}
+		return nil
+	case []byte:
+		return json.Unmarshal(v, l)
+	case string:
+		return json.Unmarshal([]byte(v), l)
+	default:
+		return fmt.Errorf("JSONInt64s: unsupported type %T", value)
+	}
+}
+
+func (l JSONInt64s) Contains(value int64) bool {
+	for _, v := range l {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate memeriksa kelengkapan parameter sesuai trigger dan target
+func (r *GeofenceAlertRuleDB) Validate() error {
+	switch r.Trigger {
+	case models.GeofenceRuleTriggerDwell:
+		if r.DwellMinutes == nil || *r.DwellMinutes <= 0 {
+			return errors.New("dwellMinutes harus lebih dari 0 untuk trigger DWELL")
+		}
+	case models.GeofenceRuleTriggerSpeeding:
+		if r.SpeedKnots == nil || *r.SpeedKnots <= 0 {
+			return errors.New("speedKnots harus lebih dari 0 untuk trigger SPEEDING")
+		}
+	}
+
+	switch r.Target {
+	case models.GeofenceRuleTargetMmsiList:
+		if len(r.MmsiList) == 0 {
+			return errors.New("mmsiList tidak boleh kosong untuk target MMSI_LIST")
+		}
+	case models.GeofenceRuleTargetOwner:
+		if r.OwnerID == nil {
+			return errors.New("ownerId tidak boleh kosong untuk target OWNER")
+		}
+	}
+
+	if (r.ActiveFrom == nil) != (r.ActiveTo == nil) {
+		return errors.New("activeFrom dan activeTo harus diisi bersamaan")
+	}
+	for _, clock := range []*string{r.ActiveFrom, r.ActiveTo} {
+		if clock == nil {
+			continue
+		}
+		if _, err := time.Parse("15:04", *clock); err != nil {
+			return fmt.Errorf("format jam %q tidak valid, gunakan HH:MM", *clock)
+		}
+	}
+	for _, day := range r.ActiveDays {
+		if day < 1 || day > 7 {
+			return fmt.Errorf("activeDays %d tidak valid, gunakan 1 (Senin) sampai 7 (Minggu)", day)
+		}
+	}
+	if _, err := time.LoadLocation(r.Timezone); err != nil {
+		return fmt.Errorf("timezone %q tidak dikenal", r.Timezone)
+	}
+	if r.CooldownMinutes < 0 {
+		return errors.New("cooldownMinutes tidak boleh negatif")
+	}
+
+	return nil
+}
+
+// ActiveAt memeriksa apakah waktu at berada di dalam jendela aktif aturan.
+// Jendela yang melewati tengah malam (mis. 22:00–06:00) dihitung milik hari mulainya.
+func (r *GeofenceAlertRuleDB) ActiveAt(at time.Time) bool {
+	location, err := time.LoadLocation(r.Timezone)
+	if err != nil {
+		location = time.UTC
+	}
+	local := at.In(location)
+
+	if r.ActiveFrom == nil || r.ActiveTo == nil {
+		return r.activeOn(local.Weekday())
+	}
+
+	from, _ := time.Parse("15:04", *r.ActiveFrom)
+	to, _ := time.Parse("15:04", *r.ActiveTo)
+	minute := local.Hour()*60 + local.Minute()
+	start := from.Hour()*60 + from.Minute()
+	end := to.Hour()*60 + to.Minute()
+
+	switch {
+	case start <= end:
+		return minute >= start && minute < end && r.activeOn(local.Weekday())
+	case minute >= start:
+		return r.activeOn(local.Weekday())
+	case minute < end:
+		return r.activeOn(local.AddDate(0, 0, -1).Weekday())
+	}
+	return false
+}
+
+func (r *GeofenceAlertRuleDB) activeOn(day time.Weekday) bool {
+	if len(r.ActiveDays) == 0 {
+		return true
+	}
+	iso := int64(day)
+	if day == time.Sunday {
+		iso = 7
+	}
+	return r.ActiveDays.Contains(iso)
+}
@@ -0,0 +1,188 @@
+package geofences
+
+import (
+	"context"
+	"errors"
+
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/pkg"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"gorm.io/gorm"
+)
+
+type geofenceAlertRuleRepository struct{ db *gorm.DB }
+
+func NewGeofenceAlertRuleRepository(db *gorm.DB) GeofenceAlertRuleRepository {
+	return &geofenceAlertRuleRepository{db}
+}
+
+var _ GeofenceAlertRuleRepository = &geofenceAlertRuleRepository{}
+
+func (r *geofenceAlertRuleRepository) CreateGeofenceAlertRule(ctx context.Context, input *models.CreateGeofenceAlertRuleInput, createdBy int) (*GeofenceAlertRuleDB, error) {
+	rule := &GeofenceAlertRuleDB{
+		Name:            input.Name,
+		Description:     input.Description,
+		GeofenceID:      int32(input.GeofenceID),
+		Trigger:         input.Trigger,
+		DwellMinutes:    int32Ptr(input.DwellMinutes),
+		SpeedKnots:      input.SpeedKnots,
+		Target:          input.Target,
+		MmsiList:        JSONInt64s(input.MmsiList),
+		OwnerID:         int32Ptr(input.OwnerID),
+		ActiveFrom:      input.ActiveFrom,
+		ActiveTo:        input.ActiveTo,
+		ActiveDays:      toInt64s(input.ActiveDays),
+		Timezone:        strOr(input.Timezone, defaultRuleTimezone),
+		CooldownMinutes: int32Or(input.CooldownMinutes, defaultRuleCooldown),
+		Severity:        models.AlertSeverityWarning,
+		IsActive:        boolOr(input.IsActive, true),
+		CreatedBy:       createdBy,
+	}
+	if input.Severity != nil {
+		rule.Severity = *input.Severity
+	}
+
+	if err := r.validate(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	err := r.db.WithContext(ctx).Create(rule).Error
+	return rule, err
+}
+
+func (r *geofenceAlertRuleRepository) UpdateGeofenceAlertRule(ctx context.Context, id int32, input *models.UpdateGeofenceAlertRuleInput) (*GeofenceAlertRuleDB, error) {
+	rule := &GeofenceAlertRuleDB{}
+	if err := r.db.WithContext(ctx).Where("id = ?", id).Take(rule).Error; err != nil {
+		return nil, err
+	}
+	applyRuleUpdate(rule, input)
+
+	if err := r.validate(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	// Save agar isActive=false dan daftar kosong ikut tersimpan
+	err := r.db.WithContext(ctx).Save(rule).Error
+	return rule, err
+}
+
+func (r *geofenceAlertRuleRepository) DeleteGeofenceAlertRule(ctx context.Context, id int32) error {
+	rule := &GeofenceAlertRuleDB{}
+	err := r.db.WithContext(ctx).Where("id = ?", id).Delete(rule).Error
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+func (r *geofenceAlertRuleRepository) GetGeofenceAlertRuleByID(ctx context.Context, id int32) (*GeofenceAlertRuleDB, error) {
+	rule := &GeofenceAlertRuleDB{}
+	err := r.db.WithContext(ctx).Where("id = ? AND deleted_at = 0", id).Take(rule).Error
+	return rule, err
+}
+
+func (r *geofenceAlertRuleRepository) GetAllGeofenceAlertRules(ctx context.Context, geofenceID *int32) ([]*GeofenceAlertRuleDB, error) {
+	var list []*GeofenceAlertRuleDB
+	query := r.db.WithContext(ctx).Where("deleted_at = 0")
+	if geofenceID != nil {
+		query = query.Where("geofence_id = ?", *geofenceID)
+	}
+	err := query.Order("created_at DESC").Find(&list).Error
+	return list, err
+}
+
+func (r *geofenceAlertRuleRepository) PageGeofenceAlertRule(ctx context.Context, pagination models.Pagination) (models.Pagination, error) {
+	var list []GeofenceAlertRuleDB
+	err := r.db.WithContext(ctx).Scopes(pkg.Paginate(&GeofenceAlertRuleDB{}, &pagination, r.db)).Find(&list).Error
+	pagination.Rows = make([]any, len(list))
+	for i, rule := range list {
+		pagination.Rows[i] = rule
+	}
+	return pagination, err
+}
+
+// validate memeriksa parameter aturan dan keberadaan geofence-nya
+func (r *geofenceAlertRuleRepository) validate(ctx context.Context, rule *GeofenceAlertRuleDB) error {
+	if err := rule.Validate(); err != nil {
+		return err
+	}
+
+	err := r.db.WithContext(ctx).Where("id = ? AND deleted_at = 0", rule.GeofenceID).Take(&GeofenceDB{}).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return errors.New("geofence tidak ditemukan")
+	}
+	return err
+}
+
+// ─── helpers ───────────────────────────────────────────────────
+
+func applyRuleUpdate(rule *GeofenceAlertRuleDB, input *models.UpdateGeofenceAlertRuleInput) {
+	if input.Name != nil {
+		rule.Name = *input.Name
+	}
+	if input.Description != nil {
+		rule.Description = input.Description
+	}
+	if input.GeofenceID != nil {
+		rule.GeofenceID = int32(*input.GeofenceID)
+	}
+	if input.Trigger != nil {
+		rule.Trigger = *input.Trigger
+	}
+	if input.DwellMinutes != nil {
+		rule.DwellMinutes = int32Ptr(input.DwellMinutes)
+	}
+	if input.SpeedKnots != nil {
+		rule.SpeedKnots = input.SpeedKnots
+	}
+	if input.Target != nil {
+		rule.Target = *input.Target
+	}
+	if input.MmsiList != nil {
+		rule.MmsiList = JSONInt64s(input.MmsiList)
+	}
+	if input.OwnerID != nil {
+		rule.OwnerID = int32Ptr(input.OwnerID)
+	}
+	if input.ActiveFrom != nil {
+		rule.ActiveFrom = input.ActiveFrom
+	}
+	if input.ActiveTo != nil {
+		rule.ActiveTo = input.ActiveTo
+	}
+	// string kosong menghapus jendela jam aktif
+	if rule.ActiveFrom != nil && rule.ActiveTo != nil && *rule.ActiveFrom == "" && *rule.ActiveTo == "" {
+		rule.ActiveFrom, rule.ActiveTo = nil, nil
+	}
+	if input.ActiveDays != nil {
+		rule.ActiveDays = toInt64s(input.ActiveDays)
+	}
+	if input.Timezone != nil {
+		rule.Timezone = *input.Timezone
+	}
+	if input.CooldownMinutes != nil {
+		rule.CooldownMinutes = int32(*input.CooldownMinutes)
+	}
+	if input.Severity != nil {
+		rule.Severity = *input.Severity
+	}
+	if input.IsActive != nil {
+		rule.IsActive = *input.IsActive
+	}
+}
+
+func int32Ptr(v *int) *int32 {
+	if v == nil {
+		return nil
+	}
+	value := int32(*v)
+	return &value
+}
+
+func toInt64s(values []int) JSONInt64s {
+	list := make(JSONInt64s, len(values))
+	for i, v := range values {
+		list[i] = int64(v)
+	}
+	return list
+}
@@ -0,0 +1,332 @@
+package geofences
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/ais"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+const (
+	defaultDwell           = 10 * time.Minute
+	defaultRefreshInterval = time.Minute
+	engineBuffer           = 1024
+	// lastSeen vessel yang tidak mengirim posisi selama ini dibuang dari memori
+	lastSeenRetention = 24 * time.Hour
+)
+
+// PositionSource adalah sumber posisi realtime (lihat ais.Ingestor)
+type PositionSource interface {
+	SubscribePositions(buffer int) (<-chan *ais.Document, func())
+}
+
+// EngineConfig dibaca dari environment:
+//
+//	GEOFENCE_DWELL_SECONDS      lama di dalam geofence sebelum event DWELL, default 600
+//	GEOFENCE_REFRESH_INTERVAL   interval memuat ulang geofence aktif, default "1m"
+type EngineConfig struct {
+	Dwell           time.Duration
+	RefreshInterval time.Duration
+}
+
+// LoadEngineConfig membaca konfigurasi engine geofence dari environment
+func LoadEngineConfig() (EngineConfig, error) {
+	config := EngineConfig{Dwell: defaultDwell, RefreshInterval: defaultRefreshInterval}
+
+	if raw := strings.TrimSpace(os.Getenv("GEOFENCE_DWELL_SECONDS")); raw != "" {
+		seconds, err := strconv.Atoi(raw)
+		if err != nil || seconds <= 0 {
+			return config, fmt.Errorf("invalid GEOFENCE_DWELL_SECONDS: %q", raw)
+		}
+		config.Dwell = time.Duration(seconds) * time.Second
+	}
+
+	if raw := strings.TrimSpace(os.Getenv("GEOFENCE_REFRESH_INTERVAL")); raw != "" {
+		interval, err := time.ParseDuration(raw)
+		if err != nil || interval <= 0 {
+			return config, fmt.Errorf("invalid GEOFENCE_REFRESH_INTERVAL: %q", raw)
+		}
+		config.RefreshInterval = interval
+	}
+
+	return config, nil
+}
+
+type fence struct {
+	*GeofenceDB
+	box    geo.BoundingBox
+	hasBox bool
+}
+
+type presenceKey struct {
+	geofenceID int32
+	subject    string
+}
+
+type presence struct {
+	enteredAt    time.Time
+	dwellEmitted bool
+}
+
+// observation adalah hasil evaluasi satu posisi, diteruskan ke RuleEvaluator
+type observation struct {
+	subject string
+	mmsi    *int64
+	imei    *string
+	point   geo.Point
+	sog     *float64
+	at      time.Time
+	events  []*models.GeofenceEvent
+	// geofence yang sedang dimasuki, per ID geofence
+	inside map[int32]visit
+}
+
+type visit struct {
+	geofence  *GeofenceDB
+	enteredAt time.Time
+}
+
+// Engine mengevaluasi setiap posisi AIS/perangkat yang masuk terhadap geofence
+// aktif dan mencatat event ENTER/EXIT/DWELL. State inside/outside disimpan di
+// memori dan dipulihkan dari event terakhir di geofence_events saat start.
+// Bila rules diisi, setiap hasil evaluasi juga dicocokkan ke GeofenceAlertRule.
+type Engine struct {
+	geofenceRepository GeofenceRepository
+	eventRepository    GeofenceEventRepository
+	positions          PositionSource
+	rules              *RuleEvaluator
+	config             EngineConfig
+
+	fences    []*fence
+	presences map[presenceKey]*presence
+	lastSeen  map[string]time.Time
+}
+
+func NewEngine(geofenceRepository GeofenceRepository, eventRepository GeofenceEventRepository, positions PositionSource, rules *RuleEvaluator, config EngineConfig) *Engine {
+	return &Engine{
+		geofenceRepository: geofenceRepository,
+		eventRepository:    eventRepository,
+		positions:          positions,
+		rules:              rules,
+		config:             config,
+		presences:          make(map[presenceKey]*presence),
+		lastSeen:           make(map[string]time.Time),
+	}
+}
+
+func (e *Engine) Start(ctx context.Context) {
+	if err := e.refresh(ctx); err != nil {
+		log.Printf("geofence engine: load geofences failed: %v", err)
+	}
+	if err := e.restore(ctx); err != nil {
+		log.Printf("geofence engine: restore state failed: %v", err)
+	}
+
+	positions, unsubscribe := e.positions.SubscribePositions(engineBuffer)
+	defer unsubscribe()
+
+	ticker := time.NewTicker(e.config.RefreshInterval)
+	defer ticker.Stop()
+
+	log.Printf("geofence engine started with %d active geofence(s)", len(e.fences))
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case doc, ok := <-positions:
+			if !ok {
+				return
+			}
+			obs := e.evaluate(doc)
+			if obs == nil {
+				continue
+			}
+			if len(obs.events) > 0 {
+				if err := e.eventRepository.CreateGeofenceEvents(ctx, obs.events); err != nil {
+					log.Printf("geofence engine: store events failed: %v", err)
+				}
+			}
+			if e.rules != nil {
+				e.rules.evaluate(ctx, obs)
+			}
+		case <-ticker.C:
+			if err := e.refresh(ctx); err != nil {
+				log.Printf("geofence engine: load geofences failed: %v", err)
+			}
+		}
+	}
+}
+
+// refresh memuat ulang geofence aktif; state geofence yang dihapus/nonaktif ikut dibuang
+func (e *Engine) refresh(ctx context.Context) error {
+	list, err := e.geofenceRepository.GetAllGeofences(ctx)
+	if err != nil {
+		return err
+	}
+
+	fences := make([]*fence, 0, len(list))
+	active := make(map[int32]bool, len(list))
+	for _, g := range list {
+		if !g.IsActive {
+			continue
+		}
+		f := &fence{GeofenceDB: g}
+		f.box, f.hasBox = g.BoundingBox()
+		fences = append(fences, f)
+		active[g.ID] = true
+	}
+	e.fences = fences
+
+	if e.rules != nil {
+		if err := e.rules.refresh(ctx); err != nil {
+			log.Printf("geofence engine: load alert rules failed: %v", err)
+		}
+	}
+
+	for key := range e.presences {
+		if !active[key.geofenceID] {
+			delete(e.presences, key)
+		}
+	}
+	for subject, seen := range e.lastSeen {
+		if time.Since(seen) > lastSeenRetention {
+			delete(e.lastSeen, subject)
+		}
+	}
+
+	return nil
+}
+
+// restore membangun ulang state inside dari event terakhir (ENTER/DWELL tanpa EXIT)
+func (e *Engine) restore(ctx context.Context) error {
+	events, err := e.eventRepository.GetLatestGeofenceEvents(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, event := range events {
+		if event.Kind == models.GeofenceEventKindExit {
+			continue
+		}
+		subject := subjectKey(event.Mmsi, event.DeviceImei)
+		if subject == "" {
+			continue
+		}
+		state := &presence{enteredAt: time.UnixMilli(event.OccurredAt)}
+		if event.Kind == models.GeofenceEventKindDwell {
+			state.dwellEmitted = true
+			if event.DwellSeconds != nil {
+				state.enteredAt = state.enteredAt.Add(-time.Duration(*event.DwellSeconds) * time.Second)
+			}
+		}
+		e.presences[presenceKey{geofenceID: int32(event.GeofenceID), subject: subject}] = state
+	}
+
+	return nil
+}
+
+// evaluate membandingkan satu posisi dengan semua geofence aktif
+func (e *Engine) evaluate(doc *ais.Document) *observation {
+	lat, lon, ok := doc.Position()
+	if !ok {
+		return nil
+	}
+
+	var mmsi *int64
+	if doc.MMSI > 0 {
+		mmsi = &doc.MMSI
+	}
+	var imei *string
+	if doc.Imei != "" {
+		imei = &doc.Imei
+	}
+	subject := subjectKey(mmsi, imei)
+	if subject == "" {
+		return nil
+	}
+
+	// posisi yang datang terlambat tidak boleh membalik state
+	if last, ok := e.lastSeen[subject]; ok && doc.TS.Before(last) {
+		return nil
+	}
+	e.lastSeen[subject] = doc.TS
+
+	p := geo.Point{Lat: lat, Lon: lon}
+	obs := &observation{
+		subject: subject,
+		mmsi:    mmsi,
+		imei:    imei,
+		point:   p,
+		at:      doc.TS,
+		inside:  make(map[int32]visit),
+	}
+	if sog, ok := doc.Sog(); ok {
+		obs.sog = &sog
+	}
+
+	var events []*models.GeofenceEvent
+	for _, f := range e.fences {
+		inside := (!f.hasBox || f.box.Contains(p)) && f.Contains(p)
+		key := presenceKey{geofenceID: f.ID, subject: subject}
+		state, wasInside := e.presences[key]
+
+		switch {
+		case inside && !wasInside:
+			e.presences[key] = &presence{enteredAt: doc.TS}
+			events = append(events, newGeofenceEvent(f.GeofenceDB, models.GeofenceEventKindEnter, mmsi, imei, p, doc.TS, nil))
+		case inside && !state.dwellEmitted && doc.TS.Sub(state.enteredAt) >= e.config.Dwell:
+			state.dwellEmitted = true
+			dwell := int(doc.TS.Sub(state.enteredAt).Seconds())
+			events = append(events, newGeofenceEvent(f.GeofenceDB, models.GeofenceEventKindDwell, mmsi, imei, p, doc.TS, &dwell))
+		case !inside && wasInside:
+			delete(e.presences, key)
+			dwell := int(doc.TS.Sub(state.enteredAt).Seconds())
+			events = append(events, newGeofenceEvent(f.GeofenceDB, models.GeofenceEventKindExit, mmsi, imei, p, doc.TS, &dwell))
+		}
+		if inside {
+			obs.inside[f.ID] = visit{geofence: f.GeofenceDB, enteredAt: e.presences[key].enteredAt}
+		}
+	}
+	obs.events = events
+
+	return obs
+}
+
+func newGeofenceEvent(g *GeofenceDB, kind models.GeofenceEventKind, mmsi *int64, imei *string, p geo.Point, at time.Time, dwellSeconds *int) *models.GeofenceEvent {
+	event := &models.GeofenceEvent{
+		GeofenceID:   int(g.ID),
+		GeofenceName: g.Name,
+		Kind:         kind,
+		Latitude:     p.Lat,
+		Longitude:    p.Lon,
+		OccurredAt:   at.UnixMilli(),
+		DwellSeconds: dwellSeconds,
+	}
+	if mmsi != nil {
+		value := *mmsi
+		event.Mmsi = &value
+	}
+	if imei != nil {
+		value := *imei
+		event.DeviceImei = &value
+	}
+	return event
+}
+
+// subjectKey — vessel diidentifikasi dengan MMSI, perangkat tanpa MMSI dengan IMEI
+func subjectKey(mmsi *int64, imei *string) string {
+	if mmsi != nil && *mmsi > 0 {
+		return fmt.Sprintf("mmsi:%d", *mmsi)
+	}
+	if imei != nil && *imei != "" {
+		return "imei:" + *imei
+	}
+	return ""
+}
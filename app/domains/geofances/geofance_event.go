@@ -0,0 +1,16 @@
+package geofences
+
+import (
+	"context"
+
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+type GeofenceEventRepository interface {
+	CreateGeofenceEvents(ctx context.Context, events []*models.GeofenceEvent) error
+	// start dan end dalam epoch ms; geofenceID dan mmsi opsional
+	GetGeofenceEvents(ctx context.Context, geofenceID *int, mmsi *int64, start int64, end int64) ([]*models.GeofenceEvent, error)
+	// GetLatestGeofenceEvents mengembalikan event terakhir per geofence dan vessel/perangkat
+	GetLatestGeofenceEvents(ctx context.Context) ([]*models.GeofenceEvent, error)
+	PageGeofenceEvent(ctx context.Context, pagination models.Pagination) (models.Pagination, error)
+}
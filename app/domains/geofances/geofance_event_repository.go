@@ -0,0 +1,58 @@
+package geofences
+
+import (
+	"context"
+
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/pkg"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"gorm.io/gorm"
+)
+
+type geofenceEventRepository struct{ db *gorm.DB }
+
+func NewGeofenceEventRepository(db *gorm.DB) GeofenceEventRepository {
+	return &geofenceEventRepository{db}
+}
+
+var _ GeofenceEventRepository = &geofenceEventRepository{}
+
+func (r *geofenceEventRepository) CreateGeofenceEvents(ctx context.Context, events []*models.GeofenceEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return r.db.WithContext(ctx).Create(&events).Error
+}
+
+func (r *geofenceEventRepository) GetGeofenceEvents(ctx context.Context, geofenceID *int, mmsi *int64, start int64, end int64) ([]*models.GeofenceEvent, error) {
+	var events []*models.GeofenceEvent
+	query := r.db.WithContext(ctx).Where("occurred_at BETWEEN ? AND ?", start, end)
+	if geofenceID != nil {
+		query = query.Where("geofence_id = ?", *geofenceID)
+	}
+	if mmsi != nil {
+		query = query.Where("mmsi = ?", *mmsi)
+	}
+	err := query.Order("occurred_at ASC, id ASC").Find(&events).Error
+	return events, err
+}
+
+func (r *geofenceEventRepository) GetLatestGeofenceEvents(ctx context.Context) ([]*models.GeofenceEvent, error) {
+	var events []*models.GeofenceEvent
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT DISTINCT ON (geofence_id, mmsi, device_imei) *
+		FROM geofence_events
+		WHERE deleted_at = 0
+		ORDER BY geofence_id, mmsi, device_imei, occurred_at DESC, id DESC
+	`).Scan(&events).Error
+	return events, err
+}
+
+func (r *geofenceEventRepository) PageGeofenceEvent(ctx context.Context, pagination models.Pagination) (models.Pagination, error) {
+	var list []models.GeofenceEvent
+	err := r.db.WithContext(ctx).Scopes(pkg.Paginate(&models.GeofenceEvent{}, &pagination, r.db)).Find(&list).Error
+	pagination.Rows = make([]any, len(list))
+	for i, event := range list {
+		pagination.Rows[i] = event
+	}
+	return pagination, err
+}
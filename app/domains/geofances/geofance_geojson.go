@@ -0,0 +1,159 @@
+package geofences
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// geoJSONObject mencakup FeatureCollection, Feature, dan geometry telanjang
+type geoJSONObject struct {
+	Type        string                 `json:"type"`
+	ID          interface{}            `json:"id,omitempty"`
+	Features    []*geoJSONObject       `json:"features,omitempty"`
+	Geometry    *geoJSONObject         `json:"geometry,omitempty"`
+	Properties  map[string]interface{} `json:"properties,omitempty"`
+	Coordinates interface{}            `json:"coordinates,omitempty"`
+}
+
+// encodeGeoJSON menulis geofence sebagai FeatureCollection; circle ditulis
+// sebagai Point dengan properti radius (meter)
+func encodeGeoJSON(list []*GeofenceDB) ([]byte, error) {
+	collection := &geoJSONObject{Type: "FeatureCollection", Features: make([]*geoJSONObject, 0, len(list))}
+	for _, g := range list {
+		properties := map[string]interface{}{
+			"id":          g.ID,
+			"uuid":        g.UUID,
+			"name":        g.Name,
+			"type":        g.Type,
+			"color":       g.Color,
+			"fillColor":   g.FillColor,
+			"strokeWidth": g.StrokeWidth,
+			"isActive":    g.IsActive,
+		}
+		if g.Description != nil {
+			properties["description"] = *g.Description
+		}
+		if g.Radius != nil {
+			properties["radius"] = *g.Radius
+		}
+
+		collection.Features = append(collection.Features, &geoJSONObject{
+			Type:       "Feature",
+			ID:         g.UUID,
+			Geometry:   &geoJSONObject{Type: g.GeoType, Coordinates: exportCoordinates(g)},
+			Properties: properties,
+		})
+	}
+	return json.MarshalIndent(collection, "", "  ")
+}
+
+// decodeGeoJSON membaca FeatureCollection, Feature tunggal, atau geometry telanjang
+func decodeGeoJSON(data []byte) ([]*geofenceFeature, error) {
+	root := &geoJSONObject{}
+	if err := json.Unmarshal(data, root); err != nil {
+		return nil, fmt.Errorf("GeoJSON tidak valid: %v", err)
+	}
+
+	var objects []*geoJSONObject
+	switch root.Type {
+	case "FeatureCollection":
+		objects = root.Features
+	case "Feature":
+		objects = []*geoJSONObject{root}
+	case "":
+		return nil, errors.New("GeoJSON tidak memiliki type")
+	default:
+		objects = []*geoJSONObject{{Type: "Feature", Geometry: root}}
+	}
+
+	features := make([]*geofenceFeature, 0, len(objects))
+	for _, object := range objects {
+		features = append(features, geoJSONFeature(object))
+	}
+	return features, nil
+}
+
+// geoJSONFeature memetakan properti Feature ke input geofence; kunci
+// simplestyle (stroke, fill, stroke-width) dipakai bila kunci aplikasi tidak ada
+func geoJSONFeature(object *geoJSONObject) *geofenceFeature {
+	feature := &geofenceFeature{}
+	if object == nil || object.Type != "Feature" {
+		feature.errors = append(feature.errors, "bukan GeoJSON Feature")
+		return feature
+	}
+	props := object.Properties
+	input := &feature.input
+
+	input.Name = propString(props, "name", "title")
+	input.Description = nonEmpty(propString(props, "description"))
+	input.Color = nonEmpty(propString(props, "color", "stroke"))
+	input.FillColor = nonEmpty(propString(props, "fillColor"))
+	if input.FillColor == nil {
+		input.FillColor = nonEmpty(withOpacity(propString(props, "fill"), props["fill-opacity"]))
+	}
+	if width, ok := propNumber(props, "strokeWidth", "stroke-width"); ok {
+		strokeWidth := int(math.Round(width))
+		input.StrokeWidth = &strokeWidth
+	}
+	if radius, ok := propNumber(props, "radius"); ok {
+		input.Radius = &radius
+	}
+	if active, ok := props["isActive"].(bool); ok {
+		input.IsActive = &active
+	}
+
+	if object.Geometry == nil {
+		feature.errors = append(feature.errors, "Feature tidak memiliki geometry")
+		return feature
+	}
+	switch object.Geometry.Type {
+	case "Point", "LineString", "Polygon":
+	default:
+		feature.errors = append(feature.errors, fmt.Sprintf("geometry %s tidak didukung, gunakan Point, LineString, atau Polygon", object.Geometry.Type))
+		return feature
+	}
+	input.GeoType = object.Geometry.Type
+	input.Coordinates = object.Geometry.Coordinates
+	input.Type = propString(props, "type")
+	if input.Type == "" {
+		input.Type = typeForGeoType(input.GeoType)
+	}
+	return feature
+}
+
+func propString(props map[string]interface{}, keys ...string) string {
+	for _, key := range keys {
+		if v, ok := props[key].(string); ok && strings.TrimSpace(v) != "" {
+			return strings.TrimSpace(v)
+		}
+	}
+	return ""
+}
+
+func propNumber(props map[string]interface{}, keys ...string) (float64, bool) {
+	for _, key := range keys {
+		switch v := props[key].(type) {
+		case float64:
+			return v, true
+		case string:
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				return f, true
+			}
+		}
+	}
+	return 0, false
+}
+
+// withOpacity menambahkan alpha fill-opacity (0..1) ke warna "#rrggbb"
+func withOpacity(color string, opacity interface{}) string {
+	alpha, ok := opacity.(float64)
+	if color == "" || !ok || len(color) != 7 {
+		return color
+	}
+	alpha = math.Max(0, math.Min(1, alpha))
+	return fmt.Sprintf("%s%02X", color, int(math.Round(alpha*255)))
+}
@@ -0,0 +1,150 @@
+package geofences
+
+import (
+	"math"
+
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
+)
+
+// Rings mengubah Coordinates menjadi ring polygon. Menerima bentuk GeoJSON
+// Polygon ([[[lon,lat],...]]) maupun satu ring saja ([[lon,lat],...]).
+func (g *GeofenceDB) Rings() [][]geo.Point {
+	var rings [][]geo.Point
+	for _, item := range g.Coordinates {
+		if pt, ok := toPoint(item); ok {
+			// satu ring tanpa pembungkus
+			if len(rings) == 0 {
+				rings = append(rings, nil)
+			}
+			rings[0] = append(rings[0], pt)
+			continue
+		}
+		ringRaw, ok := item.([]interface{})
+		if !ok {
+			continue
+		}
+		var ring []geo.Point
+		for _, p := range ringRaw {
+			if pt, ok := toPoint(p); ok {
+				ring = append(ring, pt)
+			}
+		}
+		if len(ring) > 0 {
+			rings = append(rings, ring)
+		}
+	}
+	return rings
+}
+
+// Center mengembalikan titik pusat geofence circle (GeoJSON Point [lon,lat])
+func (g *GeofenceDB) Center() (geo.Point, bool) {
+	return toPoint([]interface{}(g.Coordinates))
+}
+
+// IsCircle — geofence berupa titik pusat + radius (meter)
+func (g *GeofenceDB) IsCircle() bool {
+	return g.Type == "circle" || g.GeoType == "Point"
+}
+
+// IsLine — geofence berupa polyline; Radius (meter) dipakai sebagai lebar buffer
+func (g *GeofenceDB) IsLine() bool {
+	return g.GeoType == "LineString" || g.Type == "line"
+}
+
+// Line mengembalikan titik-titik polyline ([[lon,lat],...])
+func (g *GeofenceDB) Line() []geo.Point {
+	rings := g.Rings()
+	if len(rings) == 0 {
+		return nil
+	}
+	return rings[0]
+}
+
+// Contains menguji apakah titik berada di dalam geofence.
+// Geofence line tanpa Radius tidak punya area sehingga selalu false.
+func (g *GeofenceDB) Contains(p geo.Point) bool {
+	switch {
+	case g.IsCircle():
+		center, ok := g.Center()
+		if !ok || g.Radius == nil {
+			return false
+		}
+		return geo.DistanceMeters(center, p) <= *g.Radius
+	case g.IsLine():
+		if g.Radius == nil || *g.Radius <= 0 {
+			return false
+		}
+		return geo.DistanceToLineMeters(p, g.Line()) <= *g.Radius
+	case g.Type == "rectangle":
+		// rectangle boleh disimpan sebagai dua sudut [[minLon,minLat],[maxLon,maxLat]]
+		if rings := g.Rings(); len(rings) == 1 && len(rings[0]) == 2 {
+			a, b := rings[0][0], rings[0][1]
+			box := geo.BoundingBox{
+				MinLat: math.Min(a.Lat, b.Lat), MinLon: math.Min(a.Lon, b.Lon),
+				MaxLat: math.Max(a.Lat, b.Lat), MaxLon: math.Max(a.Lon, b.Lon),
+			}
+			return box.Contains(p)
+		}
+	}
+	return geo.PointInPolygon(p, g.Rings())
+}
+
+// BoundingBox geofence, dipakai untuk pre-filter query
+func (g *GeofenceDB) BoundingBox() (geo.BoundingBox, bool) {
+	if g.IsCircle() {
+		center, ok := g.Center()
+		if !ok || g.Radius == nil {
+			return geo.BoundingBox{}, false
+		}
+		north := geo.Destination(center, 0, *g.Radius)
+		east := geo.Destination(center, 90, *g.Radius)
+		south := geo.Destination(center, 180, *g.Radius)
+		west := geo.Destination(center, 270, *g.Radius)
+		return geo.BoundingBox{MinLat: south.Lat, MinLon: west.Lon, MaxLat: north.Lat, MaxLon: east.Lon}, true
+	}
+
+	rings := g.Rings()
+	if len(rings) == 0 || len(rings[0]) == 0 {
+		return geo.BoundingBox{}, false
+	}
+	box := geo.BoundingBox{MinLat: 90, MinLon: 180, MaxLat: -90, MaxLon: -180}
+	for _, p := range rings[0] {
+		if p.Lat < box.MinLat {
+			box.MinLat = p.Lat
+		}
+		if p.Lat > box.MaxLat {
+			box.MaxLat = p.Lat
+		}
+		if p.Lon < box.MinLon {
+			box.MinLon = p.Lon
+		}
+		if p.Lon > box.MaxLon {
+			box.MaxLon = p.Lon
+		}
+	}
+
+	// buffer line melebar ke segala arah; pakai lintang terjauh dari ekuator untuk lebar bujur
+	if g.IsLine() && g.Radius != nil && *g.Radius > 0 {
+		edge := geo.BoundingBoxAround(geo.Point{Lat: math.Max(math.Abs(box.MinLat), math.Abs(box.MaxLat))}, *g.Radius)
+		dLat := *g.Radius / geo.EarthRadiusMeters * 180 / math.Pi
+		box.MinLat = math.Max(-90, box.MinLat-dLat)
+		box.MaxLat = math.Min(90, box.MaxLat+dLat)
+		box.MinLon = math.Max(-180, box.MinLon-edge.MaxLon)
+		box.MaxLon = math.Min(180, box.MaxLon+edge.MaxLon)
+	}
+	return box, true
+}
+
+// toPoint membaca pasangan [lon, lat] GeoJSON
+func toPoint(v interface{}) (geo.Point, bool) {
+	pair, ok := v.([]interface{})
+	if !ok || len(pair) < 2 {
+		return geo.Point{}, false
+	}
+	lon, ok1 := pair[0].(float64)
+	lat, ok2 := pair[1].(float64)
+	if !ok1 || !ok2 {
+		return geo.Point{}, false
+	}
+	return geo.Point{Lat: lat, Lon: lon}, true
+}
@@ -0,0 +1,361 @@
+package geofences
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const kmlNamespace = "http://www.opengis.net/kml/2.2"
+
+type kmlRoot struct {
+	XMLName  xml.Name    `xml:"kml"`
+	Xmlns    string      `xml:"xmlns,attr"`
+	Document kmlDocument `xml:"Document"`
+}
+
+type kmlDocument struct {
+	Name       string          `xml:"name"`
+	Placemarks []*kmlPlacemark `xml:"Placemark"`
+}
+
+type kmlPlacemark struct {
+	Name          string           `xml:"name"`
+	Description   string           `xml:"description,omitempty"`
+	StyleURL      string           `xml:"styleUrl,omitempty"`
+	Style         *kmlStyle        `xml:"Style,omitempty"`
+	ExtendedData  *kmlExtendedData `xml:"ExtendedData,omitempty"`
+	Point         *kmlCoordinates  `xml:"Point,omitempty"`
+	LineString    *kmlCoordinates  `xml:"LineString,omitempty"`
+	Polygon       *kmlPolygon      `xml:"Polygon,omitempty"`
+	MultiGeometry *struct{}        `xml:"MultiGeometry,omitempty"`
+}
+
+type kmlStyle struct {
+	ID        string        `xml:"id,attr,omitempty"`
+	LineStyle *kmlLineStyle `xml:"LineStyle,omitempty"`
+	PolyStyle *kmlPolyStyle `xml:"PolyStyle,omitempty"`
+}
+
+type kmlLineStyle struct {
+	Color string  `xml:"color,omitempty"`
+	Width float64 `xml:"width,omitempty"`
+}
+
+type kmlPolyStyle struct {
+	Color string `xml:"color,omitempty"`
+}
+
+type kmlExtendedData struct {
+	Data []kmlData `xml:"Data"`
+}
+
+type kmlData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type kmlCoordinates struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type kmlPolygon struct {
+	Outer kmlBoundary   `xml:"outerBoundaryIs"`
+	Inner []kmlBoundary `xml:"innerBoundaryIs"`
+}
+
+type kmlBoundary struct {
+	LinearRing kmlCoordinates `xml:"LinearRing"`
+}
+
+// encodeKML menulis satu Placemark per geofence dengan Style inline;
+// type, radius dan isActive disimpan di ExtendedData
+func encodeKML(list []*GeofenceDB) ([]byte, error) {
+	root := &kmlRoot{Xmlns: kmlNamespace, Document: kmlDocument{Name: "Geofences"}}
+	for _, g := range list {
+		placemark := &kmlPlacemark{
+			Name: g.Name,
+			Style: &kmlStyle{
+				LineStyle: &kmlLineStyle{Color: toKMLColor(g.Color), Width: float64(g.StrokeWidth)},
+				PolyStyle: &kmlPolyStyle{Color: toKMLColor(g.FillColor)},
+			},
+			ExtendedData: &kmlExtendedData{Data: []kmlData{
+				{Name: "type", Value: g.Type},
+				{Name: "isActive", Value: strconv.FormatBool(g.IsActive)},
+			}},
+		}
+		if g.Description != nil {
+			placemark.Description = *g.Description
+		}
+		if g.Radius != nil {
+			placemark.ExtendedData.Data = append(placemark.ExtendedData.Data, kmlData{Name: "radius", Value: strconv.FormatFloat(*g.Radius, 'f', -1, 64)})
+		}
+
+		switch coords := exportCoordinates(g).(type) {
+		case []float64:
+			placemark.Point = &kmlCoordinates{Coordinates: formatKMLCoordinates([][]float64{coords})}
+		case [][]float64:
+			placemark.LineString = &kmlCoordinates{Coordinates: formatKMLCoordinates(coords)}
+		case [][][]float64:
+			if len(coords) == 0 {
+				continue
+			}
+			placemark.Polygon = &kmlPolygon{Outer: kmlBoundary{kmlCoordinates{formatKMLCoordinates(coords[0])}}}
+			for _, hole := range coords[1:] {
+				placemark.Polygon.Inner = append(placemark.Polygon.Inner, kmlBoundary{kmlCoordinates{formatKMLCoordinates(hole)}})
+			}
+		default:
+			continue
+		}
+		root.Document.Placemarks = append(root.Document.Placemarks, placemark)
+	}
+
+	body, err := xml.MarshalIndent(root, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), body...), nil
+}
+
+// encodeKMZ membungkus KML sebagai doc.kml di dalam zip
+func encodeKMZ(list []*GeofenceDB) ([]byte, error) {
+	kml, err := encodeKML(list)
+	if err != nil {
+		return nil, err
+	}
+
+	buf := &bytes.Buffer{}
+	archive := zip.NewWriter(buf)
+	w, err := archive.Create("doc.kml")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(kml); err != nil {
+		return nil, err
+	}
+	if err := archive.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeKMZ membaca file .kml pertama di dalam zip (biasanya doc.kml)
+func decodeKMZ(data []byte) ([]*geofenceFeature, error) {
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("KMZ tidak valid: %v", err)
+	}
+
+	for _, f := range archive.File {
+		if !strings.EqualFold(f.Name[strings.LastIndex(f.Name, ".")+1:], "kml") {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		kml, err := io.ReadAll(io.LimitReader(rc, maxImportBytes+1))
+		rc.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(kml) > maxImportBytes {
+			return nil, fmt.Errorf("isi KMZ terlalu besar, maksimal %d MB", maxImportBytes>>20)
+		}
+		return decodeKML(kml)
+	}
+	return nil, errors.New("KMZ tidak berisi file .kml")
+}
+
+// decodeKML mencari Placemark di seluruh dokumen (termasuk di dalam Folder)
+// dan menyelesaikan styleUrl ke Style yang didefinisikan di dokumen
+func decodeKML(data []byte) ([]*geofenceFeature, error) {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	styles := make(map[string]*kmlStyle)
+	var placemarks []*kmlPlacemark
+
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("KML tidak valid: %v", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch start.Name.Local {
+		case "Style":
+			style := &kmlStyle{}
+			if err := decoder.DecodeElement(style, &start); err != nil {
+				return nil, fmt.Errorf("KML tidak valid: %v", err)
+			}
+			if style.ID != "" {
+				styles["#"+style.ID] = style
+			}
+		case "Placemark":
+			placemark := &kmlPlacemark{}
+			if err := decoder.DecodeElement(placemark, &start); err != nil {
+				return nil, fmt.Errorf("KML tidak valid: %v", err)
+			}
+			placemarks = append(placemarks, placemark)
+		}
+	}
+
+	features := make([]*geofenceFeature, 0, len(placemarks))
+	for _, placemark := range placemarks {
+		style := placemark.Style
+		if style == nil {
+			style = styles[placemark.StyleURL]
+		}
+		features = append(features, kmlFeature(placemark, style))
+	}
+	return features, nil
+}
+
+func kmlFeature(placemark *kmlPlacemark, style *kmlStyle) *geofenceFeature {
+	feature := &geofenceFeature{}
+	input := &feature.input
+	input.Name = strings.TrimSpace(placemark.Name)
+	input.Description = nonEmpty(strings.TrimSpace(placemark.Description))
+
+	if style != nil && style.LineStyle != nil {
+		input.Color = nonEmpty(fromKMLColor(style.LineStyle.Color, false))
+		if style.LineStyle.Width > 0 {
+			width := int(style.LineStyle.Width + 0.5)
+			input.StrokeWidth = &width
+		}
+	}
+	if style != nil && style.PolyStyle != nil {
+		input.FillColor = nonEmpty(fromKMLColor(style.PolyStyle.Color, true))
+	}
+
+	if placemark.ExtendedData != nil {
+		for _, data := range placemark.ExtendedData.Data {
+			value := strings.TrimSpace(data.Value)
+			switch data.Name {
+			case "type":
+				input.Type = value
+			case "radius":
+				radius, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					feature.errors = append(feature.errors, "radius: bukan angka")
+					continue
+				}
+				input.Radius = &radius
+			case "isActive":
+				if active, err := strconv.ParseBool(value); err == nil {
+					input.IsActive = &active
+				}
+			}
+		}
+	}
+
+	var err error
+	switch {
+	case placemark.Polygon != nil:
+		input.GeoType = "Polygon"
+		var ring []interface{}
+		ring, err = parseKMLCoordinates(placemark.Polygon.Outer.LinearRing.Coordinates)
+		rings := []interface{}{ring}
+		for _, boundary := range placemark.Polygon.Inner {
+			if err != nil {
+				break
+			}
+			ring, err = parseKMLCoordinates(boundary.LinearRing.Coordinates)
+			rings = append(rings, ring)
+		}
+		input.Coordinates = rings
+	case placemark.LineString != nil:
+		input.GeoType = "LineString"
+		input.Coordinates, err = parseKMLCoordinates(placemark.LineString.Coordinates)
+	case placemark.Point != nil:
+		input.GeoType = "Point"
+		var points []interface{}
+		points, err = parseKMLCoordinates(placemark.Point.Coordinates)
+		if err == nil && len(points) != 1 {
+			err = errors.New("Point harus berisi tepat satu koordinat")
+		}
+		if err == nil {
+			input.Coordinates = points[0]
+		}
+	case placemark.MultiGeometry != nil:
+		feature.errors = append(feature.errors, "geometry MultiGeometry tidak didukung, gunakan Point, LineString, atau Polygon")
+		return feature
+	default:
+		feature.errors = append(feature.errors, "Placemark tidak memiliki geometry")
+		return feature
+	}
+	if err != nil {
+		feature.errors = append(feature.errors, "coordinates: "+err.Error())
+	}
+
+	if input.Type == "" {
+		input.Type = typeForGeoType(input.GeoType)
+	}
+	return feature
+}
+
+// parseKMLCoordinates membaca tuple "lon,lat[,alt]" yang dipisah spasi
+func parseKMLCoordinates(text string) ([]interface{}, error) {
+	positions := make([]interface{}, 0)
+	for _, tuple := range strings.Fields(text) {
+		parts := strings.Split(tuple, ",")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("tuple %q harus berupa lon,lat[,alt]", tuple)
+		}
+		lon, err1 := strconv.ParseFloat(parts[0], 64)
+		lat, err2 := strconv.ParseFloat(parts[1], 64)
+		if err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("tuple %q bukan angka", tuple)
+		}
+		positions = append(positions, []interface{}{lon, lat})
+	}
+	return positions, nil
+}
+
+func formatKMLCoordinates(positions [][]float64) string {
+	tuples := make([]string, len(positions))
+	for i, p := range positions {
+		tuples[i] = strconv.FormatFloat(p[0], 'f', -1, 64) + "," + strconv.FormatFloat(p[1], 'f', -1, 64)
+	}
+	return strings.Join(tuples, " ")
+}
+
+// toKMLColor mengubah "#rrggbb" / "#rrggbbaa" menjadi aabbggrr milik KML
+func toKMLColor(color string) string {
+	hex := strings.TrimPrefix(color, "#")
+	switch len(hex) {
+	case 6:
+		hex += "ff"
+	case 8:
+	default:
+		return ""
+	}
+	return strings.ToLower(hex[6:8] + hex[4:6] + hex[2:4] + hex[0:2])
+}
+
+// fromKMLColor mengubah aabbggrr menjadi "#RRGGBB"; withAlpha untuk fillColor
+// ("#RRGGBBAA")
+func fromKMLColor(color string, withAlpha bool) string {
+	hex := strings.TrimPrefix(strings.TrimSpace(color), "#")
+	if len(hex) != 8 {
+		return ""
+	}
+	if _, err := strconv.ParseUint(hex, 16, 32); err != nil {
+		return ""
+	}
+	rgb := strings.ToUpper("#" + hex[6:8] + hex[4:6] + hex[2:4])
+	if withAlpha {
+		return rgb + strings.ToUpper(hex[0:2])
+	}
+	return rgb
+}
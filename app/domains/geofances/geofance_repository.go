@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
 	"github.com/khoirulhasin/untirta_api/app/infrastructures/pkg"
 	"github.com/khoirulhasin/untirta_api/app/models"
 	"gorm.io/gorm"
@@ -18,12 +19,6 @@ func NewGeofenceRepository(db *gorm.DB) GeofenceRepository {
 var _ GeofenceRepository = &geofenceRepository{}
 
 func (r *geofenceRepository) CreateGeofence(ctx context.Context, input *models.CreateGeofenceInput, createdBy int) (*GeofenceDB, error) {
-	// Konversi Any (interface{}) → GeoJSONCoords
-	coords, err := toCoords(input.Coordinates)
-	if err != nil {
-		return nil, err
-	}
-
 	g := &GeofenceDB{
 		Name:        input.Name,
 		Description: input.Description,
@@ -32,12 +27,16 @@ func (r *geofenceRepository) CreateGeofence(ctx context.Context, input *models.C
 		FillColor:   strOr(input.FillColor, "#3B82F640"),
 		StrokeWidth: int32Or(input.StrokeWidth, 2),
 		GeoType:     input.GeoType,
-		Coordinates: coords,
 		Radius:      input.Radius,
 		IsActive:    boolOr(input.IsActive, true),
 		CreatedBy:   createdBy,
 	}
-	err = r.db.WithContext(ctx).Create(g).Error
+	// Konversi Any (interface{}) → GeoJSONCoords yang sudah divalidasi
+	if err := NormalizeGeometry(g, input.Coordinates); err != nil {
+		return nil, err
+	}
+
+	err := r.db.WithContext(ctx).Create(g).Error
 	return g, err
 }
 
@@ -46,7 +45,9 @@ func (r *geofenceRepository) UpdateGeofence(ctx context.Context, id int32, input
 	if err := r.db.WithContext(ctx).Where("id = ?", id).Take(g).Error; err != nil {
 		return nil, err
 	}
-	applyUpdate(g, input)
+	if err := applyUpdate(g, input); err != nil {
+		return nil, err
+	}
 	err := r.db.WithContext(ctx).Where("id = ?", id).Model(g).Updates(g).Error
 	return g, err
 }
@@ -56,7 +57,9 @@ func (r *geofenceRepository) UpdateGeofenceByUUID(ctx context.Context, uuid stri
 	if err := r.db.WithContext(ctx).Where("uuid = ?", uuid).Take(g).Error; err != nil {
 		return nil, err
 	}
-	applyUpdate(g, input)
+	if err := applyUpdate(g, input); err != nil {
+		return nil, err
+	}
 	err := r.db.WithContext(ctx).Where("uuid = ?", uuid).Model(g).Updates(g).Error
 	return g, err
 }
@@ -99,6 +102,35 @@ func (r *geofenceRepository) GetAllGeofences(ctx context.Context) ([]*GeofenceDB
 	return list, err
 }
 
+func (r *geofenceRepository) GetGeofencesContainingPoint(ctx context.Context, p geo.Point) ([]*GeofenceDB, error) {
+	var list []*GeofenceDB
+	query := r.db.WithContext(ctx).Where("deleted_at = 0 AND is_active = true")
+	if pkg.PostGIS() {
+		// ST_DWithin memakai index GIST geofences.geog (jarak 0 untuk polygon,
+		// radius untuk circle/line); ST_Contains menyamakan hasil polygon dengan
+		// perhitungan datar lon/lat. geog NULL (data lama) diuji di bawah.
+		query = query.Where(`(geog IS NULL OR (
+			ST_DWithin(geog, ST_MakePoint(?, ?)::geography, CASE WHEN geo_type = 'Polygon' THEN 0 ELSE COALESCE(radius, 0) END)
+			AND (geo_type <> 'Polygon' OR ST_Contains(geog::geometry, ST_SetSRID(ST_MakePoint(?, ?), 4326)))
+		))`, p.Lon, p.Lat, p.Lon, p.Lat)
+	}
+	err := query.Order("created_at DESC").Find(&list).Error
+	if err != nil {
+		return nil, err
+	}
+
+	containing := make([]*GeofenceDB, 0)
+	for _, g := range list {
+		if box, ok := g.BoundingBox(); ok && !box.Contains(p) {
+			continue
+		}
+		if g.Contains(p) {
+			containing = append(containing, g)
+		}
+	}
+	return containing, nil
+}
+
 func (r *geofenceRepository) PageGeofence(ctx context.Context, pagination models.Pagination) (models.Pagination, error) {
 	var list []GeofenceDB
 	err := r.db.Scopes(pkg.Paginate(list, &pagination, r.db)).Find(&list).Error
@@ -120,7 +152,9 @@ func toCoords(v interface{}) (GeoJSONCoords, error) {
 	return coords, json.Unmarshal(b, &coords)
 }
 
-func applyUpdate(g *GeofenceDB, input *models.UpdateGeofenceInput) {
+// applyUpdate menyalin field yang diisi; geometri divalidasi ulang bila
+// type, geoType, coordinates atau radius berubah
+func applyUpdate(g *GeofenceDB, input *models.UpdateGeofenceInput) error {
 	if input.Name != nil {
 		g.Name = *input.Name
 	}
@@ -148,11 +182,15 @@ func applyUpdate(g *GeofenceDB, input *models.UpdateGeofenceInput) {
 	if input.IsActive != nil {
 		g.IsActive = *input.IsActive
 	}
+
+	if input.Type == nil && input.GeoType == nil && input.Coordinates == nil && input.Radius == nil {
+		return nil
+	}
+	var coordinates interface{} = []interface{}(g.Coordinates)
 	if input.Coordinates != nil {
-		if coords, err := toCoords(input.Coordinates); err == nil {
-			g.Coordinates = coords
-		}
+		coordinates = input.Coordinates
 	}
+	return NormalizeGeometry(g, coordinates)
 }
 
 func strOr(v *string, def string) string {
@@ -0,0 +1,176 @@
+package geofences
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/domains/ships"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+const (
+	// dua posisi di dalam yang berjarak lebih dari ini dianggap kunjungan berbeda
+	occupancyVisitGap = 30 * time.Minute
+	// satu titik per vessel per bucket ini cukup untuk occupancy
+	occupancySampleSeconds = 60
+	occupancyMaxBuckets    = 2000
+)
+
+// GeofenceVisit — satu kunjungan vessel ke dalam geofence (epoch ms)
+type GeofenceVisit struct {
+	Mmsi            int64   `json:"mmsi"`
+	Imei            *string `json:"imei,omitempty"`
+	EnteredAt       int64   `json:"enteredAt"`
+	ExitedAt        int64   `json:"exitedAt"`
+	DurationSeconds int     `json:"durationSeconds"`
+	PositionCount   int     `json:"positionCount"`
+	Ongoing         bool    `json:"ongoing"`
+}
+
+type GeofenceOccupancyBucket struct {
+	Time  int64 `json:"time"`
+	Count int   `json:"count"`
+}
+
+type GeofenceOccupancy struct {
+	GeofenceID    int                        `json:"geofenceId"`
+	GeofenceName  string                     `json:"geofenceName"`
+	Start         int64                      `json:"start"`
+	End           int64                      `json:"end"`
+	BucketSeconds int                        `json:"bucketSeconds"`
+	UniqueVessels int                        `json:"uniqueVessels"`
+	PeakCount     int                        `json:"peakCount"`
+	PeakAt        *int64                     `json:"peakAt,omitempty"`
+	Visits        []*GeofenceVisit           `json:"visits"`
+	Buckets       []*GeofenceOccupancyBucket `json:"buckets"`
+}
+
+// Locator menjawab query spasial geofence memakai posisi AIS di Mongo
+type Locator struct {
+	geofenceRepository GeofenceRepository
+	shipMongotory      ships.ShipMongotory
+	shipMongodistory   ships.ShipMongodistory
+}
+
+func NewLocator(geofenceRepository GeofenceRepository, shipMongotory ships.ShipMongotory, shipMongodistory ships.ShipMongodistory) *Locator {
+	return &Locator{
+		geofenceRepository: geofenceRepository,
+		shipMongotory:      shipMongotory,
+		shipMongodistory:   shipMongodistory,
+	}
+}
+
+// VesselsInGeofence mengembalikan posisi terakhir (pada waktu at) setiap vessel
+// yang berada di dalam geofence
+func (l *Locator) VesselsInGeofence(ctx context.Context, id int32, at time.Time, maxAge time.Duration) ([]*ships.VesselSnapshot, error) {
+	g, err := l.geofenceRepository.GetGeofenceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	snapshots, err := l.shipMongodistory.GetVesselSnapshot(ctx, boundingBoxOf(g), at, maxAge)
+	if err != nil {
+		return nil, err
+	}
+
+	inside := make([]*ships.VesselSnapshot, 0, len(snapshots))
+	for _, snapshot := range snapshots {
+		if snapshot.Position == nil {
+			continue
+		}
+		if g.Contains(geo.Point{Lat: snapshot.Position.Latitude, Lon: snapshot.Position.Longitude}) {
+			inside = append(inside, snapshot)
+		}
+	}
+	return inside, nil
+}
+
+// OccupancyHistory menyusun kunjungan per vessel dan jumlah vessel per bucket waktu
+func (l *Locator) OccupancyHistory(ctx context.Context, id int32, durationTimeInput models.DurationTimeInput, bucket time.Duration) (*GeofenceOccupancy, error) {
+	g, err := l.geofenceRepository.GetGeofenceByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Unix(int64(durationTimeInput.Start), 0)
+	end := time.Unix(int64(durationTimeInput.End), 0)
+	if int(end.Sub(start)/bucket) > occupancyMaxBuckets {
+		return nil, fmt.Errorf("bucketSeconds terlalu kecil, maksimal %d bucket", occupancyMaxBuckets)
+	}
+
+	sample := occupancySampleSeconds
+	tracks, err := l.shipMongotory.GetShipsInBoundingBox(ctx, durationTimeInput, boundingBoxOf(g), ships.TrackOptions{BucketSeconds: &sample})
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildOccupancy(g, ships.NewVesselTrackResult(tracks).Tracks, start, end, bucket), nil
+}
+
+// BuildOccupancy menghitung occupancy dari track; hanya titik di dalam geofence yang dipakai
+func BuildOccupancy(g *GeofenceDB, tracks []*ships.VesselTrack, start, end time.Time, bucket time.Duration) *GeofenceOccupancy {
+	occupancy := &GeofenceOccupancy{
+		GeofenceID:    int(g.ID),
+		GeofenceName:  g.Name,
+		Start:         start.UnixMilli(),
+		End:           end.UnixMilli(),
+		BucketSeconds: int(bucket.Seconds()),
+		Visits:        []*GeofenceVisit{},
+		Buckets:       []*GeofenceOccupancyBucket{},
+	}
+
+	gap := occupancyVisitGap.Milliseconds()
+	for _, track := range tracks {
+		var current *GeofenceVisit
+		for _, position := range track.Positions {
+			if !g.Contains(geo.Point{Lat: position.Latitude, Lon: position.Longitude}) {
+				continue
+			}
+			if current == nil || position.Ts-current.ExitedAt > gap {
+				current = &GeofenceVisit{Mmsi: track.Mmsi, Imei: track.Imei, EnteredAt: position.Ts}
+				occupancy.Visits = append(occupancy.Visits, current)
+			}
+			current.ExitedAt = position.Ts
+			current.PositionCount++
+		}
+		if current != nil {
+			occupancy.UniqueVessels++
+		}
+	}
+
+	for _, visit := range occupancy.Visits {
+		visit.DurationSeconds = int((visit.ExitedAt - visit.EnteredAt) / 1000)
+		visit.Ongoing = occupancy.End-visit.ExitedAt <= gap
+	}
+	sort.Slice(occupancy.Visits, func(i, j int) bool {
+		return occupancy.Visits[i].EnteredAt < occupancy.Visits[j].EnteredAt
+	})
+
+	bucketMs := bucket.Milliseconds()
+	for t := occupancy.Start; t < occupancy.End; t += bucketMs {
+		seen := make(map[int64]bool)
+		for _, visit := range occupancy.Visits {
+			if visit.EnteredAt < t+bucketMs && visit.ExitedAt >= t {
+				seen[visit.Mmsi] = true
+			}
+		}
+		occupancy.Buckets = append(occupancy.Buckets, &GeofenceOccupancyBucket{Time: t, Count: len(seen)})
+		if len(seen) > occupancy.PeakCount {
+			peakAt := t
+			occupancy.PeakCount, occupancy.PeakAt = len(seen), &peakAt
+		}
+	}
+
+	return occupancy
+}
+
+// boundingBoxOf — geofence tanpa bounding box (geometri tidak dikenal) memakai seluruh bumi
+func boundingBoxOf(g *GeofenceDB) geo.BoundingBox {
+	if box, ok := g.BoundingBox(); ok {
+		return box
+	}
+	return geo.BoundingBox{MinLat: -90, MinLon: -180, MaxLat: 90, MaxLon: 180}
+}
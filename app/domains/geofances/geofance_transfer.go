@@ -0,0 +1,351 @@
+package geofences
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+// ukuran file import maksimal
+const maxImportBytes = 20 << 20
+
+// GeofenceFile adalah hasil export siap diunduh
+type GeofenceFile struct {
+	Format      models.GeofenceFileFormat
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// geofenceFeature adalah satu feature/placemark hasil parsing file import
+type geofenceFeature struct {
+	input  models.CreateGeofenceInput
+	errors []string
+}
+
+// Transfer meng-import dan meng-export tabel geofences sebagai GeoJSON
+// FeatureCollection, KML, atau KMZ
+type Transfer struct {
+	geofenceRepository GeofenceRepository
+}
+
+func NewTransfer(geofenceRepository GeofenceRepository) *Transfer {
+	return &Transfer{geofenceRepository: geofenceRepository}
+}
+
+// ExportFile menulis geofence (ids kosong = semua) dalam format yang diminta
+func (t *Transfer) ExportFile(ctx context.Context, format models.GeofenceFileFormat, ids []int32) (*GeofenceFile, error) {
+	list, err := t.geofenceRepository.GetAllGeofences(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(ids) > 0 {
+		wanted := make(map[int32]bool, len(ids))
+		for _, id := range ids {
+			wanted[id] = true
+		}
+		filtered := make([]*GeofenceDB, 0, len(ids))
+		for _, g := range list {
+			if wanted[g.ID] {
+				filtered = append(filtered, g)
+			}
+		}
+		list = filtered
+	}
+
+	file := &GeofenceFile{Format: format}
+	switch format {
+	case models.GeofenceFileFormatGeojson:
+		file.Filename, file.ContentType = "geofences.geojson", "application/geo+json"
+		file.Data, err = encodeGeoJSON(list)
+	case models.GeofenceFileFormatKml:
+		file.Filename, file.ContentType = "geofences.kml", "application/vnd.google-earth.kml+xml"
+		file.Data, err = encodeKML(list)
+	case models.GeofenceFileFormatKmz:
+		file.Filename, file.ContentType = "geofences.kmz", "application/vnd.google-earth.kmz"
+		file.Data, err = encodeKMZ(list)
+	default:
+		return nil, fmt.Errorf("format %q tidak didukung", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return file, nil
+}
+
+// Export sama dengan ExportFile dengan isi sebagai string (base64 untuk KMZ)
+func (t *Transfer) Export(ctx context.Context, format models.GeofenceFileFormat, ids []int32) (*models.GeofenceExport, error) {
+	file, err := t.ExportFile(ctx, format, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	export := &models.GeofenceExport{
+		Format:      file.Format,
+		Filename:    file.Filename,
+		ContentType: file.ContentType,
+		Encoding:    "utf-8",
+		Content:     string(file.Data),
+	}
+	if format == models.GeofenceFileFormatKmz {
+		export.Encoding = "base64"
+		export.Content = base64.StdEncoding.EncodeToString(file.Data)
+	}
+	return export, nil
+}
+
+// Import membaca file lalu membuat/memperbarui geofence. Dengan dryRun tidak
+// ada yang disimpan; hasilnya preview aksi per feature. format nil berarti
+// dideteksi dari ekstensi filename atau isi file.
+func (t *Transfer) Import(ctx context.Context, r io.Reader, filename string, format *models.GeofenceFileFormat, dryRun bool, onDuplicate models.GeofenceDuplicateStrategy, createdBy int) (*models.GeofenceImportResult, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxImportBytes+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > maxImportBytes {
+		return nil, fmt.Errorf("file terlalu besar, maksimal %d MB", maxImportBytes>>20)
+	}
+
+	detected := detectFormat(filename, data)
+	if format != nil {
+		detected = *format
+	}
+
+	var features []*geofenceFeature
+	switch detected {
+	case models.GeofenceFileFormatGeojson:
+		features, err = decodeGeoJSON(data)
+	case models.GeofenceFileFormatKml:
+		features, err = decodeKML(data)
+	case models.GeofenceFileFormatKmz:
+		features, err = decodeKMZ(data)
+	default:
+		return nil, errors.New("format file tidak dikenali, gunakan GeoJSON, KML, atau KMZ")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	existing, err := t.geofenceRepository.GetAllGeofences(ctx)
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*GeofenceDB, len(existing))
+	for _, g := range existing {
+		byName[nameKey(g.Name)] = g
+	}
+
+	result := &models.GeofenceImportResult{
+		DryRun: dryRun,
+		Format: detected,
+		Total:  len(features),
+		Items:  make([]*models.GeofenceImportItem, 0, len(features)),
+	}
+	seen := make(map[string]int)
+	for i, feature := range features {
+		item := t.plan(i, feature, byName, seen, onDuplicate)
+		if !dryRun {
+			t.apply(ctx, item, feature, createdBy)
+		}
+
+		switch item.Action {
+		case models.GeofenceImportActionCreate:
+			result.Created++
+		case models.GeofenceImportActionUpdate:
+			result.Updated++
+		case models.GeofenceImportActionSkip:
+			result.Skipped++
+		case models.GeofenceImportActionInvalid:
+			result.Invalid++
+		}
+		result.Items = append(result.Items, item)
+	}
+
+	return result, nil
+}
+
+// plan menentukan aksi satu feature tanpa menyimpan apa pun
+func (t *Transfer) plan(index int, feature *geofenceFeature, byName map[string]*GeofenceDB, seen map[string]int, onDuplicate models.GeofenceDuplicateStrategy) *models.GeofenceImportItem {
+	input := feature.input
+	item := &models.GeofenceImportItem{
+		Index:   index,
+		Name:    input.Name,
+		Errors:  append([]string{}, feature.errors...),
+		Type:    nonEmpty(input.Type),
+		GeoType: nonEmpty(input.GeoType),
+	}
+
+	if strings.TrimSpace(input.Name) == "" {
+		item.Errors = append(item.Errors, "name tidak boleh kosong")
+	}
+
+	preview := &GeofenceDB{
+		Name:        input.Name,
+		Description: input.Description,
+		Type:        input.Type,
+		Color:       strOr(input.Color, "#3B82F6"),
+		FillColor:   strOr(input.FillColor, "#3B82F640"),
+		StrokeWidth: int32Or(input.StrokeWidth, 2),
+		GeoType:     input.GeoType,
+		Radius:      input.Radius,
+		IsActive:    boolOr(input.IsActive, true),
+	}
+	if len(item.Errors) == 0 {
+		if err := NormalizeGeometry(preview, input.Coordinates); err != nil {
+			var validationErrors error_handlers.ValidationErrors
+			if errors.As(err, &validationErrors) {
+				for _, fieldErr := range validationErrors {
+					item.Errors = append(item.Errors, fieldErr.Error())
+				}
+			} else {
+				item.Errors = append(item.Errors, err.Error())
+			}
+		}
+	}
+	if len(item.Errors) > 0 {
+		item.Action = models.GeofenceImportActionInvalid
+		return item
+	}
+	item.Geofence = preview
+
+	key := nameKey(input.Name)
+	if first, ok := seen[key]; ok {
+		item.Action = models.GeofenceImportActionSkip
+		item.Errors = append(item.Errors, fmt.Sprintf("name sama dengan feature %d di file yang sama", first))
+		return item
+	}
+	seen[key] = index
+
+	if g, ok := byName[key]; ok {
+		id := int(g.ID)
+		item.DuplicateOfID = &id
+		item.Action = models.GeofenceImportActionSkip
+		if onDuplicate == models.GeofenceDuplicateStrategyOverwrite {
+			item.Action = models.GeofenceImportActionUpdate
+		}
+		return item
+	}
+
+	item.Action = models.GeofenceImportActionCreate
+	return item
+}
+
+// apply menyimpan hasil plan; kegagalan menjadikan item INVALID
+func (t *Transfer) apply(ctx context.Context, item *models.GeofenceImportItem, feature *geofenceFeature, createdBy int) {
+	input := feature.input
+
+	var saved *GeofenceDB
+	var err error
+	switch item.Action {
+	case models.GeofenceImportActionCreate:
+		saved, err = t.geofenceRepository.CreateGeofence(ctx, &input, createdBy)
+	case models.GeofenceImportActionUpdate:
+		saved, err = t.geofenceRepository.UpdateGeofence(ctx, int32(*item.DuplicateOfID), &models.UpdateGeofenceInput{
+			Name:        &input.Name,
+			Description: input.Description,
+			Type:        &input.Type,
+			Color:       input.Color,
+			FillColor:   input.FillColor,
+			StrokeWidth: input.StrokeWidth,
+			GeoType:     &input.GeoType,
+			Coordinates: input.Coordinates,
+			Radius:      input.Radius,
+			IsActive:    input.IsActive,
+		})
+	default:
+		return
+	}
+
+	if err != nil {
+		item.Action = models.GeofenceImportActionInvalid
+		item.Errors = append(item.Errors, err.Error())
+		item.Geofence = nil
+		return
+	}
+	item.Geofence = saved
+}
+
+// detectFormat menebak format dari ekstensi file, lalu dari isi
+func detectFormat(filename string, data []byte) models.GeofenceFileFormat {
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".geojson", ".json":
+		return models.GeofenceFileFormatGeojson
+	case ".kml":
+		return models.GeofenceFileFormatKml
+	case ".kmz":
+		return models.GeofenceFileFormatKmz
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(data, []byte("PK")):
+		return models.GeofenceFileFormatKmz
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return models.GeofenceFileFormatKml
+	case bytes.HasPrefix(trimmed, []byte("{")):
+		return models.GeofenceFileFormatGeojson
+	}
+	return ""
+}
+
+// nameKey — kunci deteksi duplikat
+func nameKey(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+func nonEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// typeForGeoType — type default bila file tidak menyebutkan type
+func typeForGeoType(geoType string) string {
+	switch geoType {
+	case "Point":
+		return "circle"
+	case "LineString":
+		return "line"
+	}
+	return "polygon"
+}
+
+// exportCoordinates mengembalikan koordinat GeoJSON baku untuk export; data
+// lama (ring tanpa pembungkus, rectangle dua sudut) ikut diseragamkan
+func exportCoordinates(g *GeofenceDB) interface{} {
+	switch g.GeoType {
+	case "Point":
+		if center, ok := g.Center(); ok {
+			return []float64{center.Lon, center.Lat}
+		}
+	case "LineString":
+		line := make([][]float64, 0)
+		for _, p := range g.Line() {
+			line = append(line, []float64{p.Lon, p.Lat})
+		}
+		return line
+	case "Polygon":
+		rings := make([][][]float64, 0)
+		for _, ring := range g.Rings() {
+			positions := make([][]float64, 0, len(ring))
+			for _, p := range ring {
+				positions = append(positions, []float64{p.Lon, p.Lat})
+			}
+			if g.Type == "rectangle" && len(positions) == 2 {
+				positions = rectangleRing(positions[0], positions[1])
+			}
+			rings = append(rings, positions)
+		}
+		return rings
+	}
+	return []interface{}(g.Coordinates)
+}
@@ -0,0 +1,322 @@
+package geofences
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+)
+
+// batas titik per geofence; pemeriksaan self-intersection O(n²)
+const maxGeofenceVertices = 5000
+
+// geoType GeoJSON yang diterima untuk setiap type geofence
+var geofenceGeoTypes = map[string]string{
+	"polygon":   "Polygon",
+	"rectangle": "Polygon",
+	"circle":    "Point",
+	"line":      "LineString",
+}
+
+// NormalizeGeometry memvalidasi type, geoType, coordinates dan radius lalu
+// mengisi g.Coordinates dengan bentuk baku:
+//
+//	Point       [lon, lat] (altitude dibuang), radius wajib > 0
+//	LineString  [[lon, lat], ...] minimal 2 titik
+//	Polygon     [[[lon, lat], ...], ...] ring tertutup minimal 4 titik, tidak
+//	            berpotongan sendiri; ring luar berlawanan arah jarum jam dan
+//	            hole searah jarum jam (RFC 7946). Rectangle boleh dikirim
+//	            sebagai dua sudut dan diubah menjadi ring.
+//
+// Kesalahan dikembalikan sebagai error_handlers.ValidationErrors per field.
+func NormalizeGeometry(g *GeofenceDB, coordinates interface{}) error {
+	var errs error_handlers.ValidationErrors
+
+	expected, ok := geofenceGeoTypes[g.Type]
+	if !ok {
+		errs.Add("type", "harus salah satu dari polygon, rectangle, circle, line")
+		return errs
+	}
+	if g.GeoType != expected {
+		errs.Add("geoType", "harus %s untuk type %s", expected, g.Type)
+		return errs
+	}
+
+	coords, err := toCoords(coordinates)
+	if err != nil {
+		errs.Add("coordinates", "bukan array koordinat GeoJSON")
+		return errs
+	}
+
+	switch g.GeoType {
+	case "Point":
+		point, ok := readPosition([]interface{}(coords), "coordinates", &errs)
+		if g.Radius == nil || *g.Radius <= 0 {
+			errs.Add("radius", "harus lebih dari 0 untuk circle")
+		}
+		if ok {
+			g.Coordinates = GeoJSONCoords{point[0], point[1]}
+		}
+	case "LineString":
+		line := readPositions(coords, "coordinates", &errs)
+		if len(line) < 2 {
+			errs.Add("coordinates", "LineString membutuhkan minimal 2 titik")
+		}
+		if g.Radius != nil && *g.Radius < 0 {
+			errs.Add("radius", "tidak boleh negatif")
+		}
+		g.Coordinates = toCoordsList(toInterfaces(line)...)
+	case "Polygon":
+		rings := readRings(g, coords, &errs)
+		if len(errs) == 0 {
+			checkRings(rings, &errs)
+		}
+		g.Coordinates = toCoordsList(rings...)
+	}
+
+	return errs.Err()
+}
+
+// readRings membaca Polygon; satu ring tanpa pembungkus juga diterima
+func readRings(g *GeofenceDB, coords GeoJSONCoords, errs *error_handlers.ValidationErrors) []interface{} {
+	if len(coords) == 0 {
+		errs.Add("coordinates", "Polygon membutuhkan minimal satu ring")
+		return nil
+	}
+
+	var raw [][]interface{}
+	prefix := "coordinates[%d]"
+	if isNumberPair(coords[0]) {
+		raw = [][]interface{}{coords}
+		prefix = "coordinates"
+	} else {
+		for i, item := range coords {
+			ring, ok := item.([]interface{})
+			if !ok {
+				errs.Add(fmt.Sprintf("coordinates[%d]", i), "ring harus berupa array posisi")
+				continue
+			}
+			raw = append(raw, ring)
+		}
+	}
+
+	total := 0
+	rings := make([]interface{}, 0, len(raw))
+	for i, ringRaw := range raw {
+		field := prefix
+		if prefix != "coordinates" {
+			field = fmt.Sprintf(prefix, i)
+		}
+		ring := dedupePositions(readPositions(ringRaw, field, errs))
+		total += len(ring)
+
+		// rectangle dua sudut → ring tertutup
+		if g.Type == "rectangle" && len(raw) == 1 && len(ring) == 2 {
+			ring = rectangleRing(ring[0], ring[1])
+		}
+		if len(ring) < 4 {
+			errs.Add(field, "ring membutuhkan minimal 4 titik")
+		} else if !samePosition(ring[0], ring[len(ring)-1]) {
+			errs.Add(field, "ring harus tertutup (titik pertama sama dengan titik terakhir)")
+		}
+		rings = append(rings, ring)
+	}
+	if total > maxGeofenceVertices {
+		errs.Add("coordinates", "maksimal %d titik", maxGeofenceVertices)
+	}
+
+	return rings
+}
+
+// checkRings menolak ring yang berpotongan dan menyeragamkan arah putaran
+func checkRings(rings []interface{}, errs *error_handlers.ValidationErrors) {
+	segments := make([][][2][]float64, len(rings))
+	for i, ring := range rings {
+		positions := ring.([][]float64)
+		segments[i] = make([][2][]float64, 0, len(positions)-1)
+		for j := 0; j+1 < len(positions); j++ {
+			segments[i] = append(segments[i], [2][]float64{positions[j], positions[j+1]})
+		}
+	}
+
+	for i := range segments {
+		for a := 0; a < len(segments[i]); a++ {
+			for b := a + 1; b < len(segments[i]); b++ {
+				// segmen bersebelahan berbagi satu titik
+				if b == a+1 || (a == 0 && b == len(segments[i])-1) {
+					if !overlapsCollinear(segments[i][a], segments[i][b]) {
+						continue
+					}
+				} else if !segmentsIntersect(segments[i][a], segments[i][b]) {
+					continue
+				}
+				errs.Add(fmt.Sprintf("coordinates[%d]", i), "ring berpotongan sendiri pada segmen %d dan %d", a, b)
+				return
+			}
+			for k := i + 1; k < len(segments); k++ {
+				for b := range segments[k] {
+					if segmentsIntersect(segments[i][a], segments[k][b]) {
+						errs.Add(fmt.Sprintf("coordinates[%d]", k), "ring berpotongan dengan ring %d", i)
+						return
+					}
+				}
+			}
+		}
+	}
+
+	for i, ring := range rings {
+		positions := ring.([][]float64)
+		area := signedArea(positions)
+		if area == 0 {
+			errs.Add(fmt.Sprintf("coordinates[%d]", i), "ring tidak memiliki luas")
+			return
+		}
+		// ring luar CCW (luas positif), hole CW
+		if ccw := area > 0; ccw != (i == 0) {
+			for l, r := 0, len(positions)-1; l < r; l, r = l+1, r-1 {
+				positions[l], positions[r] = positions[r], positions[l]
+			}
+		}
+	}
+}
+
+// readPositions membaca array posisi [lon, lat]
+func readPositions(items []interface{}, field string, errs *error_handlers.ValidationErrors) [][]float64 {
+	positions := make([][]float64, 0, len(items))
+	for i, item := range items {
+		if position, ok := readPosition(item, fmt.Sprintf("%s[%d]", field, i), errs); ok {
+			positions = append(positions, position)
+		}
+	}
+	return positions
+}
+
+// readPosition membaca satu posisi [lon, lat] atau [lon, lat, alt] dan memeriksa rentangnya
+func readPosition(v interface{}, field string, errs *error_handlers.ValidationErrors) ([]float64, bool) {
+	pair, ok := v.([]interface{})
+	if !ok || len(pair) < 2 || len(pair) > 3 {
+		errs.Add(field, "posisi harus berupa [lon, lat]")
+		return nil, false
+	}
+	lon, ok1 := pair[0].(float64)
+	lat, ok2 := pair[1].(float64)
+	if !ok1 || !ok2 || math.IsNaN(lon) || math.IsNaN(lat) {
+		errs.Add(field, "lon dan lat harus berupa angka")
+		return nil, false
+	}
+	if lon < -180 || lon > 180 {
+		errs.Add(field, "longitude %v di luar rentang -180..180", lon)
+		return nil, false
+	}
+	if lat < -90 || lat > 90 {
+		errs.Add(field, "latitude %v di luar rentang -90..90", lat)
+		return nil, false
+	}
+	return []float64{lon, lat}, true
+}
+
+// dedupePositions membuang titik berurutan yang sama
+func dedupePositions(positions [][]float64) [][]float64 {
+	result := make([][]float64, 0, len(positions))
+	for _, p := range positions {
+		if len(result) > 0 && samePosition(result[len(result)-1], p) {
+			continue
+		}
+		result = append(result, p)
+	}
+	return result
+}
+
+func isNumberPair(v interface{}) bool {
+	pair, ok := v.([]interface{})
+	if !ok || len(pair) < 2 {
+		return false
+	}
+	_, ok = pair[0].(float64)
+	return ok
+}
+
+func samePosition(a, b []float64) bool {
+	return a[0] == b[0] && a[1] == b[1]
+}
+
+func rectangleRing(a, b []float64) [][]float64 {
+	minLon, maxLon := math.Min(a[0], b[0]), math.Max(a[0], b[0])
+	minLat, maxLat := math.Min(a[1], b[1]), math.Max(a[1], b[1])
+	return [][]float64{{minLon, minLat}, {maxLon, minLat}, {maxLon, maxLat}, {minLon, maxLat}, {minLon, minLat}}
+}
+
+// signedArea — rumus shoelace pada bidang lon/lat; positif berarti CCW
+func signedArea(ring [][]float64) float64 {
+	area := 0.0
+	for i := 0; i+1 < len(ring); i++ {
+		area += ring[i][0]*ring[i+1][1] - ring[i+1][0]*ring[i][1]
+	}
+	return area / 2
+}
+
+func orientation(p, q, r []float64) int {
+	v := (q[1]-p[1])*(r[0]-q[0]) - (q[0]-p[0])*(r[1]-q[1])
+	switch {
+	case v > 0:
+		return 1
+	case v < 0:
+		return -1
+	}
+	return 0
+}
+
+func onSegment(p, q, r []float64) bool {
+	return q[0] <= math.Max(p[0], r[0]) && q[0] >= math.Min(p[0], r[0]) &&
+		q[1] <= math.Max(p[1], r[1]) && q[1] >= math.Min(p[1], r[1])
+}
+
+func segmentsIntersect(s1, s2 [2][]float64) bool {
+	p1, q1, p2, q2 := s1[0], s1[1], s2[0], s2[1]
+	o1, o2 := orientation(p1, q1, p2), orientation(p1, q1, q2)
+	o3, o4 := orientation(p2, q2, p1), orientation(p2, q2, q1)
+
+	if o1 != o2 && o3 != o4 {
+		return true
+	}
+	return (o1 == 0 && onSegment(p1, p2, q1)) ||
+		(o2 == 0 && onSegment(p1, q2, q1)) ||
+		(o3 == 0 && onSegment(p2, p1, q2)) ||
+		(o4 == 0 && onSegment(p2, q1, q2))
+}
+
+// overlapsCollinear — segmen bersebelahan hanya salah bila berbalik arah di atas dirinya sendiri
+func overlapsCollinear(s1, s2 [2][]float64) bool {
+	if orientation(s1[0], s1[1], s2[0]) != 0 || orientation(s1[0], s1[1], s2[1]) != 0 {
+		return false
+	}
+	// titik bersama adalah s1[1] = s2[0] (atau s1[0] = s2[1] untuk segmen pertama/terakhir)
+	a, shared, b := s1[0], s1[1], s2[1]
+	if samePosition(s1[0], s2[1]) {
+		a, shared, b = s1[1], s1[0], s2[0]
+	}
+	return (a[0]-shared[0])*(b[0]-shared[0])+(a[1]-shared[1])*(b[1]-shared[1]) > 0
+}
+
+// toCoordsList mengubah posisi/ring bertipe menjadi GeoJSONCoords ([]interface{}
+// bersarang) agar sama dengan hasil baca dari JSONB
+func toCoordsList(items ...interface{}) GeoJSONCoords {
+	coords := GeoJSONCoords{}
+	for _, item := range items {
+		switch v := item.(type) {
+		case [][]float64:
+			coords = append(coords, []interface{}(toCoordsList(toInterfaces(v)...)))
+		case []float64:
+			coords = append(coords, []interface{}{v[0], v[1]})
+		}
+	}
+	return coords
+}
+
+func toInterfaces(positions [][]float64) []interface{} {
+	items := make([]interface{}, len(positions))
+	for i, p := range positions {
+		items[i] = p
+	}
+	return items
+}
@@ -0,0 +1,184 @@
+package geofences
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+)
+
+func TestNormalizeGeometry(t *testing.T) {
+	radius := 500.0
+
+	tests := []struct {
+		name        string
+		geofence    GeofenceDB
+		coordinates interface{}
+		want        GeoJSONCoords
+	}{
+		{
+			name:        "polygon searah jarum jam dibalik menjadi CCW",
+			geofence:    GeofenceDB{Type: "polygon", GeoType: "Polygon"},
+			coordinates: [][][]float64{{{0, 0}, {0, 1}, {1, 1}, {1, 0}, {0, 0}}},
+			want:        coords([][]float64{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}),
+		},
+		{
+			name:     "hole dibuat searah jarum jam",
+			geofence: GeofenceDB{Type: "polygon", GeoType: "Polygon"},
+			coordinates: [][][]float64{
+				{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+				{{1, 1}, {2, 1}, {2, 2}, {1, 2}, {1, 1}},
+			},
+			want: coords(
+				[][]float64{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+				[][]float64{{1, 1}, {1, 2}, {2, 2}, {2, 1}, {1, 1}},
+			),
+		},
+		{
+			name:        "ring tanpa pembungkus dan titik ganda berurutan",
+			geofence:    GeofenceDB{Type: "polygon", GeoType: "Polygon"},
+			coordinates: [][]float64{{0, 0}, {1, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}},
+			want:        coords([][]float64{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}),
+		},
+		{
+			name:        "rectangle dari dua sudut",
+			geofence:    GeofenceDB{Type: "rectangle", GeoType: "Polygon"},
+			coordinates: [][]float64{{2, 1}, {0, 0}},
+			want:        coords([][]float64{{0, 0}, {2, 0}, {2, 1}, {0, 1}, {0, 0}}),
+		},
+		{
+			name:        "circle membuang altitude",
+			geofence:    GeofenceDB{Type: "circle", GeoType: "Point", Radius: &radius},
+			coordinates: []float64{106.1, -6, 12},
+			want:        GeoJSONCoords{106.1, -6.0},
+		},
+		{
+			name:        "line",
+			geofence:    GeofenceDB{Type: "line", GeoType: "LineString"},
+			coordinates: [][]float64{{106, -6}, {106.5, -5.9, 3}},
+			want:        coords([]float64{106, -6}, []float64{106.5, -5.9}),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := tt.geofence
+			if err := NormalizeGeometry(&g, tt.coordinates); err != nil {
+				t.Fatalf("NormalizeGeometry() error = %v", err)
+			}
+			if !reflect.DeepEqual(g.Coordinates, tt.want) {
+				t.Errorf("Coordinates = %v, want %v", g.Coordinates, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeGeometryErrors(t *testing.T) {
+	radius := 500.0
+
+	tests := []struct {
+		name        string
+		geofence    GeofenceDB
+		coordinates interface{}
+		wantField   string
+	}{
+		{
+			name:        "type tidak dikenal",
+			geofence:    GeofenceDB{Type: "hexagon", GeoType: "Polygon"},
+			coordinates: [][]float64{},
+			wantField:   "type",
+		},
+		{
+			name:        "geoType tidak cocok",
+			geofence:    GeofenceDB{Type: "circle", GeoType: "Polygon", Radius: &radius},
+			coordinates: []float64{106, -6},
+			wantField:   "geoType",
+		},
+		{
+			name:        "bukan array",
+			geofence:    GeofenceDB{Type: "line", GeoType: "LineString"},
+			coordinates: "106,-6",
+			wantField:   "coordinates",
+		},
+		{
+			name:        "circle tanpa radius",
+			geofence:    GeofenceDB{Type: "circle", GeoType: "Point"},
+			coordinates: []float64{106, -6},
+			wantField:   "radius",
+		},
+		{
+			name:        "latitude di luar rentang",
+			geofence:    GeofenceDB{Type: "line", GeoType: "LineString"},
+			coordinates: [][]float64{{106, -6}, {106, 91}},
+			wantField:   "coordinates[1]",
+		},
+		{
+			name:        "line satu titik",
+			geofence:    GeofenceDB{Type: "line", GeoType: "LineString"},
+			coordinates: [][]float64{{106, -6}},
+			wantField:   "coordinates",
+		},
+		{
+			name:        "ring tidak tertutup",
+			geofence:    GeofenceDB{Type: "polygon", GeoType: "Polygon"},
+			coordinates: [][][]float64{{{0, 0}, {1, 0}, {1, 1}, {0, 1}}},
+			wantField:   "coordinates[0]",
+		},
+		{
+			name:        "ring kurang dari 4 titik",
+			geofence:    GeofenceDB{Type: "polygon", GeoType: "Polygon"},
+			coordinates: [][][]float64{{{0, 0}, {1, 0}, {0, 0}}},
+			wantField:   "coordinates[0]",
+		},
+		{
+			name:        "bowtie berpotongan sendiri",
+			geofence:    GeofenceDB{Type: "polygon", GeoType: "Polygon"},
+			coordinates: [][][]float64{{{0, 0}, {1, 1}, {1, 0}, {0, 1}, {0, 0}}},
+			wantField:   "coordinates[0]",
+		},
+		{
+			name:        "segmen bersebelahan berbalik arah",
+			geofence:    GeofenceDB{Type: "polygon", GeoType: "Polygon"},
+			coordinates: [][][]float64{{{0, 0}, {2, 0}, {1, 0}, {1, 1}, {0, 0}}},
+			wantField:   "coordinates[0]",
+		},
+		{
+			name:     "hole memotong ring luar",
+			geofence: GeofenceDB{Type: "polygon", GeoType: "Polygon"},
+			coordinates: [][][]float64{
+				{{0, 0}, {4, 0}, {4, 4}, {0, 4}, {0, 0}},
+				{{3, 1}, {5, 1}, {5, 2}, {3, 2}, {3, 1}},
+			},
+			wantField: "coordinates[1]",
+		},
+		{
+			name:        "ring tanpa luas",
+			geofence:    GeofenceDB{Type: "polygon", GeoType: "Polygon"},
+			coordinates: [][][]float64{{{0, 0}, {1, 1}, {2, 2}, {3, 3}, {0, 0}}},
+			wantField:   "coordinates[0]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := tt.geofence
+			err := NormalizeGeometry(&g, tt.coordinates)
+
+			var errs error_handlers.ValidationErrors
+			if !errors.As(err, &errs) {
+				t.Fatalf("NormalizeGeometry() error = %v, want ValidationErrors", err)
+			}
+			for _, fieldErr := range errs {
+				if fieldErr.Field == tt.wantField {
+					return
+				}
+			}
+			t.Errorf("NormalizeGeometry() error = %v, want field %q", err, tt.wantField)
+		})
+	}
+}
+
+func coords(items ...interface{}) GeoJSONCoords {
+	return toCoordsList(items...)
+}
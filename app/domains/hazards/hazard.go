@@ -0,0 +1,44 @@
+package hazards
+
+import (
+	"math"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+// HazardApproach — jarak terdekat lintasan proyeksi kapal ke satu marker
+// selama jendela lookahead
+type HazardApproach struct {
+	Marker *models.Marker
+	// jarak terdekat (NM) dan kapan terjadi
+	DistanceNm float64
+	At         time.Time
+	// menit dari sekarang; 0 berarti kapal sudah paling dekat saat ini
+	Minutes float64
+}
+
+// ApproachMarker memproyeksikan kapal dengan course & speed tetap dan mencari
+// jarak terdekat ke marker dalam rentang [now, now+lookahead]. Marker dianggap
+// target diam sehingga dihitung dengan CPA biasa lalu dibatasi ke jendela.
+func ApproachMarker(own geo.Motion, marker *models.Marker, now time.Time, lookahead time.Duration) HazardApproach {
+	own = own.DeadReckon(now)
+	target := geo.Motion{Point: geo.Point{Lat: marker.Lat, Lon: marker.Lng}, At: now}
+	approach := geo.ClosestApproach(own, target)
+
+	result := HazardApproach{Marker: marker, DistanceNm: approach.DistanceMeters / geo.MetersPerNauticalMile, At: now}
+	switch {
+	case approach.Tcpa <= 0 || own.Speed <= 0:
+		// sudah menjauh atau diam: saat ini paling dekat
+	case approach.Tcpa >= lookahead:
+		end := own.DeadReckon(now.Add(lookahead))
+		result.DistanceNm = geo.DistanceMeters(end.Point, target.Point) / geo.MetersPerNauticalMile
+		result.At = end.At
+	default:
+		result.DistanceNm = approach.CpaMeters / geo.MetersPerNauticalMile
+		result.At = now.Add(approach.Tcpa)
+	}
+	result.Minutes = math.Max(0, result.At.Sub(now).Minutes())
+	return result
+}
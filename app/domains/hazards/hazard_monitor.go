@@ -0,0 +1,295 @@
+package hazards
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/domains/alerts"
+	"github.com/khoirulhasin/untirta_api/app/domains/devices"
+	"github.com/khoirulhasin/untirta_api/app/domains/marker_types"
+	"github.com/khoirulhasin/untirta_api/app/domains/markers"
+	"github.com/khoirulhasin/untirta_api/app/domains/ships"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+// MonitorConfig dibaca dari environment:
+//
+//	HAZARD_EVAL_INTERVAL  interval evaluasi, default "30s" ("0" = nonaktif)
+//	HAZARD_LOOKAHEAD      panjang proyeksi lintasan, default "15m"
+//	HAZARD_RADIUS_NM      radius bila marker type tidak mengatur alertRadiusNm, default 0.5
+//	HAZARD_MAX_AGE        umur posisi maksimal, default "10m"
+type MonitorConfig struct {
+	Interval  time.Duration
+	Lookahead time.Duration
+	RadiusNm  float64
+	MaxAge    time.Duration
+}
+
+func DefaultMonitorConfig() MonitorConfig {
+	return MonitorConfig{
+		Interval:  30 * time.Second,
+		Lookahead: 15 * time.Minute,
+		RadiusNm:  0.5,
+		MaxAge:    10 * time.Minute,
+	}
+}
+
+// LoadMonitorConfig membaca konfigurasi monitor hazard dari environment
+func LoadMonitorConfig() (MonitorConfig, error) {
+	config := DefaultMonitorConfig()
+
+	durations := map[string]*time.Duration{
+		"HAZARD_EVAL_INTERVAL": &config.Interval,
+		"HAZARD_LOOKAHEAD":     &config.Lookahead,
+		"HAZARD_MAX_AGE":       &config.MaxAge,
+	}
+	for key, target := range durations {
+		if raw := strings.TrimSpace(os.Getenv(key)); raw != "" {
+			value, err := time.ParseDuration(raw)
+			if err != nil {
+				return config, fmt.Errorf("invalid %s: %w", key, err)
+			}
+			*target = value
+		}
+	}
+
+	if raw := strings.TrimSpace(os.Getenv("HAZARD_RADIUS_NM")); raw != "" {
+		value, err := strconv.ParseFloat(raw, 64)
+		if err != nil || value < 0 {
+			return config, fmt.Errorf("invalid HAZARD_RADIUS_NM: %q", raw)
+		}
+		config.RadiusNm = value
+	}
+
+	return config, nil
+}
+
+// Monitor memproyeksikan lintasan kapal own-fleet berdasarkan COG/SOG dan
+// membuat alert HAZARD_PROXIMITY selama lintasan itu melewati radius marker
+// aktif (start/end) dalam jendela lookahead
+type Monitor struct {
+	deviceRepository     devices.DeviceRepository
+	markerRepository     markers.MarkerRepository
+	markerTypeRepository marker_types.MarkerTypeRepository
+	shipMongotory        ships.ShipMongotory
+	alertRepository      alerts.AlertRepository
+	config               MonitorConfig
+}
+
+func NewMonitor(deviceRepository devices.DeviceRepository, markerRepository markers.MarkerRepository, markerTypeRepository marker_types.MarkerTypeRepository, shipMongotory ships.ShipMongotory, alertRepository alerts.AlertRepository, config MonitorConfig) *Monitor {
+	return &Monitor{
+		deviceRepository:     deviceRepository,
+		markerRepository:     markerRepository,
+		markerTypeRepository: markerTypeRepository,
+		shipMongotory:        shipMongotory,
+		alertRepository:      alertRepository,
+		config:               config,
+	}
+}
+
+func (m *Monitor) Start(ctx context.Context) {
+	if m.config.Interval <= 0 {
+		log.Printf("hazard monitor disabled (HAZARD_EVAL_INTERVAL=0)")
+		return
+	}
+
+	log.Printf("hazard monitor started: interval %s, lookahead %s, default radius %.2f NM", m.config.Interval, m.config.Lookahead, m.config.RadiusNm)
+	ticker := time.NewTicker(m.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		if err := m.evaluate(ctx); err != nil {
+			log.Printf("hazard monitor: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// vessel — satu kapal (atau perangkat tanpa kapal) dengan posisi terbarunya
+type vessel struct {
+	subject  string
+	shipID   *int
+	imei     string
+	position *ships.VesselPosition
+}
+
+func (m *Monitor) evaluate(ctx context.Context) error {
+	markerTypes, err := m.markerTypeRepository.GetAllMarkerTypes(ctx)
+	if err != nil {
+		return err
+	}
+	maxRadiusNm := m.config.RadiusNm
+	for _, markerType := range markerTypes {
+		maxRadiusNm = math.Max(maxRadiusNm, m.radiusNm(markerType))
+	}
+
+	now := time.Now()
+	sweep := alerts.NewSweep(models.AlertKindHazardProximity)
+	vessels, err := m.vessels(ctx, now, sweep)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range vessels {
+		if v.failed(sweep) {
+			continue
+		}
+		if err := m.evaluateVessel(ctx, v, maxRadiusNm, sweep, now); err != nil {
+			log.Printf("hazard monitor: %s: %v", v.subject, err)
+			v.fail(sweep)
+		}
+	}
+
+	_, err = sweep.Resolve(ctx, m.alertRepository)
+	return err
+}
+
+// evaluateVessel membuat alert untuk setiap marker aktif yang dilewati lintasan kapal v
+func (m *Monitor) evaluateVessel(ctx context.Context, v *vessel, maxRadiusNm float64, sweep *alerts.Sweep, now time.Time) error {
+	own := v.position.Motion().DeadReckon(now)
+	pathNm := own.Speed * m.config.Lookahead.Hours()
+
+	candidates, err := m.markerRepository.GetMarkersWithinRadius(ctx, own.Lat, own.Lon, (pathNm+maxRadiusNm)*geo.MetersPerNauticalMile, now, now.Add(m.config.Lookahead))
+	if err != nil {
+		return err
+	}
+
+	for _, marker := range candidates {
+		radiusNm := m.radiusNm(marker.MarkerType)
+		if radiusNm <= 0 {
+			continue
+		}
+		approach := ApproachMarker(own, marker, now, m.config.Lookahead)
+		if approach.DistanceNm > radiusNm || !markers.ActiveAt(marker, approach.At) {
+			continue
+		}
+
+		alert := newHazardAlert(v, approach, severityOf(marker.MarkerType), now)
+		if _, err := m.alertRepository.RaiseAlert(ctx, alert); err != nil {
+			return err
+		}
+		sweep.Keep(alert.DedupKey)
+	}
+	return nil
+}
+
+// vessels mengelompokkan perangkat per kapal dan memakai posisi yang paling baru;
+// perangkat yang posisinya gagal dibaca menandai kapalnya gagal pada sweep
+func (m *Monitor) vessels(ctx context.Context, now time.Time, sweep *alerts.Sweep) ([]*vessel, error) {
+	allDevices, err := m.deviceRepository.GetAllDevices(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bySubject := make(map[string]*vessel)
+	var list []*vessel
+	for _, device := range allDevices {
+		position, err := m.shipMongotory.GetLatestPositionByImei(ctx, device.Imei, now.Add(-m.config.MaxAge))
+		if err != nil {
+			log.Printf("hazard monitor: device %s: %v", device.Imei, err)
+			if device.ShipID != nil {
+				sweep.Fail(*device.ShipID)
+			} else {
+				sweep.FailDevice(device.Imei)
+			}
+			continue
+		}
+		if position == nil || !geo.ValidPosition(geo.Point{Lat: position.Latitude, Lon: position.Longitude}) {
+			continue
+		}
+
+		subject := "imei:" + device.Imei
+		if device.ShipID != nil {
+			subject = fmt.Sprintf("ship:%d", *device.ShipID)
+		}
+		v, ok := bySubject[subject]
+		if !ok {
+			v = &vessel{subject: subject, shipID: device.ShipID}
+			bySubject[subject] = v
+			list = append(list, v)
+		}
+		if v.position == nil || position.Ts > v.position.Ts {
+			v.position, v.imei = position, device.Imei
+		}
+	}
+	return list, nil
+}
+
+func (v *vessel) failed(sweep *alerts.Sweep) bool {
+	if v.shipID != nil {
+		return sweep.Failed(*v.shipID)
+	}
+	return sweep.FailedDevice(v.imei)
+}
+
+func (v *vessel) fail(sweep *alerts.Sweep) {
+	if v.shipID != nil {
+		sweep.Fail(*v.shipID)
+	} else {
+		sweep.FailDevice(v.imei)
+	}
+}
+
+func (m *Monitor) radiusNm(markerType *models.MarkerType) float64 {
+	if markerType == nil || markerType.AlertRadiusNm == nil {
+		return m.config.RadiusNm
+	}
+	return *markerType.AlertRadiusNm
+}
+
+func severityOf(markerType *models.MarkerType) models.AlertSeverity {
+	if markerType == nil || markerType.AlertSeverity == nil {
+		return models.AlertSeverityWarning
+	}
+	return *markerType.AlertSeverity
+}
+
+func newHazardAlert(v *vessel, approach HazardApproach, severity models.AlertSeverity, now time.Time) *models.Alert {
+	marker := approach.Marker
+	markerID := marker.ID
+	imei := v.imei
+	latitude, longitude := v.position.Latitude, v.position.Longitude
+	distanceNm, minutes := approach.DistanceNm, approach.Minutes
+	closestAt := approach.At.UnixMilli()
+
+	label := marker.Title
+	if marker.MarkerType != nil {
+		label = marker.MarkerType.Name + " " + marker.Title
+	}
+	message := fmt.Sprintf("Lintasan kapal melewati %s pada jarak %.2f NM dalam %.1f menit", label, distanceNm, minutes)
+	if minutes == 0 {
+		message = fmt.Sprintf("Kapal berada %.2f NM dari %s", distanceNm, label)
+	}
+
+	alert := &models.Alert{
+		Kind:              models.AlertKindHazardProximity,
+		Severity:          severity,
+		DedupKey:          fmt.Sprintf("hazard:%s:%d", v.subject, markerID),
+		Message:           message,
+		ShipID:            v.shipID,
+		DeviceImei:        &imei,
+		MarkerID:          &markerID,
+		Latitude:          &latitude,
+		Longitude:         &longitude,
+		CpaNm:             &distanceNm,
+		TcpaMinutes:       &minutes,
+		ClosestApproachAt: &closestAt,
+		LastSeenAt:        now.UnixMilli(),
+	}
+	if v.position.Mmsi != 0 {
+		mmsi := v.position.Mmsi
+		alert.Mmsi = &mmsi
+	}
+	return alert
+}
@@ -0,0 +1,80 @@
+package incidents
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/domains/ships"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+type IncidentRepository interface {
+	// GetIncidentByID mengembalikan insiden beserta assignee dan timeline
+	GetIncidentByID(ctx context.Context, id int32) (*models.Incident, error)
+	PageIncident(ctx context.Context, pagination models.Pagination) (models.Pagination, error)
+	// OpenIncident membuat insiden NEW, atau mencatat deteksi ulang pada insiden
+	// dengan dedupKey yang sama (lihat quietPeriod). Hasil bool true bila insiden baru.
+	// Hanya satu insiden terbuka per dedupKey (indeks unik idx_incidents_open_dedup_key).
+	OpenIncident(ctx context.Context, incident *models.Incident, quietPeriod time.Duration) (*models.Incident, bool, error)
+	// UpdateIncidentStatus mengubah status sesuai transitions dan mencatatnya di timeline
+	UpdateIncidentStatus(ctx context.Context, id int32, status models.IncidentStatus, note *string, userID int) (*models.Incident, error)
+	// AssignIncident menugaskan insiden ke assigneeID (nil = lepas penugasan)
+	AssignIncident(ctx context.Context, id int32, assigneeID *int, note *string, userID int) (*models.Incident, error)
+	AddIncidentNote(ctx context.Context, id int32, note string, userID int) (*models.IncidentEvent, error)
+}
+
+// transitions adalah perubahan status yang diizinkan. Insiden tertutup
+// (RESOLVED/FALSE_ALARM) hanya dapat dibuka kembali ke IN_PROGRESS.
+var transitions = map[models.IncidentStatus][]models.IncidentStatus{
+	models.IncidentStatusNew:          {models.IncidentStatusAcknowledged, models.IncidentStatusInProgress, models.IncidentStatusResolved, models.IncidentStatusFalseAlarm},
+	models.IncidentStatusAcknowledged: {models.IncidentStatusInProgress, models.IncidentStatusResolved, models.IncidentStatusFalseAlarm},
+	models.IncidentStatusInProgress:   {models.IncidentStatusResolved, models.IncidentStatusFalseAlarm},
+	models.IncidentStatusResolved:     {models.IncidentStatusInProgress},
+	models.IncidentStatusFalseAlarm:   {models.IncidentStatusInProgress},
+}
+
+// CanTransition memeriksa apakah status from boleh berubah menjadi to
+func CanTransition(from, to models.IncidentStatus) bool {
+	for _, status := range transitions[from] {
+		if status == to {
+			return true
+		}
+	}
+	return false
+}
+
+// Closed — insiden RESOLVED atau FALSE_ALARM
+func Closed(status models.IncidentStatus) bool {
+	return status == models.IncidentStatusResolved || status == models.IncidentStatusFalseAlarm
+}
+
+// DedupKey mengelompokkan deteksi seperti GetMobShips: pesan type 14 per
+// (mmsi, text), posisi SART/MOB/EPIRB per mmsi
+func DedupKey(event *ships.MobEvent) string {
+	if event.MessageType == 14 {
+		text := ""
+		if event.Text != nil {
+			text = *event.Text
+		}
+		return fmt.Sprintf("mob:%d:%s", event.Mmsi, text)
+	}
+	return fmt.Sprintf("mob:%d", event.Mmsi)
+}
+
+// NewIncident membuat insiden NEW dari satu deteksi ais_mob
+func NewIncident(event *ships.MobEvent) *models.Incident {
+	return &models.Incident{
+		Kind:           event.Kind,
+		Status:         models.IncidentStatusNew,
+		DedupKey:       DedupKey(event),
+		Mmsi:           event.Mmsi,
+		MessageType:    event.MessageType,
+		Text:           event.Text,
+		Latitude:       event.Latitude,
+		Longitude:      event.Longitude,
+		DetectionCount: 1,
+		FirstSeenAt:    event.Ts,
+		LastSeenAt:     event.Ts,
+	}
+}
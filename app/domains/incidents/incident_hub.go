@@ -0,0 +1,52 @@
+package incidents
+
+import (
+	"sync"
+
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+// Hub menyebarkan insiden baru ke subscriber GraphQL. Subscriber yang lambat
+// tidak menahan monitor: insiden untuknya dibuang bila buffer penuh.
+type Hub struct {
+	mu     sync.RWMutex
+	nextID int
+	subs   map[int]chan *models.Incident
+}
+
+func NewHub() *Hub {
+	return &Hub{subs: make(map[int]chan *models.Incident)}
+}
+
+// Subscribe mendaftarkan subscriber baru. Fungsi kedua wajib dipanggil untuk berhenti.
+func (h *Hub) Subscribe(buffer int) (<-chan *models.Incident, func()) {
+	ch := make(chan *models.Incident, buffer)
+
+	h.mu.Lock()
+	id := h.nextID
+	h.nextID++
+	h.subs[id] = ch
+	h.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			h.mu.Lock()
+			delete(h.subs, id)
+			h.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+func (h *Hub) Publish(incident *models.Incident) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for _, ch := range h.subs {
+		select {
+		case ch <- incident:
+		default:
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package incidents
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/domains/ships"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/ais"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+// MonitorConfig dibaca dari environment:
+//
+//	INCIDENT_POLL_INTERVAL  interval pembacaan ais_mob, default "1m" ("0" = hanya dari ingest)
+//	INCIDENT_LOOKBACK       panjang window pembacaan ais_mob, default "30m"
+//	INCIDENT_QUIET_PERIOD   deteksi setelah insiden ditutup membuka insiden baru
+//	                        bila sinyal sempat hilang selama ini, default "30m"
+type MonitorConfig struct {
+	PollInterval time.Duration
+	Lookback     time.Duration
+	QuietPeriod  time.Duration
+}
+
+func DefaultMonitorConfig() MonitorConfig {
+	return MonitorConfig{
+		PollInterval: time.Minute,
+		Lookback:     30 * time.Minute,
+		QuietPeriod:  30 * time.Minute,
+	}
+}
+
+// LoadMonitorConfig membaca konfigurasi monitor insiden dari environment
+func LoadMonitorConfig() (MonitorConfig, error) {
+	config := DefaultMonitorConfig()
+
+	durations := map[string]*time.Duration{
+		"INCIDENT_POLL_INTERVAL": &config.PollInterval,
+		"INCIDENT_LOOKBACK":      &config.Lookback,
+		"INCIDENT_QUIET_PERIOD":  &config.QuietPeriod,
+	}
+	for key, target := range durations {
+		if raw := strings.TrimSpace(os.Getenv(key)); raw != "" {
+			value, err := time.ParseDuration(raw)
+			if err != nil || value < 0 {
+				return config, fmt.Errorf("invalid %s: %q", key, raw)
+			}
+			*target = value
+		}
+	}
+
+	return config, nil
+}
+
+// Monitor membuka insiden dari deteksi MOB/SART/EPIRB. Dokumen dari ingest
+// diproses seketika; ais_mob juga dibaca berkala agar dokumen yang ditulis
+// collector lain (atau saat server mati) tetap tercatat. Transmisi uji
+// (SART TEST, status 15) diabaikan.
+type Monitor struct {
+	incidentRepository IncidentRepository
+	shipMongotory      ships.ShipMongotory
+	aisIngestor        *ais.Ingestor
+	hub                *Hub
+	config             MonitorConfig
+}
+
+func NewMonitor(incidentRepository IncidentRepository, shipMongotory ships.ShipMongotory, aisIngestor *ais.Ingestor, hub *Hub, config MonitorConfig) *Monitor {
+	return &Monitor{
+		incidentRepository: incidentRepository,
+		shipMongotory:      shipMongotory,
+		aisIngestor:        aisIngestor,
+		hub:                hub,
+		config:             config,
+	}
+}
+
+func (m *Monitor) Start(ctx context.Context) {
+	docs, unsubscribe := m.aisIngestor.SubscribeMobs(256)
+	defer unsubscribe()
+
+	var tick <-chan time.Time
+	if m.config.PollInterval > 0 {
+		ticker := time.NewTicker(m.config.PollInterval)
+		defer ticker.Stop()
+		tick = ticker.C
+		m.poll(ctx)
+	}
+	log.Printf("incident monitor started: poll interval %s, lookback %s, quiet period %s", m.config.PollInterval, m.config.Lookback, m.config.QuietPeriod)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case doc, ok := <-docs:
+			if !ok {
+				return
+			}
+			if event, ok := ships.NewMobEventFromDocument(doc); ok {
+				m.handle(ctx, event)
+			}
+		case <-tick:
+			m.poll(ctx)
+		}
+	}
+}
+
+func (m *Monitor) poll(ctx context.Context) {
+	end := time.Now()
+	start := end.Add(-m.config.Lookback)
+
+	docs, err := m.shipMongotory.GetMobShips(models.DurationTimeInput{Start: start.Unix(), End: end.Unix()})
+	if err != nil {
+		log.Printf("incident monitor: read ais_mob failed: %v", err)
+		return
+	}
+	// GetMobShips terurut terbaru dulu; proses dari yang terlama
+	for i := len(docs) - 1; i >= 0; i-- {
+		m.handle(ctx, ships.NewMobEvent(docs[i]))
+	}
+}
+
+func (m *Monitor) handle(ctx context.Context, event *ships.MobEvent) {
+	if !event.Active {
+		return
+	}
+
+	incident, opened, err := m.incidentRepository.OpenIncident(ctx, NewIncident(event), m.config.QuietPeriod)
+	if err != nil {
+		log.Printf("incident monitor: %s: %v", DedupKey(event), err)
+		return
+	}
+	if opened {
+		log.Printf("incident %d opened: %s %s", incident.ID, incident.Kind, incident.DedupKey)
+		m.hub.Publish(incident)
+	}
+}
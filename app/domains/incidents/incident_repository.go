@@ -0,0 +1,263 @@
+package incidents
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/pkg"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"gorm.io/gorm"
+)
+
+type incidentRepository struct {
+	db *gorm.DB
+}
+
+func NewIncidentRepository(db *gorm.DB) *incidentRepository {
+	return &incidentRepository{
+		db,
+	}
+}
+
+var _ IncidentRepository = &incidentRepository{}
+
+func (r *incidentRepository) GetIncidentByID(ctx context.Context, id int32) (*models.Incident, error) {
+
+	var incident = &models.Incident{}
+
+	err := r.db.WithContext(ctx).
+		Preload("Assignee").
+		Preload("Events", func(db *gorm.DB) *gorm.DB {
+			return db.Order("created_at ASC, id ASC")
+		}).
+		Where("id = ?", id).
+		Take(&incident).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return incident, nil
+}
+
+func (r *incidentRepository) PageIncident(ctx context.Context, pagination models.Pagination) (models.Pagination, error) {
+	var incidents []models.Incident
+
+	var err = r.db.WithContext(ctx).
+		Scopes(pkg.Paginate(&models.Incident{}, &pagination, r.db)).
+		Preload("Assignee").
+		Find(&incidents).Error
+	pagination.Rows = make([]any, len(incidents))
+	for i, incident := range incidents {
+		pagination.Rows[i] = incident
+	}
+
+	if err != nil {
+		return pagination, err
+	}
+
+	return pagination, nil
+
+}
+
+// openStatuses harus sama dengan kondisi indeks unik idx_incidents_open_dedup_key
+var openStatuses = []models.IncidentStatus{
+	models.IncidentStatusNew,
+	models.IncidentStatusAcknowledged,
+	models.IncidentStatusInProgress,
+}
+
+func (r *incidentRepository) OpenIncident(ctx context.Context, incident *models.Incident, quietPeriod time.Duration) (*models.Incident, bool, error) {
+	result, opened, err := r.openIncident(ctx, incident, quietPeriod)
+	if error_handlers.IsUniqueViolation(err) {
+		// proses lain baru saja membuka insiden dengan dedupKey yang sama;
+		// ulangi sebagai deteksi ulang pada insiden tersebut
+		return r.openIncident(ctx, incident, quietPeriod)
+	}
+	return result, opened, err
+}
+
+func (r *incidentRepository) openIncident(ctx context.Context, incident *models.Incident, quietPeriod time.Duration) (*models.Incident, bool, error) {
+	var opened bool
+	var result *models.Incident
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// insiden terbuka didahulukan, lalu insiden tertutup terakhir
+		var existing = &models.Incident{}
+		err := tx.Where("dedup_key = ? AND status IN ?", incident.DedupKey, openStatuses).Take(&existing).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = tx.Where("dedup_key = ?", incident.DedupKey).
+				Order("last_seen_at DESC").
+				Take(&existing).Error
+		}
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+
+		// deteksi ulang: insiden masih terbuka, atau sinyal belum sempat hilang
+		// selama quietPeriod sejak insiden ditutup
+		if err == nil && (!Closed(existing.Status) || incident.LastSeenAt-existing.LastSeenAt <= quietPeriod.Milliseconds()) {
+			result = existing
+			if incident.LastSeenAt <= existing.LastSeenAt {
+				return nil
+			}
+			updates := map[string]any{
+				"last_seen_at":    incident.LastSeenAt,
+				"detection_count": gorm.Expr("detection_count + 1"),
+			}
+			if incident.Latitude != nil && incident.Longitude != nil {
+				updates["latitude"] = incident.Latitude
+				updates["longitude"] = incident.Longitude
+			}
+			if err := tx.Model(&existing).Updates(updates).Error; err != nil {
+				return err
+			}
+			return tx.Where("id = ?", existing.ID).Take(&existing).Error
+		}
+
+		incident.Status = models.IncidentStatusNew
+		if err := tx.Create(&incident).Error; err != nil {
+			return err
+		}
+		event := &models.IncidentEvent{
+			IncidentID: incident.ID,
+			Kind:       models.IncidentEventKindOpened,
+			ToStatus:   &incident.Status,
+			Note:       incident.Text,
+		}
+		if err := tx.Create(&event).Error; err != nil {
+			return err
+		}
+		incident.Events = []*models.IncidentEvent{event}
+		result, opened = incident, true
+		return nil
+	})
+	if err != nil {
+		return nil, false, err
+	}
+
+	return result, opened, nil
+}
+
+func (r *incidentRepository) UpdateIncidentStatus(ctx context.Context, id int32, status models.IncidentStatus, note *string, userID int) (*models.Incident, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var incident = &models.Incident{}
+		if err := tx.Where("id = ?", id).Take(&incident).Error; err != nil {
+			return err
+		}
+		from := incident.Status
+		if !CanTransition(from, status) {
+			return fmt.Errorf("insiden berstatus %s tidak dapat diubah menjadi %s", from, status)
+		}
+
+		now := time.Now().UnixMilli()
+		updates := map[string]any{"status": status}
+		switch {
+		case Closed(status):
+			updates["resolved_at"] = now
+			updates["resolved_by"] = userID
+		case Closed(from):
+			// dibuka kembali
+			updates["resolved_at"] = nil
+			updates["resolved_by"] = nil
+		}
+		if incident.AcknowledgedAt == nil && status != models.IncidentStatusNew {
+			updates["acknowledged_at"] = now
+			updates["acknowledged_by"] = userID
+		}
+		if err := tx.Model(&incident).Updates(updates).Error; err != nil {
+			if error_handlers.IsUniqueViolation(err) {
+				return fmt.Errorf("insiden lain untuk %s masih terbuka", incident.DedupKey)
+			}
+			return err
+		}
+
+		return tx.Create(&models.IncidentEvent{
+			IncidentID: incident.ID,
+			Kind:       models.IncidentEventKindStatusChanged,
+			FromStatus: &from,
+			ToStatus:   &status,
+			Note:       trimNote(note),
+			UserID:     &userID,
+			CreatedBy:  userID,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetIncidentByID(ctx, id)
+}
+
+func (r *incidentRepository) AssignIncident(ctx context.Context, id int32, assigneeID *int, note *string, userID int) (*models.Incident, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var incident = &models.Incident{}
+		if err := tx.Where("id = ?", id).Take(&incident).Error; err != nil {
+			return err
+		}
+
+		err := tx.Model(&incident).Updates(map[string]any{
+			"assignee_id": assigneeID,
+			"updated_by":  userID,
+		}).Error
+		if err != nil {
+			return err
+		}
+
+		return tx.Create(&models.IncidentEvent{
+			IncidentID: incident.ID,
+			Kind:       models.IncidentEventKindAssigned,
+			AssigneeID: assigneeID,
+			Note:       trimNote(note),
+			UserID:     &userID,
+			CreatedBy:  userID,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return r.GetIncidentByID(ctx, id)
+}
+
+func (r *incidentRepository) AddIncidentNote(ctx context.Context, id int32, note string, userID int) (*models.IncidentEvent, error) {
+	text := trimNote(&note)
+	if text == nil {
+		var errs error_handlers.ValidationErrors
+		errs.Add("note", "catatan tidak boleh kosong")
+		return nil, errs
+	}
+
+	var incident = &models.Incident{}
+	if err := r.db.WithContext(ctx).Where("id = ?", id).Take(&incident).Error; err != nil {
+		return nil, err
+	}
+
+	event := &models.IncidentEvent{
+		IncidentID: incident.ID,
+		Kind:       models.IncidentEventKindNote,
+		Note:       text,
+		UserID:     &userID,
+		CreatedBy:  userID,
+	}
+	if err := r.db.WithContext(ctx).Create(&event).Error; err != nil {
+		return nil, err
+	}
+
+	return event, nil
+}
+
+// trimNote mengembalikan nil untuk catatan kosong
+func trimNote(note *string) *string {
+	if note == nil {
+		return nil
+	}
+	text := strings.TrimSpace(*note)
+	if text == "" {
+		return nil
+	}
+	return &text
+}
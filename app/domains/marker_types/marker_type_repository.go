@@ -30,9 +30,14 @@ func (r *markerTypeRepository) CreateMarkerType(ctx context.Context, markerType
 	return markerType, nil
 }
 
+// kolom yang ditulis update; alert_radius_nm dan alert_severity null berarti
+// kembali ke default hazard monitor sehingga harus bisa dikosongkan.
+// updated_by diisi callback global.
+var markerTypeColumns = []string{"name", "icon", "alert_radius_nm", "alert_severity", "updated_by"}
+
 func (r *markerTypeRepository) UpdateMarkerType(ctx context.Context, id int32, markerType *models.MarkerType) (*models.MarkerType, error) {
 
-	err := r.db.WithContext(ctx).Where("id = ?", id).Model(&markerType).Updates(markerType).Error
+	err := r.db.WithContext(ctx).Where("id = ?", id).Model(&markerType).Select(markerTypeColumns).Updates(markerType).Error
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +47,7 @@ func (r *markerTypeRepository) UpdateMarkerType(ctx context.Context, id int32, m
 
 func (r *markerTypeRepository) UpdateMarkerTypeByUUID(ctx context.Context, uuid string, markerType *models.MarkerType) (*models.MarkerType, error) {
 
-	err := r.db.WithContext(ctx).Where("uuid = ?", uuid).Model(&markerType).Updates(markerType).Error
+	err := r.db.WithContext(ctx).Where("uuid = ?", uuid).Model(&markerType).Select(markerTypeColumns).Updates(markerType).Error
 	if err != nil {
 		return nil, err
 	}
@@ -2,7 +2,9 @@ package markers
 
 import (
 	"context"
+	"time"
 
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
 	"github.com/khoirulhasin/untirta_api/app/models"
 )
 
@@ -14,19 +16,54 @@ type MarkerRepository interface {
 	DeleteMarkerByUUID(ctx context.Context, uuid string) error
 	GetMarkerByID(ctx context.Context, id int32) (*models.Marker, error)
 	GetMarkerByUUID(ctx context.Context, uuid string) (*models.Marker, error)
-	GetAllMarkers(ctx context.Context) ([]*models.Marker, error)
+	GetAllMarkers(ctx context.Context, activeAt *time.Time) ([]*models.Marker, error)
 	PageMarker(ctx context.Context, pagination models.Pagination) (models.Pagination, error)
-	GetNearestMarkers(ctx context.Context, imei string, lat, lng float64, limit int) ([]*NearestMarkerResponse, error)
+	GetNearestMarkers(ctx context.Context, filter NearestMarkerFilter) ([]*NearestMarkerResponse, error)
+	GetMarkersWithinRadius(ctx context.Context, lat, lng, radiusMeters float64, from, to time.Time) ([]*models.Marker, error)
+}
+
+type MarkerRedistory interface {
+	GetMarkerClusters(ctx context.Context, bbox geo.BoundingBox, zoom int, activeAt time.Time) ([]*geo.Cluster, error)
+}
+
+const (
+	defaultNearestMarkers = 10
+	maxNearestMarkers     = 50
+	// batas halaman kandidat saat marker berulang disaring di Go
+	nearestMarkerPages = 10
+)
+
+// NearestMarkerFilter — titik asal dan filter pencarian marker terdekat
+type NearestMarkerFilter struct {
+	Lat           float64
+	Lng           float64
+	Limit         int
+	MarkerTypeIDs []int
+	RadiusMeters  *float64
+	// waktu evaluasi marker aktif (termasuk RRULE); zero berarti sekarang
+	At time.Time
 }
 
 type NearestMarkerResponse struct {
-	Lat        float64          `json:"lat"`
-	Lng        float64          `json:"lng"`
+	ID          int     `json:"id"`
+	UUID        string  `json:"uuid"`
+	Title       string  `json:"title"`
+	Description *string `json:"description,omitempty"`
+	Lat         float64 `json:"lat"`
+	Lng         float64 `json:"lng"`
+	// Start/End adalah kejadian yang aktif untuk marker berulang
+	Start int64   `json:"start"`
+	End   int64   `json:"end"`
+	Rrule *string `json:"rrule,omitempty"`
+	// Distance dalam km, dipertahankan untuk klien lama
 	Distance   float64          `json:"distance"`
+	DistanceNm float64          `json:"distanceNm"`
+	Bearing    float64          `json:"bearing"`
 	MarkerType MarkerTypeSimple `json:"markerType"`
 }
 
 type MarkerTypeSimple struct {
 	ID   int    `json:"id"`
 	Name string `json:"name"`
+	Icon string `json:"icon"`
 }
@@ -0,0 +1,115 @@
+package markers
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/domains/ships"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
+)
+
+// posisi perangkat lebih tua dari ini dianggap tidak diketahui
+const defaultPositionMaxAge = 24 * time.Hour
+
+// NearestMarkerQuery — asal pencarian berupa lat/lng atau imei (posisi
+// terakhir dari ais_dynamic); lat/lng didahulukan bila keduanya dikirim
+type NearestMarkerQuery struct {
+	Imei          *string
+	Lat           *float64
+	Lng           *float64
+	Limit         int
+	MarkerTypeIDs []int
+	RadiusNm      *float64
+	MaxAge        time.Duration
+	// waktu evaluasi marker aktif; zero berarti sekarang
+	ActiveAt time.Time
+}
+
+// NearestOrigin — titik asal yang dipakai; Ts terisi bila berasal dari AIS
+type NearestOrigin struct {
+	Lat    float64  `json:"lat"`
+	Lng    float64  `json:"lng"`
+	Source string   `json:"source"`
+	Ts     *int64   `json:"ts,omitempty"`
+	Sog    *float64 `json:"sog,omitempty"`
+	Cog    *float64 `json:"cog,omitempty"`
+}
+
+type NearestMarkers struct {
+	Imei    *string                  `json:"imei,omitempty"`
+	Origin  NearestOrigin            `json:"origin"`
+	Markers []*NearestMarkerResponse `json:"markers"`
+}
+
+// Locator mencari marker terdekat dari titik atau dari posisi terakhir perangkat
+type Locator struct {
+	markerRepository MarkerRepository
+	shipMongotory    ships.ShipMongotory
+}
+
+func NewLocator(markerRepository MarkerRepository, shipMongotory ships.ShipMongotory) *Locator {
+	return &Locator{
+		markerRepository: markerRepository,
+		shipMongotory:    shipMongotory,
+	}
+}
+
+func (l *Locator) Nearest(ctx context.Context, query NearestMarkerQuery) (*NearestMarkers, error) {
+	result := &NearestMarkers{Imei: query.Imei}
+
+	switch {
+	case query.Lat != nil && query.Lng != nil:
+		result.Origin = NearestOrigin{Lat: *query.Lat, Lng: *query.Lng, Source: "INPUT"}
+	case query.Lat != nil || query.Lng != nil:
+		return nil, errors.New("lat dan lng harus dikirim bersamaan")
+	case query.Imei != nil && *query.Imei != "":
+		maxAge := query.MaxAge
+		if maxAge <= 0 {
+			maxAge = defaultPositionMaxAge
+		}
+		position, err := l.shipMongotory.GetLatestPositionByImei(ctx, *query.Imei, time.Now().Add(-maxAge))
+		if err != nil {
+			return nil, err
+		}
+		if position == nil {
+			return nil, errors.New("posisi terakhir perangkat tidak ditemukan")
+		}
+		result.Origin = NearestOrigin{
+			Lat:    position.Latitude,
+			Lng:    position.Longitude,
+			Source: "IMEI",
+			Ts:     &position.Ts,
+			Sog:    position.Sog,
+			Cog:    position.Cog,
+		}
+	default:
+		return nil, errors.New("imei atau lat/lng wajib diisi")
+	}
+
+	if !geo.ValidPosition(geo.Point{Lat: result.Origin.Lat, Lon: result.Origin.Lng}) {
+		return nil, errors.New("posisi asal di luar rentang koordinat")
+	}
+	if query.RadiusNm != nil && *query.RadiusNm <= 0 {
+		return nil, errors.New("radiusNm harus lebih dari 0")
+	}
+
+	filter := NearestMarkerFilter{
+		Lat:           result.Origin.Lat,
+		Lng:           result.Origin.Lng,
+		Limit:         query.Limit,
+		MarkerTypeIDs: query.MarkerTypeIDs,
+		At:            query.ActiveAt,
+	}
+	if query.RadiusNm != nil {
+		radius := *query.RadiusNm * geo.MetersPerNauticalMile
+		filter.RadiusMeters = &radius
+	}
+
+	markers, err := l.markerRepository.GetNearestMarkers(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	result.Markers = markers
+	return result, nil
+}
@@ -0,0 +1,185 @@
+package markers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const (
+	markerClusterMaxTiles   = 16
+	markerClusterMaxMembers = 10
+	// marker aktif berdasarkan start/end dan RRULE, jadi cache tidak boleh terlalu lama
+	markerClusterTTL = 1 * time.Minute
+)
+
+// markerRedistory — query marker dari Postgres dengan cache Redis
+type markerRedistory struct {
+	db    *gorm.DB
+	redis *redis.Client
+}
+
+func NewMarkerRedistory(db *gorm.DB, redis *redis.Client) *markerRedistory {
+	return &markerRedistory{
+		db:    db,
+		redis: redis,
+	}
+}
+
+var _ MarkerRedistory = &markerRedistory{}
+
+// GetMarkerClusters mengelompokkan marker yang aktif pada activeAt ke grid sesuai
+// zoom peta. Cache per tile memakai fingerprint tabel markers sehingga
+// create/update/delete (lewat GraphQL maupun REST) langsung membuat cache lama
+// tidak terpakai, dan dibedakan per menit activeAt karena kejadian RRULE.
+func (r *markerRedistory) GetMarkerClusters(ctx context.Context, bbox geo.BoundingBox, zoom int, activeAt time.Time) ([]*geo.Cluster, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var source string
+	if v := ctx.Value("X-Source"); v != nil {
+		if s, ok := v.(string); ok {
+			source = s
+		}
+	}
+
+	fingerprint, err := r.fingerprint(timeoutCtx)
+	if err != nil {
+		return nil, err
+	}
+
+	tiles := geo.TilesUpTo(bbox, markerClusterMaxTiles, geo.ClusterMaxTileZoom(zoom))
+	byTile := make(map[geo.Tile][]*geo.Cluster, len(tiles))
+	cacheKeys := make(map[geo.Tile]string, len(tiles))
+	var missing []geo.Tile
+
+	for _, tile := range tiles {
+		cacheKey := fmt.Sprintf("markers:clusters:v2:%s:%d:%s:%d", fingerprint, activeAt.Unix()/60, tile, zoom)
+		cacheKeys[tile] = cacheKey
+
+		if source != "crontab" {
+			if cached, err := r.redis.Get(timeoutCtx, cacheKey).Result(); err == nil {
+				var clusters []*geo.Cluster
+				if err := json.Unmarshal([]byte(cached), &clusters); err == nil {
+					byTile[tile] = clusters
+					continue
+				}
+				log.Printf("redis unmarshal failed: %v", err)
+			} else if err != redis.Nil {
+				log.Printf("redis get error: %v", err)
+			}
+		}
+		missing = append(missing, tile)
+	}
+
+	if len(missing) > 0 {
+		points, err := r.activeMarkerPoints(timeoutCtx, missing, activeAt)
+		if err != nil {
+			return nil, err
+		}
+
+		z := missing[0].Z
+		byTilePoints := make(map[geo.Tile][]geo.ClusterPoint, len(missing))
+		for _, tile := range missing {
+			byTilePoints[tile] = nil
+		}
+		for _, p := range points {
+			tile := geo.TileAt(p.Point, z)
+			if list, ok := byTilePoints[tile]; ok {
+				byTilePoints[tile] = append(list, p)
+			}
+		}
+
+		for _, tile := range missing {
+			byTile[tile] = geo.GridCluster(byTilePoints[tile], zoom, markerClusterMaxMembers)
+
+			data, err := json.Marshal(byTile[tile])
+			if err != nil {
+				log.Printf("marshal error for redis: %v", err)
+				continue
+			}
+			if err := r.redis.Set(timeoutCtx, cacheKeys[tile], data, markerClusterTTL).Err(); err != nil {
+				log.Printf("redis set error: %v", err)
+			}
+		}
+	}
+
+	results := []*geo.Cluster{}
+	for _, tile := range tiles {
+		for _, cluster := range byTile[tile] {
+			if bbox.Contains(geo.Point{Lat: cluster.Latitude, Lon: cluster.Longitude}) {
+				results = append(results, cluster)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// fingerprint berubah setiap ada marker dibuat, diubah, atau dihapus (soft delete)
+func (r *markerRedistory) fingerprint(ctx context.Context) (string, error) {
+	var row struct {
+		Total     int64 `gorm:"column:total"`
+		UpdatedAt int64 `gorm:"column:updated_at"`
+		DeletedAt int64 `gorm:"column:deleted_at"`
+	}
+	err := r.db.WithContext(ctx).Raw(`
+		SELECT COUNT(*) AS total,
+			COALESCE(MAX(updated_at), 0) AS updated_at,
+			COALESCE(MAX(deleted_at), 0) AS deleted_at
+		FROM markers`).Scan(&row).Error
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%d-%d-%d", row.Total, row.UpdatedAt, row.DeletedAt), nil
+}
+
+func (r *markerRedistory) activeMarkerPoints(ctx context.Context, tiles []geo.Tile, at time.Time) ([]geo.ClusterPoint, error) {
+	areas := r.db.Session(&gorm.Session{NewDB: true})
+	for i, tile := range tiles {
+		box := tile.BoundingBox()
+		condition := "lat BETWEEN ? AND ? AND lng BETWEEN ? AND ?"
+		if i == 0 {
+			areas = areas.Where(condition, box.MinLat, box.MaxLat, box.MinLon, box.MaxLon)
+		} else {
+			areas = areas.Or(condition, box.MinLat, box.MaxLat, box.MinLon, box.MaxLon)
+		}
+	}
+
+	var rows []struct {
+		ID       int     `gorm:"column:id"`
+		Lat      float64 `gorm:"column:lat"`
+		Lng      float64 `gorm:"column:lng"`
+		Start    int64   `gorm:"column:start"`
+		End      int64   `gorm:"column:end"`
+		Rrule    *string `gorm:"column:rrule"`
+		Timezone *string `gorm:"column:timezone"`
+	}
+	condition, args := activeCondition("", at.Unix())
+	err := r.db.WithContext(ctx).
+		Table("markers").
+		Select(`id, lat, lng, start, "end", rrule, timezone`).
+		Where("deleted_at = 0").
+		Where(condition, args...).
+		Where(areas).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]geo.ClusterPoint, 0, len(rows))
+	for _, row := range rows {
+		if row.Rrule != nil && !ActiveAt(&models.Marker{Start: row.Start, End: row.End, Rrule: row.Rrule, Timezone: row.Timezone}, at) {
+			continue
+		}
+		points = append(points, geo.ClusterPoint{ID: int64(row.ID), Point: geo.Point{Lat: row.Lat, Lon: row.Lng}})
+	}
+	return points, nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
 	"github.com/khoirulhasin/untirta_api/app/infrastructures/pkg"
 	"github.com/khoirulhasin/untirta_api/app/models"
 	"gorm.io/gorm"
@@ -23,6 +24,9 @@ func NewMarkerRepository(db *gorm.DB) *markerRepository {
 var _ MarkerRepository = &markerRepository{}
 
 func (r *markerRepository) CreateMarker(ctx context.Context, marker *models.Marker) (*models.Marker, error) {
+	if err := NormalizeSchedule(marker); err != nil {
+		return nil, err
+	}
 
 	err := r.db.WithContext(ctx).Create(&marker).Error
 	if err != nil {
@@ -33,18 +37,30 @@ func (r *markerRepository) CreateMarker(ctx context.Context, marker *models.Mark
 }
 
 func (r *markerRepository) UpdateMarker(ctx context.Context, id int32, marker *models.Marker) (*models.Marker, error) {
-
-	err := r.db.WithContext(ctx).Where("id = ?", id).Model(&marker).Updates(marker).Error
-	if err != nil {
-		return nil, err
-	}
-
-	return marker, nil
+	return r.updateMarker(ctx, "id = ?", id, marker)
 }
 
 func (r *markerRepository) UpdateMarkerByUUID(ctx context.Context, uuid string, marker *models.Marker) (*models.Marker, error) {
+	return r.updateMarker(ctx, "uuid = ?", uuid, marker)
+}
 
-	err := r.db.WithContext(ctx).Where("uuid = ?", uuid).Model(&marker).Updates(marker).Error
+// updateMarker menulis kolom jadwal secara eksplisit karena Updates(struct)
+// melewati nilai nil, sehingga RRULE yang dihapus tidak akan tersimpan
+func (r *markerRepository) updateMarker(ctx context.Context, condition string, key interface{}, marker *models.Marker) (*models.Marker, error) {
+	if err := NormalizeSchedule(marker); err != nil {
+		return nil, err
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where(condition, key).Model(&marker).Updates(marker).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Marker{}).Where(condition, key).Updates(map[string]interface{}{
+			"rrule":          marker.Rrule,
+			"timezone":       marker.Timezone,
+			"recurrence_end": marker.RecurrenceEnd,
+		}).Error
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -102,15 +118,27 @@ func (r *markerRepository) GetMarkerByUUID(ctx context.Context, uuid string) (*m
 	return marker, nil
 }
 
-func (r *markerRepository) GetAllMarkers(ctx context.Context) ([]*models.Marker, error) {
+// GetAllMarkers mengembalikan semua marker; dengan activeAt hanya marker yang
+// aktif pada waktu itu (jendela start/end atau kejadian RRULE)
+func (r *markerRepository) GetAllMarkers(ctx context.Context, activeAt *time.Time) ([]*models.Marker, error) {
 
 	var markers []*models.Marker
 
-	err := r.db.WithContext(ctx).Preload("MarkerType").Find(&markers).Error
+	query := r.db.WithContext(ctx).Preload("MarkerType")
+	if activeAt != nil {
+		condition, args := activeCondition("", activeAt.Unix())
+		query = query.Where(condition, args...)
+	}
+
+	err := query.Find(&markers).Error
 	if err != nil {
 		return nil, err
 	}
 
+	if activeAt != nil {
+		markers = filterActive(markers, *activeAt)
+	}
+
 	return markers, nil
 
 }
@@ -132,60 +160,179 @@ func (r *markerRepository) PageMarker(ctx context.Context, pagination models.Pag
 
 }
 
-func (r *markerRepository) GetNearestMarkers(ctx context.Context, imei string, lat, lng float64, limit int) ([]*NearestMarkerResponse, error) {
-	if limit <= 0 || limit > 10 {
-		limit = 10
+type nearestRow struct {
+	ID             int     `gorm:"column:id"`
+	UUID           string  `gorm:"column:uuid"`
+	Title          string  `gorm:"column:title"`
+	Description    *string `gorm:"column:description"`
+	Lat            float64 `gorm:"column:lat"`
+	Lng            float64 `gorm:"column:lng"`
+	Start          int64   `gorm:"column:start"`
+	End            int64   `gorm:"column:end"`
+	Rrule          *string `gorm:"column:rrule"`
+	Timezone       *string `gorm:"column:timezone"`
+	Distance       float64 `gorm:"column:distance"`
+	MarkerTypeID   int     `gorm:"column:marker_type_id"`
+	MarkerTypeName string  `gorm:"column:marker_type_name"`
+	MarkerTypeIcon string  `gorm:"column:marker_type_icon"`
+}
+
+func (r *markerRepository) GetNearestMarkers(ctx context.Context, filter NearestMarkerFilter) ([]*NearestMarkerResponse, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultNearestMarkers
+	}
+	if limit > maxNearestMarkers {
+		limit = maxNearestMarkers
 	}
 
-	currentTime := time.Now().Unix()
+	at := filter.At
+	if at.IsZero() {
+		at = time.Now()
+	}
 
 	distanceFormula := fmt.Sprintf(
-		"(6371 * acos(cos(radians(%f)) * cos(radians(lat)) * cos(radians(lng) - radians(%f)) + sin(radians(%f)) * sin(radians(lat))))",
-		lat, lng, lat,
+		"(6371 * acos(LEAST(1.0, cos(radians(%f)) * cos(radians(m.lat)) * cos(radians(m.lng) - radians(%f)) + sin(radians(%f)) * sin(radians(m.lat)))))",
+		filter.Lat, filter.Lng, filter.Lat,
 	)
+	orderBy := "distance ASC"
+	args := []interface{}{}
+	if pkg.PostGIS() {
+		// KNN lewat index GIST markers.geog, jarak tetap dalam km
+		distanceFormula = "(ST_Distance(m.geog, ST_MakePoint(?, ?)::geography) / 1000)"
+		orderBy = "m.geog <-> ST_MakePoint(?, ?)::geography"
+		args = append(args, filter.Lng, filter.Lat)
+	}
+
+	activeSQL, activeArgs := activeCondition("m.", at.Unix())
+	conditions := activeSQL + " AND m.deleted_at = 0"
+	args = append(args, activeArgs...)
+	if len(filter.MarkerTypeIDs) > 0 {
+		conditions += " AND m.marker_type_id IN ?"
+		args = append(args, filter.MarkerTypeIDs)
+	}
+	if pkg.PostGIS() {
+		conditions += " AND m.geog IS NOT NULL"
+		if filter.RadiusMeters != nil {
+			conditions += " AND ST_DWithin(m.geog, ST_MakePoint(?, ?)::geography, ?)"
+			args = append(args, filter.Lng, filter.Lat, *filter.RadiusMeters)
+		}
+		args = append(args, filter.Lng, filter.Lat)
+	} else if filter.RadiusMeters != nil {
+		conditions += " AND " + distanceFormula + " <= ?"
+		args = append(args, *filter.RadiusMeters/1000)
+	}
 
 	query := fmt.Sprintf(`
 		SELECT 
+			m.id,
+			m.uuid,
+			m.title,
+			m.description,
 			m.lat, 
 			m.lng, 
+			m.start,
+			m."end",
+			m.rrule,
+			m.timezone,
 			%s as distance,
 			mt.id as marker_type_id,
 			mt.name as marker_type_name,
 			mt.icon as marker_type_icon
 		FROM markers m
 		LEFT JOIN marker_types mt ON m.marker_type_id = mt.id
-		WHERE (m.start IS NULL OR m.start <= ?) 
-		AND (m."end" IS NULL OR m."end" >= ?) 
-		AND m.deleted_at = 0
-		ORDER BY distance ASC 
-		LIMIT ?`, distanceFormula)
-
-	var results []struct {
-		Lat            float64 `gorm:"column:lat"`
-		Lng            float64 `gorm:"column:lng"`
-		Distance       float64 `gorm:"column:distance"`
-		MarkerTypeID   int     `gorm:"column:marker_type_id"`
-		MarkerTypeName string  `gorm:"column:marker_type_name"`
-		MarkerTypeIcon string  `gorm:"column:marker_type_icon"`
-	}
-
-	err := r.db.WithContext(ctx).Raw(query, currentTime, currentTime, limit).Scan(&results).Error
-	if err != nil {
-		return nil, err
+		WHERE %s
+		ORDER BY %s 
+		LIMIT ? OFFSET ?`, distanceFormula, conditions, orderBy)
+
+	origin := geo.Point{Lat: filter.Lat, Lon: filter.Lng}
+	response := make([]*NearestMarkerResponse, 0, limit)
+
+	// marker berulang baru bisa dipastikan aktif di Go, jadi kandidat diambil
+	// per halaman sampai limit terpenuhi
+	for page := 0; page < nearestMarkerPages && len(response) < limit; page++ {
+		var results []nearestRow
+		err := r.db.WithContext(ctx).Raw(query, append(args, limit, page*limit)...).Scan(&results).Error
+		if err != nil {
+			return nil, err
+		}
+
+		for _, result := range results {
+			occurrence, ok := OccurrenceAt(&models.Marker{Start: result.Start, End: result.End, Rrule: result.Rrule, Timezone: result.Timezone}, at)
+			if !ok {
+				continue
+			}
+			response = append(response, nearestResponse(result, occurrence, origin))
+			if len(response) == limit {
+				break
+			}
+		}
+		if len(results) < limit {
+			break
+		}
 	}
 
-	var response []*NearestMarkerResponse
-	for _, result := range results {
-		response = append(response, &NearestMarkerResponse{
-			Lat:      result.Lat,
-			Lng:      result.Lng,
-			Distance: result.Distance,
-			MarkerType: MarkerTypeSimple{
-				ID:   result.MarkerTypeID,
-				Name: result.MarkerTypeName,
-			},
-		})
+	return response, nil
+}
+
+func nearestResponse(result nearestRow, occurrence *models.MarkerOccurrence, origin geo.Point) *NearestMarkerResponse {
+	return &NearestMarkerResponse{
+		ID:          result.ID,
+		UUID:        result.UUID,
+		Title:       result.Title,
+		Description: result.Description,
+		Lat:         result.Lat,
+		Lng:         result.Lng,
+		Start:       occurrence.Start,
+		End:         occurrence.End,
+		Rrule:       result.Rrule,
+		Distance:    result.Distance,
+		DistanceNm:  result.Distance * 1000 / geo.MetersPerNauticalMile,
+		Bearing:     geo.Bearing(origin, geo.Point{Lat: result.Lat, Lon: result.Lng}),
+		MarkerType: MarkerTypeSimple{
+			ID:   result.MarkerTypeID,
+			Name: result.MarkerTypeName,
+			Icon: result.MarkerTypeIcon,
+		},
 	}
+}
 
-	return response, nil
+// GetMarkersWithinRadius mengembalikan marker dalam radius meter dari titik
+// yang mungkin aktif (start/end atau RRULE) pada suatu waktu antara from dan
+// to; pemanggil memeriksa ActiveAt pada waktu yang relevan. Memakai ST_DWithin
+// bila PostGIS aktif; tanpa PostGIS disaring bounding box lalu jarak haversine.
+func (r *markerRepository) GetMarkersWithinRadius(ctx context.Context, lat, lng, radiusMeters float64, from, to time.Time) ([]*models.Marker, error) {
+	center := geo.Point{Lat: lat, Lon: lng}
+
+	condition, args := activeBetweenCondition("", from.Unix(), to.Unix())
+	query := r.db.WithContext(ctx).Preload("MarkerType").Where(condition, args...)
+
+	var markers []*models.Marker
+	if pkg.PostGIS() {
+		err := query.Where("ST_DWithin(geog, ST_MakePoint(?, ?)::geography, ?)", lng, lat, radiusMeters).Find(&markers).Error
+		if err != nil {
+			return nil, err
+		}
+		return markers, nil
+	}
+
+	box := geo.BoundingBoxAround(center, radiusMeters)
+	query = query.Where("lat BETWEEN ? AND ?", box.MinLat, box.MaxLat)
+	if box.MinLon <= box.MaxLon {
+		query = query.Where("lng BETWEEN ? AND ?", box.MinLon, box.MaxLon)
+	} else {
+		// bounding box melintasi antimeridian
+		query = query.Where("(lng >= ? OR lng <= ?)", box.MinLon, box.MaxLon)
+	}
+	if err := query.Find(&markers).Error; err != nil {
+		return nil, err
+	}
+
+	within := make([]*models.Marker, 0, len(markers))
+	for _, marker := range markers {
+		if geo.DistanceMeters(center, geo.Point{Lat: marker.Lat, Lon: marker.Lng}) <= radiusMeters {
+			within = append(within, marker)
+		}
+	}
+	return within, nil
 }
@@ -0,0 +1,172 @@
+package markers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/recurrence"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+const (
+	defaultMarkerTimezone  = "Asia/Jakarta"
+	defaultOccurrenceLimit = 10
+	maxOccurrenceLimit     = 100
+)
+
+// NormalizeSchedule memvalidasi RRULE dan timezone marker, menulis ulang RRULE
+// dalam bentuk kanonik, dan mengisi RecurrenceEnd (nil bila seri tanpa batas).
+// Kesalahan dikembalikan sebagai error_handlers.ValidationErrors per field.
+func NormalizeSchedule(marker *models.Marker) error {
+	var errs error_handlers.ValidationErrors
+
+	if marker.Rrule != nil && strings.TrimSpace(*marker.Rrule) == "" {
+		marker.Rrule = nil
+	}
+	if marker.Timezone != nil && strings.TrimSpace(*marker.Timezone) == "" {
+		marker.Timezone = nil
+	}
+	marker.RecurrenceEnd = nil
+
+	if marker.Rrule != nil && marker.Timezone == nil {
+		timezone := defaultMarkerTimezone
+		marker.Timezone = &timezone
+	}
+	var location *time.Location
+	if marker.Timezone != nil {
+		var err error
+		if location, err = time.LoadLocation(*marker.Timezone); err != nil {
+			errs.Add("timezone", "%q tidak dikenal", *marker.Timezone)
+		}
+	}
+	if marker.Rrule == nil {
+		return errs.Err()
+	}
+
+	rule, err := recurrence.Parse(*marker.Rrule)
+	if err != nil {
+		errs.Add("rrule", "%v", err)
+	}
+	if marker.End < marker.Start {
+		errs.Add("duration", "end harus setelah start untuk marker berulang")
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	series, err := recurrence.NewSeries(rule, time.Unix(marker.Start, 0), time.Duration(marker.End-marker.Start)*time.Second, location)
+	if err != nil {
+		errs.Add("rrule", "%v", err)
+		return errs
+	}
+
+	normalized := rule.String()
+	marker.Rrule = &normalized
+	if last, ok := series.Last(); ok {
+		end := last.End.Unix()
+		marker.RecurrenceEnd = &end
+	}
+	return nil
+}
+
+// ActiveAt memeriksa apakah marker berlaku pada waktu at: start <= at <= end
+// untuk marker sekali, atau ada kejadian RRULE yang mencakup at
+func ActiveAt(marker *models.Marker, at time.Time) bool {
+	_, ok := OccurrenceAt(marker, at)
+	return ok
+}
+
+// OccurrenceAt mengembalikan kejadian marker yang mencakup waktu at
+func OccurrenceAt(marker *models.Marker, at time.Time) (*models.MarkerOccurrence, bool) {
+	series := scheduleOf(marker)
+	if series == nil {
+		unix := at.Unix()
+		if marker.Start <= unix && marker.End >= unix {
+			return &models.MarkerOccurrence{Start: marker.Start, End: marker.End}, true
+		}
+		return nil, false
+	}
+
+	occurrence, ok := series.OccurrenceAt(at)
+	if !ok {
+		return nil, false
+	}
+	return &models.MarkerOccurrence{Start: occurrence.Start.Unix(), End: occurrence.End.Unix()}, true
+}
+
+// Occurrences mengembalikan kejadian marker yang belum berakhir pada from
+func Occurrences(marker *models.Marker, from time.Time, limit int) []*models.MarkerOccurrence {
+	if limit <= 0 {
+		limit = defaultOccurrenceLimit
+	}
+	if limit > maxOccurrenceLimit {
+		limit = maxOccurrenceLimit
+	}
+
+	occurrences := []*models.MarkerOccurrence{}
+	series := scheduleOf(marker)
+	if series == nil {
+		if marker.End >= from.Unix() {
+			occurrences = append(occurrences, &models.MarkerOccurrence{Start: marker.Start, End: marker.End})
+		}
+		return occurrences
+	}
+
+	for _, occurrence := range series.Occurrences(from, limit) {
+		occurrences = append(occurrences, &models.MarkerOccurrence{Start: occurrence.Start.Unix(), End: occurrence.End.Unix()})
+	}
+	return occurrences
+}
+
+// scheduleOf mengembalikan seri RRULE marker; nil untuk marker sekali atau
+// RRULE tersimpan yang tidak valid (diperlakukan sebagai jendela start/end)
+func scheduleOf(marker *models.Marker) *recurrence.Series {
+	if marker.Rrule == nil || *marker.Rrule == "" || marker.End < marker.Start {
+		return nil
+	}
+	rule, err := recurrence.Parse(*marker.Rrule)
+	if err != nil {
+		return nil
+	}
+	timezone := defaultMarkerTimezone
+	if marker.Timezone != nil && *marker.Timezone != "" {
+		timezone = *marker.Timezone
+	}
+	location, err := time.LoadLocation(timezone)
+	if err != nil {
+		return nil
+	}
+	series, err := recurrence.NewSeries(rule, time.Unix(marker.Start, 0), time.Duration(marker.End-marker.Start)*time.Second, location)
+	if err != nil {
+		return nil
+	}
+	return series
+}
+
+// activeCondition adalah prefilter SQL untuk marker yang mungkin aktif pada
+// waktu at. Marker berulang hanya dibatasi awal seri dan recurrence_end, jadi
+// hasilnya tetap harus disaring ActiveAt.
+func activeCondition(prefix string, at int64) (string, []interface{}) {
+	return activeBetweenCondition(prefix, at, at)
+}
+
+// activeBetweenCondition adalah prefilter SQL untuk marker yang mungkin aktif
+// pada suatu waktu dalam rentang from..to
+func activeBetweenCondition(prefix string, from, to int64) (string, []interface{}) {
+	condition := fmt.Sprintf(`((%[1]srrule IS NULL AND (%[1]sstart IS NULL OR %[1]sstart <= ?) AND (%[1]s"end" IS NULL OR %[1]s"end" >= ?))
+		OR (%[1]srrule IS NOT NULL AND %[1]sstart <= ? AND (%[1]srecurrence_end IS NULL OR %[1]srecurrence_end >= ?)))`, prefix)
+	return condition, []interface{}{to, from, to, from}
+}
+
+// filterActive menyaring hasil prefilter activeCondition dengan ActiveAt
+func filterActive(markers []*models.Marker, at time.Time) []*models.Marker {
+	active := make([]*models.Marker, 0, len(markers))
+	for _, marker := range markers {
+		if ActiveAt(marker, at) {
+			active = append(active, marker)
+		}
+	}
+	return active
+}
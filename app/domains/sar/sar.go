@@ -0,0 +1,233 @@
+package sar
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/domains/incidents"
+	"github.com/khoirulhasin/untirta_api/app/domains/ships"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/ais"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+// SarVessel — satu kapal yang dapat merespons beserta jarak dan ETA ke datum
+type SarVessel struct {
+	Vessel         *ships.VesselSnapshot `json:"vessel"`
+	DistanceNm     float64               `json:"distanceNm"`
+	BearingToDatum float64               `json:"bearingToDatum"`
+	EtaMinutes     *float64              `json:"etaMinutes"`
+	DistanceRank   int                   `json:"distanceRank"`
+	EtaRank        *int                  `json:"etaRank"`
+}
+
+// SarAssist — datum, kapal terdekat dan (opsional) pola pencarian
+type SarAssist struct {
+	IncidentID *int           `json:"incidentId,omitempty"`
+	Latitude   float64        `json:"latitude"`
+	Longitude  float64        `json:"longitude"`
+	DatumAt    *int64         `json:"datumAt,omitempty"`
+	Vessels    []*SarVessel   `json:"vessels"`
+	Pattern    *SearchPattern `json:"pattern,omitempty"`
+}
+
+const (
+	defaultRadiusNm      = 25.0
+	defaultMaxAge        = 30 * time.Minute
+	defaultMinSpeedKnots = 0.5
+	defaultLimit         = 20
+	maxLimit             = 200
+)
+
+// Options adalah parameter pencarian kapal di sekitar datum
+type Options struct {
+	RadiusNm      float64
+	MaxAge        time.Duration
+	MinSpeedKnots float64
+	// jumlah kapal per peringkat; kapal yang masuk salah satu peringkat dikembalikan
+	Limit int
+}
+
+func DefaultOptions() Options {
+	return Options{
+		RadiusNm:      defaultRadiusNm,
+		MaxAge:        defaultMaxAge,
+		MinSpeedKnots: defaultMinSpeedKnots,
+		Limit:         defaultLimit,
+	}
+}
+
+// NewOptions mengisi nilai default untuk field input yang kosong
+func NewOptions(input models.SarAssistInput) Options {
+	options := DefaultOptions()
+	if input.RadiusNm != nil {
+		options.RadiusNm = *input.RadiusNm
+	}
+	if input.MaxAgeSeconds != nil {
+		options.MaxAge = time.Duration(*input.MaxAgeSeconds) * time.Second
+	}
+	if input.MinSpeedKnots != nil {
+		options.MinSpeedKnots = *input.MinSpeedKnots
+	}
+	if input.Limit != nil {
+		options.Limit = *input.Limit
+	}
+	return options
+}
+
+func (o Options) validate() error {
+	var errs error_handlers.ValidationErrors
+	if o.RadiusNm <= 0 || o.RadiusNm > 500 {
+		errs.Add("radiusNm", "radius harus lebih dari 0 dan maksimal 500 NM")
+	}
+	if o.MaxAge <= 0 {
+		errs.Add("maxAgeSeconds", "harus lebih dari 0")
+	}
+	if o.MinSpeedKnots < 0 {
+		errs.Add("minSpeedKnots", "tidak boleh negatif")
+	}
+	if o.Limit < 1 || o.Limit > maxLimit {
+		errs.Add("limit", "harus antara 1 dan %d", maxLimit)
+	}
+	return errs.Err()
+}
+
+// Assistant mencari kapal yang dapat merespons insiden MOB/SART/EPIRB dari
+// snapshot ais_dynamic terakhir dan menghitung pola pencarian di sekitar datum
+type Assistant struct {
+	incidentRepository incidents.IncidentRepository
+	shipMongodistory   ships.ShipMongodistory
+}
+
+func NewAssistant(incidentRepository incidents.IncidentRepository, shipMongodistory ships.ShipMongodistory) *Assistant {
+	return &Assistant{
+		incidentRepository: incidentRepository,
+		shipMongodistory:   shipMongodistory,
+	}
+}
+
+// Assist memakai posisi terakhir insiden sebagai datum, atau latitude/longitude
+// input bila incidentId kosong. Kesalahan input dikembalikan sebagai
+// error_handlers.ValidationErrors.
+func (a *Assistant) Assist(ctx context.Context, input models.SarAssistInput) (*SarAssist, error) {
+	options := NewOptions(input)
+	if err := options.validate(); err != nil {
+		return nil, err
+	}
+	var pattern *PatternOptions
+	if input.Pattern != nil {
+		p := NewPatternOptions(*input.Pattern)
+		if err := p.validate(); err != nil {
+			return nil, err
+		}
+		pattern = &p
+	}
+
+	assist := &SarAssist{Vessels: []*SarVessel{}}
+	var exclude int64
+	switch {
+	case input.IncidentID != nil:
+		incident, err := a.incidentRepository.GetIncidentByID(ctx, int32(*input.IncidentID))
+		if err != nil {
+			return nil, err
+		}
+		if incident.Latitude == nil || incident.Longitude == nil {
+			var errs error_handlers.ValidationErrors
+			errs.Add("incidentId", "insiden belum memiliki posisi; isi latitude/longitude datum")
+			return nil, errs
+		}
+		id := int(incident.ID)
+		lastSeenAt := incident.LastSeenAt
+		assist.IncidentID = &id
+		assist.Latitude, assist.Longitude = *incident.Latitude, *incident.Longitude
+		assist.DatumAt = &lastSeenAt
+		exclude = incident.Mmsi
+	case input.Latitude != nil && input.Longitude != nil:
+		assist.Latitude, assist.Longitude = *input.Latitude, *input.Longitude
+	default:
+		var errs error_handlers.ValidationErrors
+		errs.Add("incidentId", "isi incidentId atau latitude/longitude datum")
+		return nil, errs
+	}
+
+	datum := geo.Point{Lat: assist.Latitude, Lon: assist.Longitude}
+	if !geo.ValidPosition(datum) {
+		var errs error_handlers.ValidationErrors
+		errs.Add("latitude", "posisi datum tidak valid")
+		return nil, errs
+	}
+
+	now := time.Now()
+	snapshots, err := a.shipMongodistory.GetVesselSnapshot(ctx, geo.BoundingBoxAround(datum, options.RadiusNm*geo.MetersPerNauticalMile), now, options.MaxAge)
+	if err != nil {
+		return nil, err
+	}
+	assist.Vessels = rankVessels(datum, snapshots, exclude, options, now)
+
+	if pattern != nil {
+		assist.Pattern = NewSearchPattern(datum, *pattern)
+	}
+	return assist, nil
+}
+
+// rankVessels menghitung jarak dan ETA setiap kapal dari posisi dead-reckoning
+// pada waktu now. Kapal SART/MOB/EPIRB dan MMSI exclude dilewati.
+func rankVessels(datum geo.Point, snapshots []*ships.VesselSnapshot, exclude int64, options Options, now time.Time) []*SarVessel {
+	radiusMeters := options.RadiusNm * geo.MetersPerNauticalMile
+
+	var vessels []*SarVessel
+	for _, snapshot := range snapshots {
+		if snapshot.Position == nil || snapshot.Mmsi == exclude || ais.IsSartMmsi(snapshot.Mmsi) {
+			continue
+		}
+		motion := snapshot.Position.Motion().DeadReckon(now)
+		meters := geo.DistanceMeters(motion.Point, datum)
+		if meters > radiusMeters {
+			continue
+		}
+
+		vessel := &SarVessel{
+			Vessel:         snapshot,
+			DistanceNm:     meters / geo.MetersPerNauticalMile,
+			BearingToDatum: geo.Bearing(motion.Point, datum),
+		}
+		// ETA garis lurus dengan SOG saat ini; kapal diam/berlabuh tidak diberi ETA
+		if motion.Speed > 0 && motion.Speed >= options.MinSpeedKnots {
+			eta := vessel.DistanceNm / motion.Speed * 60
+			vessel.EtaMinutes = &eta
+		}
+		vessels = append(vessels, vessel)
+	}
+
+	sort.SliceStable(vessels, func(i, j int) bool {
+		return vessels[i].DistanceNm < vessels[j].DistanceNm
+	})
+	for i, vessel := range vessels {
+		vessel.DistanceRank = i + 1
+	}
+
+	byEta := make([]*SarVessel, 0, len(vessels))
+	for _, vessel := range vessels {
+		if vessel.EtaMinutes != nil {
+			byEta = append(byEta, vessel)
+		}
+	}
+	sort.SliceStable(byEta, func(i, j int) bool {
+		return *byEta[i].EtaMinutes < *byEta[j].EtaMinutes
+	})
+	for i, vessel := range byEta {
+		rank := i + 1
+		vessel.EtaRank = &rank
+	}
+
+	// kapal cepat yang lebih jauh tetap dikembalikan bila ETA-nya termasuk terbaik
+	results := []*SarVessel{}
+	for _, vessel := range vessels {
+		if vessel.DistanceRank <= options.Limit || (vessel.EtaRank != nil && *vessel.EtaRank <= options.Limit) {
+			results = append(results, vessel)
+		}
+	}
+	return results
+}
@@ -0,0 +1,171 @@
+package sar
+
+import (
+	"math"
+
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+// SearchPattern — lintasan pencarian dalam GeoJSON FeatureCollection berisi
+// datum (Point), track (LineString) dan area (Polygon)
+type SearchPattern struct {
+	Type            models.SearchPatternType `json:"type"`
+	LegCount        int                      `json:"legCount"`
+	LengthNm        float64                  `json:"lengthNm"`
+	DurationMinutes float64                  `json:"durationMinutes"`
+	Geojson         any                      `json:"geojson"`
+}
+
+const (
+	defaultTrackSpacingNm   = 1.0
+	defaultSquareRadiusNm   = 5.0
+	defaultSectorRadiusNm   = 2.0
+	defaultSearchSpeedKnots = 10.0
+	// batas jumlah leg expanding square agar GeoJSON tetap wajar
+	maxSquareLegs = 401
+	// jumlah titik lingkaran area sector
+	circleSegments = 72
+)
+
+// PatternOptions adalah parameter pola pencarian IAMSAR
+type PatternOptions struct {
+	Type models.SearchPatternType
+	// jarak antar lintasan expanding square (NM)
+	TrackSpacingNm float64
+	// expanding square: setengah sisi area; sector: panjang leg (NM)
+	RadiusNm      float64
+	InitialCourse float64
+	SpeedKnots    float64
+}
+
+// NewPatternOptions mengisi nilai default untuk field input yang kosong
+func NewPatternOptions(input models.SearchPatternInput) PatternOptions {
+	options := PatternOptions{
+		Type:           input.Type,
+		TrackSpacingNm: defaultTrackSpacingNm,
+		RadiusNm:       defaultSquareRadiusNm,
+		SpeedKnots:     defaultSearchSpeedKnots,
+	}
+	if input.Type == models.SearchPatternTypeSector {
+		options.RadiusNm = defaultSectorRadiusNm
+	}
+	if input.TrackSpacingNm != nil {
+		options.TrackSpacingNm = *input.TrackSpacingNm
+	}
+	if input.RadiusNm != nil {
+		options.RadiusNm = *input.RadiusNm
+	}
+	if input.InitialCourse != nil {
+		options.InitialCourse = *input.InitialCourse
+	}
+	if input.SpeedKnots != nil {
+		options.SpeedKnots = *input.SpeedKnots
+	}
+	return options
+}
+
+func (o PatternOptions) validate() error {
+	var errs error_handlers.ValidationErrors
+	if !o.Type.IsValid() {
+		errs.Add("pattern.type", "tipe pola %q tidak dikenal", o.Type)
+	}
+	if o.RadiusNm <= 0 || o.RadiusNm > 100 {
+		errs.Add("pattern.radiusNm", "radius harus lebih dari 0 dan maksimal 100 NM")
+	}
+	if o.InitialCourse < 0 || o.InitialCourse >= 360 {
+		errs.Add("pattern.initialCourse", "course harus 0 sampai kurang dari 360")
+	}
+	if o.SpeedKnots <= 0 {
+		errs.Add("pattern.speedKnots", "harus lebih dari 0")
+	}
+	if o.Type == models.SearchPatternTypeExpandingSquare {
+		if o.TrackSpacingNm <= 0 {
+			errs.Add("pattern.trackSpacingNm", "harus lebih dari 0")
+		} else if o.RadiusNm > 0 && squareLegCount(o.RadiusNm, o.TrackSpacingNm) > maxSquareLegs {
+			errs.Add("pattern.trackSpacingNm", "terlalu kecil untuk radius %.1f NM", o.RadiusNm)
+		}
+	}
+	return errs.Err()
+}
+
+// squareLegCount — setiap pasang leg memperluas area setengah track spacing ke
+// tiap sisi; satu leg terakhir menyapu sisi terluar
+func squareLegCount(radiusNm, trackSpacingNm float64) int {
+	return 2*int(math.Ceil(2*radiusNm/trackSpacingNm)) + 1
+}
+
+// NewSearchPattern menghitung pola pencarian yang dimulai dari datum
+func NewSearchPattern(datum geo.Point, options PatternOptions) *SearchPattern {
+	var courses, lengths []float64
+	var area []geo.Point
+
+	switch options.Type {
+	case models.SearchPatternTypeSector:
+		// tiga segitiga sama sisi; leg ke-3, 6 dan 9 kembali ke datum
+		for _, turn := range []float64{0, 120, 240, 240, 0, 120, 120, 240, 0} {
+			courses = append(courses, options.InitialCourse+turn)
+			lengths = append(lengths, options.RadiusNm)
+		}
+		for i := 0; i <= circleSegments; i++ {
+			area = append(area, geo.Destination(datum, float64(i)*360/circleSegments, options.RadiusNm*geo.MetersPerNauticalMile))
+		}
+	default:
+		// leg n sepanjang ceil(n/2) × S, berbelok 90° ke kanan
+		for n := 1; n <= squareLegCount(options.RadiusNm, options.TrackSpacingNm); n++ {
+			courses = append(courses, options.InitialCourse+90*float64(n-1))
+			lengths = append(lengths, math.Ceil(float64(n)/2)*options.TrackSpacingNm)
+		}
+		corner := options.RadiusNm * math.Sqrt2 * geo.MetersPerNauticalMile
+		for i := 0; i <= 4; i++ {
+			area = append(area, geo.Destination(datum, options.InitialCourse+45+90*float64(i), corner))
+		}
+	}
+
+	track := []geo.Point{datum}
+	lengthNm := 0.0
+	for i, course := range courses {
+		track = append(track, geo.Destination(track[len(track)-1], math.Mod(course, 360), lengths[i]*geo.MetersPerNauticalMile))
+		lengthNm += lengths[i]
+	}
+
+	return &SearchPattern{
+		Type:            options.Type,
+		LegCount:        len(courses),
+		LengthNm:        lengthNm,
+		DurationMinutes: lengthNm / options.SpeedKnots * 60,
+		Geojson: map[string]any{
+			"type": "FeatureCollection",
+			"features": []any{
+				feature("datum", "Point", coordinate(datum)),
+				feature("track", "LineString", coordinates(track)),
+				feature("area", "Polygon", [][][]float64{coordinates(area)}),
+			},
+		},
+	}
+}
+
+func feature(role string, geometryType string, coordinates any) map[string]any {
+	return map[string]any{
+		"type":       "Feature",
+		"properties": map[string]any{"role": role},
+		"geometry": map[string]any{
+			"type":        geometryType,
+			"coordinates": coordinates,
+		},
+	}
+}
+
+// coordinate dalam urutan GeoJSON [lon, lat], dibulatkan ke 6 desimal (~0,1 m)
+func coordinate(p geo.Point) []float64 {
+	return []float64{math.Round(p.Lon*1e6) / 1e6, math.Round(p.Lat*1e6) / 1e6}
+}
+
+func coordinates(points []geo.Point) [][]float64 {
+	result := make([][]float64, len(points))
+	for i, p := range points {
+		result[i] = coordinate(p)
+	}
+	return result
+}
@@ -2,7 +2,10 @@ package ships
 
 import (
 	"context"
+	"time"
 
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/ais"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
 	"github.com/khoirulhasin/untirta_api/app/models"
 	"go.mongodb.org/mongo-driver/bson"
 )
@@ -20,12 +23,26 @@ type ShipRepository interface {
 }
 
 type ShipMongotory interface {
-	GetShipsByImei(imei string, durationTimeInput models.DurationTimeInput) ([]bson.M, error)
-	GetShipsByDatetime(durationTimeInput models.DurationTimeInput, mmsiList []int64) ([]bson.M, error)
+	GetShipsByImei(imei string, durationTimeInput models.DurationTimeInput, trackOptions TrackOptions) ([]*Track, error)
+	GetShipsByDatetime(durationTimeInput models.DurationTimeInput, mmsiList []int64, trackOptions TrackOptions) ([]*Track, error)
+	GetMmsiByDatetime(durationTimeInput models.DurationTimeInput) ([]int64, error)
+	// GetShipsInBoundingBox mengembalikan track semua vessel, hanya titik yang berada di dalam bbox
+	GetShipsInBoundingBox(ctx context.Context, durationTimeInput models.DurationTimeInput, bbox geo.BoundingBox, trackOptions TrackOptions) ([]*Track, error)
+	GetLatestPositionByImei(ctx context.Context, imei string, since time.Time) (*VesselPosition, error)
+	GetLatestPositionByMmsi(ctx context.Context, mmsi int64) (*VesselPosition, error)
+	// GetLatestStaticByMmsi menggabungkan beberapa dokumen ais_static terakhir (nil bila tidak ada)
+	GetLatestStaticByMmsi(ctx context.Context, mmsi int64) (*VesselStatic, error)
+	// StreamTrack memanggil fn per posisi satu vessel langsung dari cursor (urut waktu naik)
+	StreamTrack(ctx context.Context, query TrackQuery, fn func(*VesselPosition) error) error
+	// StreamFixes memanggil fn per dokumen ais_dynamic satu MMSI apa adanya (urut waktu naik)
+	StreamFixes(ctx context.Context, mmsi int64, start time.Time, end time.Time, fn func(*AisFix) error) error
 	GetMobShips(durationTimeInput models.DurationTimeInput) ([]bson.M, error)
+	InsertAisDocuments(ctx context.Context, collection string, docs []*ais.Document) error
 }
 
 type ShipMongodistory interface {
 	GetAllBigShips(ctx context.Context) ([]bson.M, error)
 	GetBigShipsByDatetime(ctx context.Context, durationTimeInput models.DurationTimeInput) ([]bson.M, error)
+	GetVesselSnapshot(ctx context.Context, bbox geo.BoundingBox, at time.Time, maxAge time.Duration) ([]*VesselSnapshot, error)
+	GetVesselClusters(ctx context.Context, bbox geo.BoundingBox, zoom int, at time.Time, maxAge time.Duration) ([]*geo.Cluster, error)
 }
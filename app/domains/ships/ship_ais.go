@@ -0,0 +1,436 @@
+package ships
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/ais"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// VesselPosition — satu dokumen ais_dynamic (message type 1/2/3, 18, 19)
+type VesselPosition struct {
+	Mmsi                   int64                      `json:"mmsi"`
+	Imei                   *string                    `json:"imei,omitempty"`
+	Station                *string                    `json:"station,omitempty"`
+	MessageType            int                        `json:"messageType"`
+	Latitude               float64                    `json:"latitude"`
+	Longitude              float64                    `json:"longitude"`
+	Sog                    *float64                   `json:"sog,omitempty"`
+	Cog                    *float64                   `json:"cog,omitempty"`
+	Heading                *int                       `json:"heading,omitempty"`
+	RateOfTurn             *int                       `json:"rateOfTurn,omitempty"`
+	NavigationalStatus     *models.NavigationalStatus `json:"navigationalStatus,omitempty"`
+	NavigationalStatusCode *int                       `json:"navigationalStatusCode,omitempty"`
+	Ts                     int64                      `json:"ts"`
+	TsIso                  string                     `json:"tsIso"`
+	Raw                    any                        `json:"raw,omitempty"`
+}
+
+// VesselStatic — satu dokumen ais_static (message type 5, 19, 24)
+type VesselStatic struct {
+	Mmsi             int64                    `json:"mmsi"`
+	MessageType      int                      `json:"messageType"`
+	Imo              *int64                   `json:"imo,omitempty"`
+	CallSign         *string                  `json:"callSign,omitempty"`
+	Name             *string                  `json:"name,omitempty"`
+	ShipType         *int                     `json:"shipType,omitempty"`
+	ShipTypeName     *string                  `json:"shipTypeName,omitempty"`
+	ShipTypeCategory *models.ShipTypeCategory `json:"shipTypeCategory,omitempty"`
+	Length           *int                     `json:"length,omitempty"`
+	Beam             *int                     `json:"beam,omitempty"`
+	Draught          *float64                 `json:"draught,omitempty"`
+	Destination      *string                  `json:"destination,omitempty"`
+	Eta              *string                  `json:"eta,omitempty"`
+	Ts               int64                    `json:"ts"`
+	TsIso            string                   `json:"tsIso"`
+	Raw              any                      `json:"raw,omitempty"`
+}
+
+// MobEvent — satu dokumen ais_mob (message type 14 atau posisi dari MMSI SART/MOB/EPIRB)
+type MobEvent struct {
+	Mmsi        int64          `json:"mmsi"`
+	MessageType int            `json:"messageType"`
+	Kind        models.MobKind `json:"kind"`
+	Text        *string        `json:"text,omitempty"`
+	Latitude    *float64       `json:"latitude,omitempty"`
+	Longitude   *float64       `json:"longitude,omitempty"`
+	Active      bool           `json:"active"`
+	Ts          int64          `json:"ts"`
+	TsIso       string         `json:"tsIso"`
+	Raw         any            `json:"raw,omitempty"`
+}
+
+// VesselSnapshot — posisi terakhir satu MMSI beserta data statis terakhirnya
+type VesselSnapshot struct {
+	Mmsi     int64           `json:"mmsi"`
+	Position *VesselPosition `json:"position"`
+	Static   *VesselStatic   `json:"static,omitempty"`
+}
+
+// AisFix — posisi mentah satu dokumen ais_dynamic untuk pemeriksaan kualitas
+// data. Berbeda dengan VesselPosition, nilai "not available" (lat 91, lon 181,
+// SOG 102.3) tidak dibuang.
+type AisFix struct {
+	ID        primitive.ObjectID
+	Mmsi      int64
+	Latitude  float64
+	Longitude float64
+	Sog       *float64
+	Ts        time.Time
+}
+
+// NewAisFix membaca dokumen ais_dynamic; false bila dokumen tidak punya _id atau posisi
+func NewAisFix(doc bson.M) (*AisFix, bool) {
+	id, ok := doc["_id"].(primitive.ObjectID)
+	if !ok {
+		return nil, false
+	}
+	decoded := mapOf(doc["decoded"])
+	lat, okLat := floatOf(decoded["Latitude"])
+	lon, okLon := floatOf(decoded["Longitude"])
+	if !okLat || !okLon {
+		return nil, false
+	}
+
+	fix := &AisFix{
+		ID:        id,
+		Mmsi:      int64Of(doc["mmsi"]),
+		Latitude:  lat,
+		Longitude: lon,
+		Ts:        timeOf(doc["ts"]),
+	}
+	if sog, ok := floatOf(decoded["Sog"]); ok {
+		fix.Sog = &sog
+	}
+	return fix, true
+}
+
+func (f *AisFix) Point() geo.Point {
+	return geo.Point{Lat: f.Latitude, Lon: f.Longitude}
+}
+
+// PositionAvailable false untuk lat 91 / lon 181 ("not available"), koordinat
+// di luar rentang, dan 0,0
+func (f *AisFix) PositionAvailable() bool {
+	return geo.ValidPosition(f.Point())
+}
+
+// SogNotAvailable true bila SOG berisi 102.3 ("not available")
+func (f *AisFix) SogNotAvailable() bool {
+	return f.Sog != nil && *f.Sog >= sogNotAvailable
+}
+
+// Nilai "not available" menurut ITU-R M.1371
+const (
+	sogNotAvailable     = 102.3
+	cogNotAvailable     = 360.0
+	headingNotAvailable = 511
+	rotNotAvailable     = -128
+)
+
+// NewVesselPosition membaca dokumen ais_dynamic, baik langsung dari Mongo
+// maupun dari cache Redis (JSON). Hasil false bila dokumen tidak punya posisi.
+func NewVesselPosition(doc bson.M) (*VesselPosition, bool) {
+	decoded := mapOf(doc["decoded"])
+	lat, okLat := floatOf(decoded["Latitude"])
+	lon, okLon := floatOf(decoded["Longitude"])
+	if !okLat || !okLon {
+		return nil, false
+	}
+
+	ts := timeOf(doc["ts"])
+	p := &VesselPosition{
+		Mmsi:        int64Of(doc["mmsi"]),
+		Imei:        stringPtrOf(doc["imei"]),
+		Station:     stringPtrOf(doc["station"]),
+		MessageType: int(int64Of(doc["message_type"])),
+		Latitude:    lat,
+		Longitude:   lon,
+		Ts:          ts.UnixMilli(),
+		TsIso:       ts.UTC().Format(time.RFC3339Nano),
+		Raw:         doc,
+	}
+
+	if sog, ok := floatOf(decoded["Sog"]); ok && sog < sogNotAvailable {
+		p.Sog = &sog
+	}
+	if cog, ok := floatOf(decoded["Cog"]); ok && cog < cogNotAvailable {
+		p.Cog = &cog
+	}
+	if heading, ok := floatOf(decoded["TrueHeading"]); ok && int(heading) != headingNotAvailable {
+		h := int(heading)
+		p.Heading = &h
+	}
+	if rot, ok := floatOf(decoded["RateOfTurn"]); ok && int(rot) != rotNotAvailable {
+		r := int(rot)
+		p.RateOfTurn = &r
+	}
+	// Class B (18/19) tidak mengirim navigational status
+	if status, ok := floatOf(decoded["NavigationalStatus"]); ok {
+		code := int(status)
+		p.NavigationalStatusCode = &code
+		p.NavigationalStatus = NavigationalStatusFromCode(code)
+	}
+
+	return p, true
+}
+
+// NewVesselPositionFromDocument dipakai subscription: dokumen hasil decoder
+// dikonversi ke bentuk yang sama dengan yang dibaca dari Mongo
+func NewVesselPositionFromDocument(doc *ais.Document) (*VesselPosition, bool) {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, false
+	}
+	var m bson.M
+	if err := bson.Unmarshal(data, &m); err != nil {
+		return nil, false
+	}
+	return NewVesselPosition(m)
+}
+
+// Motion memakai COG (atau heading bila COG tidak tersedia); tanpa SOG kapal dianggap diam
+func (p *VesselPosition) Motion() geo.Motion {
+	motion := geo.Motion{
+		Point: geo.Point{Lat: p.Latitude, Lon: p.Longitude},
+		At:    time.UnixMilli(p.Ts),
+	}
+	if p.Sog != nil {
+		motion.Speed = *p.Sog
+	}
+	switch {
+	case p.Cog != nil:
+		motion.Course = *p.Cog
+	case p.Heading != nil:
+		motion.Course = float64(*p.Heading)
+	default:
+		motion.Speed = 0
+	}
+	return motion
+}
+
+// NewVesselStatic membaca dokumen ais_static
+func NewVesselStatic(doc bson.M) *VesselStatic {
+	decoded := mapOf(doc["decoded"])
+	ts := timeOf(doc["ts"])
+	s := &VesselStatic{
+		Mmsi:        int64Of(doc["mmsi"]),
+		MessageType: int(int64Of(doc["message_type"])),
+		CallSign:    stringPtrOf(decoded["CallSign"]),
+		Name:        stringPtrOf(decoded["Name"]),
+		Destination: stringPtrOf(decoded["Destination"]),
+		Ts:          ts.UnixMilli(),
+		TsIso:       ts.UTC().Format(time.RFC3339Nano),
+		Raw:         doc,
+	}
+
+	if imo := int64Of(decoded["ImoNumber"]); imo > 0 {
+		s.Imo = &imo
+	}
+	if code, ok := floatOf(decoded["Type"]); ok {
+		shipType := int(code)
+		name := ShipTypeName(shipType)
+		category := ShipTypeCategoryFromCode(shipType)
+		s.ShipType = &shipType
+		s.ShipTypeName = &name
+		s.ShipTypeCategory = &category
+	}
+	if dimension := mapOf(decoded["Dimension"]); dimension != nil {
+		length := int(int64Of(dimension["A"]) + int64Of(dimension["B"]))
+		beam := int(int64Of(dimension["C"]) + int64Of(dimension["D"]))
+		if length > 0 {
+			s.Length = &length
+		}
+		if beam > 0 {
+			s.Beam = &beam
+		}
+	}
+	if draught, ok := floatOf(decoded["MaximumStaticDraught"]); ok && draught > 0 {
+		s.Draught = &draught
+	}
+	// ETA AIS tidak punya tahun: MM-DDTHH:mm UTC
+	if eta := mapOf(decoded["Eta"]); eta != nil {
+		month, day := int64Of(eta["Month"]), int64Of(eta["Day"])
+		hour, minute := int64Of(eta["Hour"]), int64Of(eta["Minute"])
+		if month > 0 && day > 0 && hour < 24 && minute < 60 {
+			value := fmt.Sprintf("%02d-%02dT%02d:%02dZ", month, day, hour, minute)
+			s.Eta = &value
+		}
+	}
+
+	return s
+}
+
+// MergeVesselStatic menggabungkan beberapa dokumen ais_static (terbaru dulu).
+// Type 24 dikirim dalam dua part (A: nama, B: callsign/dimensi) sehingga satu
+// dokumen terakhir belum tentu lengkap.
+func MergeVesselStatic(docs []bson.M) *VesselStatic {
+	if len(docs) == 0 {
+		return nil
+	}
+	merged := NewVesselStatic(docs[0])
+	for _, doc := range docs[1:] {
+		older := NewVesselStatic(doc)
+		if merged.Imo == nil {
+			merged.Imo = older.Imo
+		}
+		if merged.CallSign == nil {
+			merged.CallSign = older.CallSign
+		}
+		if merged.Name == nil {
+			merged.Name = older.Name
+		}
+		if merged.ShipType == nil {
+			merged.ShipType, merged.ShipTypeName, merged.ShipTypeCategory = older.ShipType, older.ShipTypeName, older.ShipTypeCategory
+		}
+		if merged.Length == nil {
+			merged.Length = older.Length
+		}
+		if merged.Beam == nil {
+			merged.Beam = older.Beam
+		}
+		if merged.Draught == nil {
+			merged.Draught = older.Draught
+		}
+		if merged.Destination == nil {
+			merged.Destination = older.Destination
+		}
+		if merged.Eta == nil {
+			merged.Eta = older.Eta
+		}
+	}
+	return merged
+}
+
+// NewMobEvent membaca dokumen ais_mob
+func NewMobEvent(doc bson.M) *MobEvent {
+	decoded := mapOf(doc["decoded"])
+	ts := timeOf(doc["ts"])
+	mmsi := int64Of(doc["mmsi"])
+	e := &MobEvent{
+		Mmsi:        mmsi,
+		MessageType: int(int64Of(doc["message_type"])),
+		Kind:        mobKindOf(mmsi),
+		Text:        stringPtrOf(decoded["Text"]),
+		Ts:          ts.UnixMilli(),
+		TsIso:       ts.UTC().Format(time.RFC3339Nano),
+		Raw:         doc,
+	}
+
+	if lat, ok := floatOf(decoded["Latitude"]); ok {
+		e.Latitude = &lat
+	}
+	if lon, ok := floatOf(decoded["Longitude"]); ok {
+		e.Longitude = &lon
+	}
+
+	// Posisi SART: status 14 = aktif, 15 = test. Pesan 14: teks "TEST" berarti uji.
+	if status, ok := floatOf(decoded["NavigationalStatus"]); ok {
+		e.Active = int(status) == 14
+	} else if e.Text != nil {
+		e.Active = !strings.Contains(strings.ToUpper(*e.Text), "TEST")
+	}
+
+	return e
+}
+
+// NewMobEventFromDocument mengonversi dokumen hasil decoder ke bentuk yang
+// sama dengan yang dibaca dari ais_mob
+func NewMobEventFromDocument(doc *ais.Document) (*MobEvent, bool) {
+	data, err := bson.Marshal(doc)
+	if err != nil {
+		return nil, false
+	}
+	var m bson.M
+	if err := bson.Unmarshal(data, &m); err != nil {
+		return nil, false
+	}
+	return NewMobEvent(m), true
+}
+
+func mobKindOf(mmsi int64) models.MobKind {
+	switch mmsi / 1000000 {
+	case 970:
+		return models.MobKindAisSart
+	case 972:
+		return models.MobKindMob
+	case 974:
+		return models.MobKindEpirb
+	}
+	return models.MobKindSafetyMessage
+}
+
+// ─── helpers: bson.M dari Mongo atau map dari JSON cache ─────────
+
+func mapOf(v interface{}) map[string]interface{} {
+	switch m := v.(type) {
+	case bson.M:
+		return m
+	case map[string]interface{}:
+		return m
+	case bson.D:
+		out := make(map[string]interface{}, len(m))
+		for _, e := range m {
+			out[e.Key] = e.Value
+		}
+		return out
+	}
+	return nil
+}
+
+func floatOf(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+func int64Of(v interface{}) int64 {
+	n, _ := floatOf(v)
+	return int64(n)
+}
+
+func stringPtrOf(v interface{}) *string {
+	s, ok := v.(string)
+	if !ok {
+		return nil
+	}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+// timeOf menerima primitive.DateTime (Mongo), time.Time, atau string ISO (cache JSON / data lama)
+func timeOf(v interface{}) time.Time {
+	switch t := v.(type) {
+	case primitive.DateTime:
+		return t.Time()
+	case time.Time:
+		return t
+	case string:
+		for _, layout := range []string{time.RFC3339Nano, "2006-01-02T15:04:05.000-07:00"} {
+			if parsed, err := time.Parse(layout, t); err == nil {
+				return parsed
+			}
+		}
+	case int64:
+		return time.UnixMilli(t)
+	}
+	return time.Time{}
+}
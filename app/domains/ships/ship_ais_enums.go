@@ -0,0 +1,115 @@
+package ships
+
+import (
+	"fmt"
+
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+// NavigationalStatusFromCode — kode 0-15 ITU-R M.1371, urutannya sama dengan
+// models.AllNavigationalStatus
+func NavigationalStatusFromCode(code int) *models.NavigationalStatus {
+	if code < 0 || code >= len(models.AllNavigationalStatus) {
+		return nil
+	}
+	status := models.AllNavigationalStatus[code]
+	return &status
+}
+
+// ShipTypeCategoryFromCode mengelompokkan kode ship type AIS (0-99)
+func ShipTypeCategoryFromCode(code int) models.ShipTypeCategory {
+	switch {
+	case code <= 0 || code > 99:
+		return models.ShipTypeCategoryNotAvailable
+	case code < 20:
+		return models.ShipTypeCategoryReserved
+	case code < 30:
+		return models.ShipTypeCategoryWig
+	case code == 30:
+		return models.ShipTypeCategoryFishing
+	case code == 31 || code == 32:
+		return models.ShipTypeCategoryTowing
+	case code == 33:
+		return models.ShipTypeCategoryDredging
+	case code == 34:
+		return models.ShipTypeCategoryDiving
+	case code == 35:
+		return models.ShipTypeCategoryMilitary
+	case code == 36:
+		return models.ShipTypeCategorySailing
+	case code == 37:
+		return models.ShipTypeCategoryPleasureCraft
+	case code < 40:
+		return models.ShipTypeCategoryReserved
+	case code < 50:
+		return models.ShipTypeCategoryHighSpeedCraft
+	case code == 50:
+		return models.ShipTypeCategoryPilot
+	case code == 51:
+		return models.ShipTypeCategorySearchAndRescue
+	case code == 52:
+		return models.ShipTypeCategoryTug
+	case code == 53:
+		return models.ShipTypeCategoryPortTender
+	case code == 54:
+		return models.ShipTypeCategoryAntiPollution
+	case code == 55:
+		return models.ShipTypeCategoryLawEnforcement
+	case code == 56 || code == 57:
+		return models.ShipTypeCategoryLocal
+	case code == 58:
+		return models.ShipTypeCategoryMedical
+	case code == 59:
+		return models.ShipTypeCategoryNoncombatant
+	case code < 70:
+		return models.ShipTypeCategoryPassenger
+	case code < 80:
+		return models.ShipTypeCategoryCargo
+	case code < 90:
+		return models.ShipTypeCategoryTanker
+	}
+	return models.ShipTypeCategoryOther
+}
+
+var shipTypeCategoryNames = map[models.ShipTypeCategory]string{
+	models.ShipTypeCategoryNotAvailable:    "Not available",
+	models.ShipTypeCategoryReserved:        "Reserved",
+	models.ShipTypeCategoryWig:             "Wing in ground",
+	models.ShipTypeCategoryFishing:         "Fishing",
+	models.ShipTypeCategoryTowing:          "Towing",
+	models.ShipTypeCategoryDredging:        "Dredging or underwater ops",
+	models.ShipTypeCategoryDiving:          "Diving ops",
+	models.ShipTypeCategoryMilitary:        "Military ops",
+	models.ShipTypeCategorySailing:         "Sailing",
+	models.ShipTypeCategoryPleasureCraft:   "Pleasure craft",
+	models.ShipTypeCategoryHighSpeedCraft:  "High speed craft",
+	models.ShipTypeCategoryPilot:           "Pilot vessel",
+	models.ShipTypeCategorySearchAndRescue: "Search and rescue vessel",
+	models.ShipTypeCategoryTug:             "Tug",
+	models.ShipTypeCategoryPortTender:      "Port tender",
+	models.ShipTypeCategoryAntiPollution:   "Anti-pollution equipment",
+	models.ShipTypeCategoryLawEnforcement:  "Law enforcement",
+	models.ShipTypeCategoryLocal:           "Local vessel",
+	models.ShipTypeCategoryMedical:         "Medical transport",
+	models.ShipTypeCategoryNoncombatant:    "Noncombatant ship",
+	models.ShipTypeCategoryPassenger:       "Passenger",
+	models.ShipTypeCategoryCargo:           "Cargo",
+	models.ShipTypeCategoryTanker:          "Tanker",
+	models.ShipTypeCategoryOther:           "Other",
+}
+
+// ShipTypeName mengembalikan nama ship type, termasuk kategori muatan berbahaya (X1-X4)
+func ShipTypeName(code int) string {
+	category := ShipTypeCategoryFromCode(code)
+	name := shipTypeCategoryNames[category]
+
+	// digit kedua 1-4 = hazardous category A-D untuk WIG, HSC, passenger, cargo, tanker, other
+	switch category {
+	case models.ShipTypeCategoryWig, models.ShipTypeCategoryHighSpeedCraft, models.ShipTypeCategoryPassenger,
+		models.ShipTypeCategoryCargo, models.ShipTypeCategoryTanker, models.ShipTypeCategoryOther:
+		if hazard := code % 10; hazard >= 1 && hazard <= 4 {
+			return fmt.Sprintf("%s, hazardous category %c", name, 'A'+rune(hazard-1))
+		}
+	}
+	return name
+}
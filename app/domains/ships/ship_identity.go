@@ -0,0 +1,187 @@
+package ships
+
+import (
+	"context"
+	"errors"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/ais"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+var (
+	flagPattern     = regexp.MustCompile(`^[A-Z]{2}$`)
+	callSignPattern = regexp.MustCompile(`^[A-Z0-9]{2,7}$`)
+)
+
+// ErrNoAisStatic dikembalikan AutoFill bila MMSI belum pernah mengirim data statis
+var ErrNoAisStatic = errors.New("belum ada data ais_static untuk MMSI kapal ini")
+
+// NormalizeShip memvalidasi identitas AIS kapal (MMSI 9 digit, IMO dengan
+// check digit, callsign, kode bendera, dimensi) dan mengisi flag dari MID
+// MMSI bila kosong. Kesalahan dikembalikan sebagai error_handlers.ValidationErrors.
+func NormalizeShip(ship *models.Ship) error {
+	var errs error_handlers.ValidationErrors
+
+	ship.CallSign = normalizeCode(ship.CallSign)
+	ship.Flag = normalizeCode(ship.Flag)
+	if ship.VesselType != nil && strings.TrimSpace(*ship.VesselType) == "" {
+		ship.VesselType = nil
+	}
+
+	if ship.Mmsi != nil && (*ship.Mmsi < 100000000 || *ship.Mmsi > 999999999) {
+		errs.Add("mmsi", "MMSI harus 9 digit")
+	}
+	if ship.Imo != nil && !ValidImo(*ship.Imo) {
+		errs.Add("imo", "IMO %d tidak valid (7 digit dengan check digit)", *ship.Imo)
+	}
+	if ship.CallSign != nil && !callSignPattern.MatchString(*ship.CallSign) {
+		errs.Add("callSign", "callsign harus 2-7 huruf/angka")
+	}
+	if ship.Flag != nil && !flagPattern.MatchString(*ship.Flag) {
+		errs.Add("flag", "flag harus kode negara ISO 3166-1 alpha-2, mis. ID")
+	}
+	for _, dimension := range []struct {
+		field string
+		value *float64
+	}{{"length", ship.Length}, {"beam", ship.Beam}, {"grossTonnage", ship.GrossTonnage}} {
+		if dimension.value != nil && *dimension.value <= 0 {
+			errs.Add(dimension.field, "harus lebih dari 0")
+		}
+	}
+
+	if ship.Flag == nil && ship.Mmsi != nil {
+		if flag := ais.FlagOf(*ship.Mmsi); flag != "" {
+			ship.Flag = &flag
+		}
+	}
+	return errs.Err()
+}
+
+// ValidImo memeriksa nomor IMO 7 digit: digit terakhir = jumlah digit 1-6
+// dikali 7..2, modulo 10
+func ValidImo(imo int64) bool {
+	if imo < 1000000 || imo > 9999999 {
+		return false
+	}
+	sum := int64(0)
+	for i, n := int64(2), imo/10; i <= 7; i, n = i+1, n/10 {
+		sum += (n % 10) * i
+	}
+	return sum%10 == imo%10
+}
+
+// ApplyVesselStatic menyalin data ais_static ke kapal. Tanpa overwrite hanya
+// field yang masih kosong yang diisi; nama hanya diisi bila kapal belum bernama.
+func ApplyVesselStatic(ship *models.Ship, static *VesselStatic, overwrite bool) {
+	if static.Imo != nil && ValidImo(*static.Imo) && (ship.Imo == nil || overwrite) {
+		ship.Imo = static.Imo
+	}
+	if callSign := normalizeCode(static.CallSign); callSign != nil && (ship.CallSign == nil || overwrite) {
+		ship.CallSign = callSign
+	}
+	if static.ShipTypeName != nil && (ship.VesselType == nil || overwrite) {
+		ship.VesselType = static.ShipTypeName
+	}
+	if static.Length != nil && (ship.Length == nil || overwrite) {
+		length := float64(*static.Length)
+		ship.Length = &length
+	}
+	if static.Beam != nil && (ship.Beam == nil || overwrite) {
+		beam := float64(*static.Beam)
+		ship.Beam = &beam
+	}
+	if static.Name != nil && strings.TrimSpace(ship.Name) == "" {
+		ship.Name = strings.TrimSpace(strings.Trim(*static.Name, "@ "))
+	}
+	if ship.Flag == nil {
+		if flag := ais.FlagOf(static.Mmsi); flag != "" {
+			ship.Flag = &flag
+		}
+	}
+}
+
+// normalizeCode merapikan teks AIS (huruf besar, tanpa spasi/padding "@"); nil bila kosong
+func normalizeCode(value *string) *string {
+	if value == nil {
+		return nil
+	}
+	v := strings.ToUpper(strings.TrimSpace(strings.Trim(*value, "@ ")))
+	if v == "" {
+		return nil
+	}
+	return &v
+}
+
+// ShipDetail adalah kapal beserta posisi dan data statis AIS terakhirnya.
+// Field models.Ship di-embed sehingga bentuk JSON GetOneShip tetap sama.
+type ShipDetail struct {
+	*models.Ship
+	LatestPosition *VesselPosition `json:"latestPosition"`
+	LatestStatic   *VesselStatic   `json:"latestStatic"`
+}
+
+// AisLinker menghubungkan data kapal di Postgres dengan identitas AIS di Mongo
+type AisLinker struct {
+	shipRepository ShipRepository
+	shipMongotory  ShipMongotory
+}
+
+func NewAisLinker(shipRepository ShipRepository, shipMongotory ShipMongotory) *AisLinker {
+	return &AisLinker{
+		shipRepository: shipRepository,
+		shipMongotory:  shipMongotory,
+	}
+}
+
+// Detail melengkapi kapal dengan data AIS terakhir. Kegagalan Mongo hanya
+// dicatat agar detail kapal tetap bisa dibuka.
+func (l *AisLinker) Detail(ctx context.Context, ship *models.Ship) *ShipDetail {
+	detail := &ShipDetail{Ship: ship}
+	if ship.Mmsi == nil {
+		return detail
+	}
+
+	position, err := l.shipMongotory.GetLatestPositionByMmsi(ctx, *ship.Mmsi)
+	if err != nil {
+		log.Printf("Gagal membaca posisi AIS MMSI %d: %v", *ship.Mmsi, err)
+	}
+	static, err := l.shipMongotory.GetLatestStaticByMmsi(ctx, *ship.Mmsi)
+	if err != nil {
+		log.Printf("Gagal membaca data statis AIS MMSI %d: %v", *ship.Mmsi, err)
+	}
+	detail.LatestPosition = position
+	detail.LatestStatic = static
+	return detail
+}
+
+// AutoFill mengisi identitas kapal dari ais_static terakhir MMSI-nya
+func (l *AisLinker) AutoFill(ctx context.Context, id int32, overwrite bool) (*models.Ship, error) {
+	ship, err := l.shipRepository.GetShipByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if ship.Mmsi == nil {
+		var errs error_handlers.ValidationErrors
+		errs.Add("mmsi", "kapal belum memiliki MMSI")
+		return nil, errs
+	}
+
+	static, err := l.shipMongotory.GetLatestStaticByMmsi(ctx, *ship.Mmsi)
+	if err != nil {
+		return nil, err
+	}
+	if static == nil {
+		return nil, ErrNoAisStatic
+	}
+
+	ApplyVesselStatic(ship, static, overwrite)
+	syncedAt := time.Now().UnixMilli()
+	ship.AisSyncedAt = &syncedAt
+
+	return l.shipRepository.UpdateShip(ctx, id, ship)
+}
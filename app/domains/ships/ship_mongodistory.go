@@ -5,9 +5,11 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"sort"
 	"time"
 
 	"github.com/khoirulhasin/untirta_api/app/infrastructures/dbs/mongodis"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
 	"github.com/khoirulhasin/untirta_api/app/models"
 	"github.com/redis/go-redis/v9"
 	"go.mongodb.org/mongo-driver/bson"
@@ -178,3 +180,280 @@ func (r *shipMongodistory) GetBigShipsByDatetime(ctx context.Context, durationTi
 	}
 	return results, nil
 }
+
+const (
+	// jumlah tile maksimum per viewport; zoom grid dipilih otomatis
+	snapshotMaxTiles = 16
+	// query "sekarang" dibulatkan ke jendela ini agar cache tile bisa dipakai bersama
+	snapshotLiveBucket = 30 * time.Second
+	// cluster dengan anggota sebanyak ini atau kurang menyertakan MMSI anggotanya
+	clusterMaxMembers = 10
+)
+
+// GetVesselSnapshot mengembalikan satu posisi terakhir per MMSI di dalam bbox
+// (posisi terakhir dalam rentang (at-maxAge, at]), digabung dengan data ais_static
+// terakhir. Hasil di-cache di Redis per tile grid.
+func (r *shipMongodistory) GetVesselSnapshot(ctx context.Context, bbox geo.BoundingBox, at time.Time, maxAge time.Duration) ([]*VesselSnapshot, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	// Ambil X-Source (opsional): jika "crontab" => bypass GET cache
+	var source string
+	if v := ctx.Value("X-Source"); v != nil {
+		if s, ok := v.(string); ok {
+			source = s
+		}
+	}
+
+	at, ttl := snapshotTime(at)
+
+	tiles := geo.TilesFor(bbox, snapshotMaxTiles)
+	byTile := make(map[geo.Tile][]*VesselSnapshot, len(tiles))
+	cacheKeys := make(map[geo.Tile]string, len(tiles))
+	var missing []geo.Tile
+
+	for _, tile := range tiles {
+		cacheKey := fmt.Sprintf("ais_snapshot:v1:%s:%d:%d", tile, at.Unix(), int64(maxAge.Seconds()))
+		cacheKeys[tile] = cacheKey
+
+		if source != "crontab" {
+			if cached, err := r.db.Redis.Get(timeoutCtx, cacheKey).Result(); err == nil {
+				var snapshots []*VesselSnapshot
+				if err := json.Unmarshal([]byte(cached), &snapshots); err == nil {
+					byTile[tile] = snapshots
+					continue
+				}
+				log.Printf("redis unmarshal failed: %v", err)
+			} else if err != redis.Nil {
+				log.Printf("redis get error: %v", err)
+			}
+		}
+		missing = append(missing, tile)
+	}
+
+	if len(missing) > 0 {
+		snapshots, err := r.aggregateVesselSnapshot(timeoutCtx, missing, at, maxAge)
+		if err != nil {
+			return nil, err
+		}
+
+		z := missing[0].Z
+		for _, tile := range missing {
+			byTile[tile] = []*VesselSnapshot{}
+		}
+		for _, snapshot := range snapshots {
+			tile := geo.TileAt(geo.Point{Lat: snapshot.Position.Latitude, Lon: snapshot.Position.Longitude}, z)
+			if list, ok := byTile[tile]; ok {
+				byTile[tile] = append(list, snapshot)
+			}
+		}
+
+		// Simpan tile kosong juga (negative cache)
+		for _, tile := range missing {
+			data, err := json.Marshal(byTile[tile])
+			if err != nil {
+				log.Printf("marshal error for redis: %v", err)
+				continue
+			}
+			if err := r.db.Redis.Set(timeoutCtx, cacheKeys[tile], data, ttl).Err(); err != nil {
+				log.Printf("redis set error: %v", err)
+			}
+		}
+	}
+
+	// Tile lebih luas dari viewport: potong sesuai bbox
+	var results []*VesselSnapshot
+	for _, tile := range tiles {
+		for _, snapshot := range byTile[tile] {
+			if bbox.Contains(geo.Point{Lat: snapshot.Position.Latitude, Lon: snapshot.Position.Longitude}) {
+				results = append(results, snapshot)
+			}
+		}
+	}
+	sort.Slice(results, func(i, j int) bool {
+		return results[i].Mmsi < results[j].Mmsi
+	})
+
+	return results, nil
+}
+
+// GetVesselClusters mengelompokkan posisi terakhir per MMSI (lihat GetVesselSnapshot)
+// ke grid sesuai zoom peta. Cluster dihitung dan di-cache per tile.
+func (r *shipMongodistory) GetVesselClusters(ctx context.Context, bbox geo.BoundingBox, zoom int, at time.Time, maxAge time.Duration) ([]*geo.Cluster, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	var source string
+	if v := ctx.Value("X-Source"); v != nil {
+		if s, ok := v.(string); ok {
+			source = s
+		}
+	}
+
+	at, ttl := snapshotTime(at)
+
+	tiles := geo.TilesUpTo(bbox, snapshotMaxTiles, geo.ClusterMaxTileZoom(zoom))
+	byTile := make(map[geo.Tile][]*geo.Cluster, len(tiles))
+	cacheKeys := make(map[geo.Tile]string, len(tiles))
+	var missing []geo.Tile
+
+	for _, tile := range tiles {
+		cacheKey := fmt.Sprintf("ais_clusters:v1:%s:%d:%d:%d", tile, zoom, at.Unix(), int64(maxAge.Seconds()))
+		cacheKeys[tile] = cacheKey
+
+		if source != "crontab" {
+			if cached, err := r.db.Redis.Get(timeoutCtx, cacheKey).Result(); err == nil {
+				var clusters []*geo.Cluster
+				if err := json.Unmarshal([]byte(cached), &clusters); err == nil {
+					byTile[tile] = clusters
+					continue
+				}
+				log.Printf("redis unmarshal failed: %v", err)
+			} else if err != redis.Nil {
+				log.Printf("redis get error: %v", err)
+			}
+		}
+		missing = append(missing, tile)
+	}
+
+	if len(missing) > 0 {
+		snapshots, err := r.aggregateVesselSnapshot(timeoutCtx, missing, at, maxAge)
+		if err != nil {
+			return nil, err
+		}
+
+		z := missing[0].Z
+		points := make(map[geo.Tile][]geo.ClusterPoint, len(missing))
+		for _, tile := range missing {
+			points[tile] = nil
+		}
+		for _, snapshot := range snapshots {
+			p := geo.Point{Lat: snapshot.Position.Latitude, Lon: snapshot.Position.Longitude}
+			tile := geo.TileAt(p, z)
+			if list, ok := points[tile]; ok {
+				points[tile] = append(list, geo.ClusterPoint{ID: snapshot.Mmsi, Point: p})
+			}
+		}
+
+		for _, tile := range missing {
+			byTile[tile] = geo.GridCluster(points[tile], zoom, clusterMaxMembers)
+
+			data, err := json.Marshal(byTile[tile])
+			if err != nil {
+				log.Printf("marshal error for redis: %v", err)
+				continue
+			}
+			if err := r.db.Redis.Set(timeoutCtx, cacheKeys[tile], data, ttl).Err(); err != nil {
+				log.Printf("redis set error: %v", err)
+			}
+		}
+	}
+
+	results := []*geo.Cluster{}
+	for _, tile := range tiles {
+		for _, cluster := range byTile[tile] {
+			if bbox.Contains(geo.Point{Lat: cluster.Latitude, Lon: cluster.Longitude}) {
+				results = append(results, cluster)
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// snapshotTime membulatkan waktu query. Live view dibulatkan ke snapshotLiveBucket
+// agar semua client berbagi cache yang sama; query historis di-cache lebih lama.
+func snapshotTime(at time.Time) (time.Time, time.Duration) {
+	if time.Since(at) < snapshotLiveBucket {
+		return at.Truncate(snapshotLiveBucket), snapshotLiveBucket
+	}
+	return at.Truncate(time.Second), 10 * time.Minute
+}
+
+func (r *shipMongodistory) aggregateVesselSnapshot(ctx context.Context, tiles []geo.Tile, at time.Time, maxAge time.Duration) ([]*VesselSnapshot, error) {
+	areas := make([]bson.M, len(tiles))
+	for i, tile := range tiles {
+		box := tile.BoundingBox()
+		areas[i] = bson.M{
+			"decoded.Latitude":  bson.M{"$gte": box.MinLat, "$lte": box.MaxLat},
+			"decoded.Longitude": bson.M{"$gte": box.MinLon, "$lte": box.MaxLon},
+		}
+	}
+
+	pipeline := []bson.M{
+		// Stage 1: rentang waktu (gunakan index {ts:1})
+		{
+			"$match": bson.M{
+				"ts":                bson.M{"$gt": at.Add(-maxAge), "$lte": at},
+				"decoded.Latitude":  bson.M{"$exists": true, "$ne": nil},
+				"decoded.Longitude": bson.M{"$exists": true, "$ne": nil},
+			},
+		},
+		// Stage 2-4: posisi terakhir per MMSI
+		{"$sort": bson.D{{Key: "mmsi", Value: 1}, {Key: "ts", Value: -1}}},
+		{"$group": bson.M{"_id": "$mmsi", "doc": bson.M{"$first": "$$ROOT"}}},
+		{"$replaceRoot": bson.M{"newRoot": "$doc"}},
+		// Stage 5: hanya yang posisi terakhirnya berada di tile yang diminta
+		{"$match": bson.M{"$or": areas}},
+		// Stage 6: beberapa data statis terakhir (type 24 part A/B digabung di Go)
+		{
+			"$lookup": bson.M{
+				"from": "ais_static",
+				"let":  bson.M{"mmsi": "$mmsi"},
+				"pipeline": []bson.M{
+					{"$match": bson.M{"$expr": bson.M{"$and": []bson.M{
+						{"$eq": []interface{}{"$mmsi", "$$mmsi"}},
+						{"$lte": []interface{}{"$ts", at}},
+					}}}},
+					{"$sort": bson.M{"ts": -1}},
+					{"$limit": 5},
+				},
+				"as": "static",
+			},
+		},
+	}
+
+	opts := options.Aggregate().SetAllowDiskUse(true)
+	cur, err := r.db.Mongo.Collection("ais_dynamic").Aggregate(ctx, pipeline, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cur.Close(ctx)
+
+	var docs []bson.M
+	if err := cur.All(ctx, &docs); err != nil {
+		return nil, err
+	}
+
+	snapshots := make([]*VesselSnapshot, 0, len(docs))
+	for _, doc := range docs {
+		var statics []bson.M
+		if list, ok := doc["static"].(bson.A); ok {
+			for _, item := range list {
+				if m := mapOf(item); m != nil {
+					statics = append(statics, m)
+				}
+			}
+		}
+		delete(doc, "static")
+
+		position, ok := NewVesselPosition(doc)
+		if !ok {
+			continue
+		}
+		position.Raw = nil
+
+		static := MergeVesselStatic(statics)
+		if static != nil {
+			static.Raw = nil
+		}
+
+		snapshots = append(snapshots, &VesselSnapshot{
+			Mmsi:     position.Mmsi,
+			Position: position,
+			Static:   static,
+		})
+	}
+
+	return snapshots, nil
+}
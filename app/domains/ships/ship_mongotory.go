@@ -2,10 +2,13 @@ package ships
 
 import (
 	"context"
+	"errors"
 	"log"
 	"sort"
 	"time"
 
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/ais"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
 	"github.com/khoirulhasin/untirta_api/app/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -25,7 +28,7 @@ func NewShipMongotory(db *mongo.Database) *shipMongotory {
 
 var _ ShipMongotory = &shipMongotory{}
 
-func (r *shipMongotory) GetShipsByImei(imei string, durationTimeInput models.DurationTimeInput) ([]bson.M, error) {
+func (r *shipMongotory) GetShipsByImei(imei string, durationTimeInput models.DurationTimeInput, trackOptions TrackOptions) ([]*Track, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
 
@@ -42,38 +45,20 @@ func (r *shipMongotory) GetShipsByImei(imei string, durationTimeInput models.Dur
 	startStr := start.Format("2006-01-02T15:04:05.000+00:00")
 	endStr := end.Format("2006-01-02T15:04:05.000+00:00")
 	log.Print(startStr)
-	// Define the filter to find documents by IMEI and timestamp range
+	// Define the filter to find documents by IMEI and timestamp range.
+	// Data perangkat lama menyimpan ts sebagai string, data dari listener AIS sebagai Date.
 	filter := bson.M{
 		"imei": imei,
-		"ts": bson.M{
-			"$gte": startStr,
-			"$lte": endStr,
+		"$or": []bson.M{
+			{"ts": bson.M{"$gte": startStr, "$lte": endStr}},
+			{"ts": bson.M{"$gte": start, "$lte": end}},
 		},
 	}
 
-	// Define sort option by timestamp ascending
-	opts := options.Find().SetSort(bson.D{{"timestamp", -1}}) // 1 = ascending, -1 = descending
-
-	// Query the "records" collection with sorting
-	cursor, err := r.db.Collection("ais_dynamic").Find(ctx, filter, opts)
-	if err != nil {
-		return nil, err
-	}
-	defer cursor.Close(ctx)
-
-	var results []bson.M
-	if err := cursor.All(ctx, &results); err != nil {
-		return nil, err
-	}
-
-	if len(results) == 0 {
-		return nil, nil
-	}
-
-	return results, nil
+	return r.findTracks(ctx, filter, trackOptions)
 }
 
-func (r *shipMongotory) GetShipsByDatetime(durationTimeInput models.DurationTimeInput, mmsiList []int64) ([]bson.M, error) {
+func (r *shipMongotory) GetShipsByDatetime(durationTimeInput models.DurationTimeInput, mmsiList []int64, trackOptions TrackOptions) ([]*Track, error) {
 	// Validasi dasar
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
@@ -103,26 +88,389 @@ func (r *shipMongotory) GetShipsByDatetime(durationTimeInput models.DurationTime
 		filter["mmsi"] = bson.M{"$in": mmsiList}
 	}
 
-	// Define sort option by timestamp descending
-	opts := options.Find().SetSort(bson.D{{"ts", -1}}) // 1 = ascending, -1 = descending
+	return r.findTracks(ctx, filter, trackOptions)
+}
 
-	// Query the "ais_dynamic" collection with sorting
-	cursor, err := r.db.Collection("ais_dynamic").Find(ctx, filter, opts)
+func (r *shipMongotory) GetShipsInBoundingBox(ctx context.Context, durationTimeInput models.DurationTimeInput, bbox geo.BoundingBox, trackOptions TrackOptions) ([]*Track, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	start := time.Unix(int64(durationTimeInput.Start), 0).UTC()
+	end := time.Unix(int64(durationTimeInput.End), 0).UTC()
+
+	boxes := []geo.BoundingBox{bbox}
+	if bbox.MinLon > bbox.MaxLon {
+		// melewati antimeridian: pecah jadi dua
+		boxes = []geo.BoundingBox{
+			{MinLat: bbox.MinLat, MinLon: bbox.MinLon, MaxLat: bbox.MaxLat, MaxLon: 180},
+			{MinLat: bbox.MinLat, MinLon: -180, MaxLat: bbox.MaxLat, MaxLon: bbox.MaxLon},
+		}
+	}
+	areas := make([]bson.M, len(boxes))
+	for i, box := range boxes {
+		areas[i] = bson.M{
+			"decoded.Latitude":  bson.M{"$gte": box.MinLat, "$lte": box.MaxLat},
+			"decoded.Longitude": bson.M{"$gte": box.MinLon, "$lte": box.MaxLon},
+		}
+	}
+
+	filter := bson.M{
+		"ts": bson.M{
+			"$gte": start,
+			"$lte": end,
+		},
+		"$or": areas,
+	}
+
+	return r.findTracks(timeoutCtx, filter, trackOptions)
+}
+
+// GetLatestPositionByImei mengembalikan posisi terakhir perangkat sejak waktu since (nil bila tidak ada)
+func (r *shipMongotory) GetLatestPositionByImei(ctx context.Context, imei string, since time.Time) (*VesselPosition, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	since = since.UTC()
+	filter := bson.M{
+		"imei": imei,
+		"$or": []bson.M{
+			{"ts": bson.M{"$gte": since.Format("2006-01-02T15:04:05.000+00:00")}},
+			{"ts": bson.M{"$gte": since}},
+		},
+		"decoded.Latitude": bson.M{
+			"$exists": true,
+			"$ne":     nil,
+		},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "ts", Value: -1}})
+
+	var doc bson.M
+	err := r.db.Collection("ais_dynamic").FindOne(timeoutCtx, filter, opts).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
 	if err != nil {
 		return nil, err
 	}
-	defer cursor.Close(ctx)
 
-	var results []bson.M
-	if err := cursor.All(ctx, &results); err != nil {
+	position, ok := NewVesselPosition(doc)
+	if !ok {
+		return nil, nil
+	}
+	return position, nil
+}
+
+// GetLatestPositionByMmsi mengembalikan posisi terakhir vessel (nil bila tidak ada)
+func (r *shipMongotory) GetLatestPositionByMmsi(ctx context.Context, mmsi int64) (*VesselPosition, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	filter := bson.M{
+		"mmsi": mmsi,
+		"decoded.Latitude": bson.M{
+			"$exists": true,
+			"$ne":     nil,
+		},
+	}
+	opts := options.FindOne().SetSort(bson.D{{Key: "ts", Value: -1}})
+
+	var doc bson.M
+	err := r.db.Collection("ais_dynamic").FindOne(timeoutCtx, filter, opts).Decode(&doc)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
 		return nil, err
 	}
 
-	if len(results) == 0 {
+	position, ok := NewVesselPosition(doc)
+	if !ok {
 		return nil, nil
 	}
+	position.Raw = nil
+	return position, nil
+}
+
+// GetLatestStaticByMmsi membaca 5 dokumen ais_static terakhir agar part A/B
+// type 24 ikut tergabung
+func (r *shipMongotory) GetLatestStaticByMmsi(ctx context.Context, mmsi int64) (*VesselStatic, error) {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	opts := options.Find().SetSort(bson.D{{Key: "ts", Value: -1}}).SetLimit(5)
+	cursor, err := r.db.Collection("ais_static").Find(timeoutCtx, bson.M{"mmsi": mmsi}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(timeoutCtx)
+
+	var docs []bson.M
+	if err := cursor.All(timeoutCtx, &docs); err != nil {
+		return nil, err
+	}
+
+	static := MergeVesselStatic(docs)
+	if static != nil {
+		static.Raw = nil
+	}
+	return static, nil
+}
+
+// StreamTrack membaca ais_dynamic satu vessel per batch dari cursor sehingga
+// rentang panjang tidak pernah dimuat sekaligus ke memori. Berhenti pada error
+// pertama dari fn.
+func (r *shipMongotory) StreamTrack(ctx context.Context, query TrackQuery, fn func(*VesselPosition) error) error {
+	start := query.Start.UTC()
+	end := query.End.UTC()
+
+	filter := bson.M{
+		"decoded.Latitude": bson.M{
+			"$exists": true,
+			"$ne":     nil,
+		},
+	}
+	switch {
+	case query.Mmsi != nil:
+		filter["mmsi"] = *query.Mmsi
+		filter["ts"] = bson.M{"$gte": start, "$lte": end}
+	case query.Imei != nil:
+		// data perangkat lama menyimpan ts sebagai string, data dari listener AIS sebagai Date
+		filter["imei"] = *query.Imei
+		filter["$or"] = []bson.M{
+			{"ts": bson.M{"$gte": start.Format("2006-01-02T15:04:05.000+00:00"), "$lte": end.Format("2006-01-02T15:04:05.000+00:00")}},
+			{"ts": bson.M{"$gte": start, "$lte": end}},
+		}
+	default:
+		return errors.New("mmsi atau imei wajib diisi")
+	}
+
+	opts := options.Find().
+		SetSort(bson.D{{Key: "ts", Value: 1}}).
+		SetBatchSize(1000)
+	cursor, err := r.db.Collection("ais_dynamic").Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+		position, ok := NewVesselPosition(doc)
+		if !ok {
+			continue
+		}
+		position.Raw = nil
+		if err := fn(position); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// StreamFixes tidak menyaring titik yang sudah bertanda anomali karena dipakai
+// untuk mendeteksi anomali itu sendiri
+func (r *shipMongotory) StreamFixes(ctx context.Context, mmsi int64, start time.Time, end time.Time, fn func(*AisFix) error) error {
+	filter := bson.M{
+		"mmsi": mmsi,
+		"ts":   bson.M{"$gte": start.UTC(), "$lte": end.UTC()},
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "ts", Value: 1}}).
+		SetProjection(bson.M{"mmsi": 1, "ts": 1, "decoded.Latitude": 1, "decoded.Longitude": 1, "decoded.Sog": 1}).
+		SetBatchSize(1000)
+	cursor, err := r.db.Collection("ais_dynamic").Find(ctx, filter, opts)
+	if err != nil {
+		return err
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var doc bson.M
+		if err := cursor.Decode(&doc); err != nil {
+			return err
+		}
+		fix, ok := NewAisFix(doc)
+		if !ok {
+			continue
+		}
+		if err := fn(fix); err != nil {
+			return err
+		}
+	}
+	return cursor.Err()
+}
+
+// GetMmsiByDatetime mengembalikan MMSI yang mengirim posisi dalam rentang waktu
+func (r *shipMongotory) GetMmsiByDatetime(durationTimeInput models.DurationTimeInput) ([]int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
+	defer cancel()
+
+	start := time.Unix(int64(durationTimeInput.Start), 0).UTC()
+	end := time.Unix(int64(durationTimeInput.End), 0).UTC()
+
+	filter := bson.M{
+		"ts": bson.M{
+			"$gte": start,
+			"$lte": end,
+		},
+		"decoded.Latitude": bson.M{
+			"$exists": true,
+			"$ne":     nil,
+		},
+	}
 
-	return results, nil
+	values, err := r.db.Collection("ais_dynamic").Distinct(ctx, "mmsi", filter)
+	if err != nil {
+		return nil, err
+	}
+
+	mmsiList := make([]int64, 0, len(values))
+	for _, value := range values {
+		if mmsi := int64Of(value); mmsi > 0 {
+			mmsiList = append(mmsiList, mmsi)
+		}
+	}
+	sort.Slice(mmsiList, func(i, j int) bool { return mmsiList[i] < mmsiList[j] })
+
+	return mmsiList, nil
+}
+
+// findTracks membaca dokumen ais_dynamic per vessel (urut waktu naik).
+// BucketSeconds dikerjakan di Mongo ($group) sehingga hanya satu dokumen per
+// bucket yang dikirim; Tolerance (Douglas-Peucker) dikerjakan per track di Go
+// atas titik ringkas (_id + posisi), lalu hanya dokumen yang dipertahankan
+// yang dibaca utuh. Dokumen tanpa posisi dibuang saat Tolerance dipakai.
+func (r *shipMongotory) findTracks(ctx context.Context, filter bson.M, trackOptions TrackOptions) ([]*Track, error) {
+	if trackOptions.ExcludeAnomalies {
+		// $nin juga cocok dengan dokumen yang belum punya field anomalies
+		filter[AnomaliesField] = bson.M{"$nin": TrackAnomalyKinds}
+	}
+	simplify := trackOptions.Tolerance != nil && *trackOptions.Tolerance > 0
+
+	var tracks []*Track
+	byMmsi := make(map[int64]*Track)
+	add := func(doc bson.M, count int) {
+		mmsi := int64Of(doc["mmsi"])
+		track, ok := byMmsi[mmsi]
+		if !ok {
+			track = &Track{Mmsi: mmsi}
+			if imei, ok := doc["imei"].(string); ok {
+				track.Imei = imei
+			}
+			byMmsi[mmsi] = track
+			tracks = append(tracks, track)
+		}
+		track.OriginalCount += count
+		if !simplify {
+			track.Docs = append(track.Docs, doc)
+		} else if point, ok := newSimplifyPoint(doc); ok {
+			track.points = append(track.points, point)
+		}
+	}
+
+	if trackOptions.BucketSeconds != nil && *trackOptions.BucketSeconds > 0 {
+		bucketMs := int64(*trackOptions.BucketSeconds) * 1000
+		// ts bisa Date atau string ISO (data lama): $toDate menangani keduanya
+		tsMs := bson.M{"$toLong": bson.M{"$toDate": "$ts"}}
+
+		pipeline := []bson.M{
+			{"$match": filter},
+			{"$sort": bson.D{{Key: "ts", Value: 1}}},
+		}
+		if simplify {
+			pipeline = append(pipeline, bson.M{"$project": simplifyPointProjection})
+		}
+		pipeline = append(pipeline,
+			bson.M{
+				"$group": bson.M{
+					"_id": bson.M{
+						"mmsi":   "$mmsi",
+						"bucket": bson.M{"$subtract": []interface{}{tsMs, bson.M{"$mod": []interface{}{tsMs, bucketMs}}}},
+					},
+					"doc":   bson.M{"$first": "$$ROOT"},
+					"count": bson.M{"$sum": 1},
+				},
+			},
+			bson.M{"$sort": bson.D{{Key: "_id.mmsi", Value: 1}, {Key: "_id.bucket", Value: 1}}},
+		)
+
+		cursor, err := r.db.Collection("ais_dynamic").Aggregate(ctx, pipeline, options.Aggregate().SetAllowDiskUse(true))
+		if err != nil {
+			return nil, err
+		}
+		defer cursor.Close(ctx)
+
+		for cursor.Next(ctx) {
+			var bucket struct {
+				Doc   bson.M `bson:"doc"`
+				Count int    `bson:"count"`
+			}
+			if err := cursor.Decode(&bucket); err != nil {
+				return nil, err
+			}
+			add(bucket.Doc, bucket.Count)
+		}
+		if err := cursor.Err(); err != nil {
+			return nil, err
+		}
+	} else {
+		opts := options.Find().SetSort(bson.D{{Key: "mmsi", Value: 1}, {Key: "ts", Value: 1}})
+		if simplify {
+			opts.SetProjection(simplifyPointProjection)
+		}
+		cursor, err := r.db.Collection("ais_dynamic").Find(ctx, filter, opts)
+		if err != nil {
+			return nil, err
+		}
+		defer cursor.Close(ctx)
+
+		for cursor.Next(ctx) {
+			var doc bson.M
+			if err := cursor.Decode(&doc); err != nil {
+				return nil, err
+			}
+			add(doc, 1)
+		}
+		if err := cursor.Err(); err != nil {
+			return nil, err
+		}
+	}
+
+	if simplify {
+		for _, track := range tracks {
+			docs, err := r.findByIDs(ctx, simplifyTrack(track.points, *trackOptions.Tolerance))
+			if err != nil {
+				return nil, err
+			}
+			track.Docs, track.points = docs, nil
+		}
+	}
+
+	return tracks, nil
+}
+
+// findByIDs membaca dokumen ais_dynamic utuh dari _id, per batch, urut waktu naik
+func (r *shipMongotory) findByIDs(ctx context.Context, ids []interface{}) ([]bson.M, error) {
+	const batchSize = 1000
+
+	docs := make([]bson.M, 0, len(ids))
+	opts := options.Find().SetSort(bson.D{{Key: "ts", Value: 1}})
+	for start := 0; start < len(ids); start += batchSize {
+		end := min(start+batchSize, len(ids))
+		cursor, err := r.db.Collection("ais_dynamic").Find(ctx, bson.M{"_id": bson.M{"$in": ids[start:end]}}, opts)
+		if err != nil {
+			return nil, err
+		}
+		var batch []bson.M
+		if err := cursor.All(ctx, &batch); err != nil {
+			return nil, err
+		}
+		docs = append(docs, batch...)
+	}
+	return docs, nil
 }
 
 // func (r *shipMongotory) GetMobShips(durationTimeInput models.DurationTimeInput) ([]bson.M, error) {
@@ -315,3 +663,18 @@ func (r *shipMongotory) GetMobShips(durationTimeInput models.DurationTimeInput)
 
 	return allResults, nil
 }
+
+func (r *shipMongotory) InsertAisDocuments(ctx context.Context, collection string, docs []*ais.Document) error {
+	timeoutCtx, cancel := context.WithTimeout(ctx, 1*time.Minute)
+	defer cancel()
+
+	items := make([]interface{}, len(docs))
+	for i, doc := range docs {
+		items[i] = doc
+	}
+
+	// Unordered agar satu dokumen gagal tidak menghentikan sisa batch
+	opts := options.InsertMany().SetOrdered(false)
+	_, err := r.db.Collection(collection).InsertMany(timeoutCtx, items, opts)
+	return err
+}
@@ -0,0 +1,113 @@
+package ships
+
+import (
+	"sort"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+// TrackReplay — posisi semua vessel pada timestamp yang sama (epoch ms)
+type TrackReplay struct {
+	Timestamps []int64              `json:"timestamps"`
+	Vessels    []*TrackReplayVessel `json:"vessels"`
+}
+
+type TrackReplayVessel struct {
+	Mmsi   int64               `json:"mmsi"`
+	Frames []*TrackReplayFrame `json:"frames"`
+}
+
+// TrackReplayFrame — satu frame; posisi kosong bila status GAP atau NO_DATA
+type TrackReplayFrame struct {
+	Ts        int64                    `json:"ts"`
+	Status    models.ReplayFrameStatus `json:"status"`
+	Latitude  *float64                 `json:"latitude,omitempty"`
+	Longitude *float64                 `json:"longitude,omitempty"`
+	Sog       *float64                 `json:"sog,omitempty"`
+	Cog       *float64                 `json:"cog,omitempty"`
+	Heading   *int                     `json:"heading,omitempty"`
+}
+
+// BuildTrackReplay menginterpolasi setiap track pada timestamp start, start+step, ... end.
+// Lat/lng diinterpolasi linear; sog/cog/heading diambil dari posisi sebelumnya.
+// Dua posisi yang berjarak lebih dari maxGap tidak diinterpolasi (status GAP).
+// Urutan vessel mengikuti mmsiList; MMSI tanpa data tetap muncul dengan frame NO_DATA.
+func BuildTrackReplay(mmsiList []int64, tracks []*Track, start, end time.Time, step, maxGap time.Duration) *TrackReplay {
+	replay := &TrackReplay{Vessels: make([]*TrackReplayVessel, 0, len(mmsiList))}
+	for ts := start; !ts.After(end); ts = ts.Add(step) {
+		replay.Timestamps = append(replay.Timestamps, ts.UnixMilli())
+	}
+
+	byMmsi := make(map[int64]*Track, len(tracks))
+	for _, track := range tracks {
+		byMmsi[track.Mmsi] = track
+	}
+
+	for _, mmsi := range mmsiList {
+		track, ok := byMmsi[mmsi]
+		if !ok {
+			track = &Track{Mmsi: mmsi}
+		}
+
+		positions := make([]*VesselPosition, 0, len(track.Docs))
+		for _, doc := range track.Docs {
+			if position, ok := NewVesselPosition(doc); ok {
+				positions = append(positions, position)
+			}
+		}
+		sort.SliceStable(positions, func(i, j int) bool {
+			return positions[i].Ts < positions[j].Ts
+		})
+
+		vessel := &TrackReplayVessel{
+			Mmsi:   track.Mmsi,
+			Frames: make([]*TrackReplayFrame, len(replay.Timestamps)),
+		}
+
+		// timestamps urut naik: pointer i maju bersama frame
+		i := 0
+		maxGapMs := maxGap.Milliseconds()
+		for f, ts := range replay.Timestamps {
+			for i < len(positions) && positions[i].Ts <= ts {
+				i++
+			}
+			// positions[i-1] = terakhir <= ts, positions[i] = pertama > ts
+			frame := &TrackReplayFrame{Ts: ts, Status: models.ReplayFrameStatusNoData}
+			switch {
+			case i > 0 && positions[i-1].Ts == ts:
+				frame.Status = models.ReplayFrameStatusObserved
+				fillFrame(frame, positions[i-1], geo.Point{Lat: positions[i-1].Latitude, Lon: positions[i-1].Longitude})
+			case i > 0 && i < len(positions):
+				before, after := positions[i-1], positions[i]
+				if after.Ts-before.Ts > maxGapMs {
+					frame.Status = models.ReplayFrameStatusGap
+					break
+				}
+				fraction := float64(ts-before.Ts) / float64(after.Ts-before.Ts)
+				point := geo.Interpolate(
+					geo.Point{Lat: before.Latitude, Lon: before.Longitude},
+					geo.Point{Lat: after.Latitude, Lon: after.Longitude},
+					fraction,
+				)
+				frame.Status = models.ReplayFrameStatusInterpolated
+				fillFrame(frame, before, point)
+			}
+			vessel.Frames[f] = frame
+		}
+
+		replay.Vessels = append(replay.Vessels, vessel)
+	}
+
+	return replay
+}
+
+func fillFrame(frame *TrackReplayFrame, from *VesselPosition, point geo.Point) {
+	lat, lon := point.Lat, point.Lon
+	frame.Latitude = &lat
+	frame.Longitude = &lon
+	frame.Sog = from.Sog
+	frame.Cog = from.Cog
+	frame.Heading = from.Heading
+}
@@ -30,9 +30,20 @@ func (r *shipRepository) CreateShip(ctx context.Context, Ship *models.Ship) (*mo
 	return Ship, nil
 }
 
+// shipUpdateColumns — UpdateShipInput memuat seluruh field kapal, jadi field
+// null (mis. mmsi, imo, callSign) ikut dikosongkan; updated_by diisi callback
+// global dan ais_synced_at hanya ditulis bila diisi (AutoFill)
+func shipUpdateColumns(ship *models.Ship) []string {
+	columns := []string{"name", "number", "description", "mmsi", "imo", "call_sign", "flag", "vessel_type", "length", "beam", "gross_tonnage", "updated_by"}
+	if ship.AisSyncedAt != nil {
+		columns = append(columns, "ais_synced_at")
+	}
+	return columns
+}
+
 func (r *shipRepository) UpdateShip(ctx context.Context, id int32, Ship *models.Ship) (*models.Ship, error) {
 
-	err := r.db.WithContext(ctx).Where("id = ?", id).Model(&Ship).Updates(Ship).Error
+	err := r.db.WithContext(ctx).Where("id = ?", id).Model(&Ship).Select(shipUpdateColumns(Ship)).Updates(Ship).Error
 	if err != nil {
 		return nil, err
 	}
@@ -42,7 +53,7 @@ func (r *shipRepository) UpdateShip(ctx context.Context, id int32, Ship *models.
 
 func (r *shipRepository) UpdateShipByUUID(ctx context.Context, uuid string, Ship *models.Ship) (*models.Ship, error) {
 
-	err := r.db.WithContext(ctx).Where("uuid = ?", uuid).Model(&Ship).Updates(Ship).Error
+	err := r.db.WithContext(ctx).Where("uuid = ?", uuid).Model(&Ship).Select(shipUpdateColumns(Ship)).Updates(Ship).Error
 	if err != nil {
 		return nil, err
 	}
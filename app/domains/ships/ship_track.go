@@ -0,0 +1,135 @@
+package ships
+
+import (
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// TrackOptions mengurangi jumlah titik history. Semuanya opsional.
+type TrackOptions struct {
+	// toleransi Douglas-Peucker dalam meter
+	Tolerance *float64
+	// satu titik (yang pertama) per bucket waktu, dikerjakan di Mongo
+	BucketSeconds *int
+	// buang titik yang ditandai anomali posisi (lihat TrackAnomalyKinds)
+	ExcludeAnomalies bool
+}
+
+// AnomaliesField adalah field array di dokumen ais_dynamic berisi jenis
+// anomali yang terdeteksi pada titik tersebut
+const AnomaliesField = "anomalies"
+
+// TrackAnomalyKinds adalah anomali yang membuat titik tidak layak digambar di
+// track. INVALID_MMSI dan SOG_NOT_AVAILABLE tidak termasuk karena posisinya tetap benar.
+var TrackAnomalyKinds = []models.AisAnomalyKind{
+	models.AisAnomalyKindPositionJump,
+	models.AisAnomalyKindDuplicateMmsi,
+	models.AisAnomalyKindPositionNotAvailable,
+}
+
+// TrackQuery memilih satu vessel dari MMSI atau IMEI perangkat dalam rentang waktu
+type TrackQuery struct {
+	Mmsi  *int64
+	Imei  *string
+	Start time.Time
+	End   time.Time
+}
+
+// Track adalah dokumen ais_dynamic satu vessel (urut waktu naik)
+type Track struct {
+	Mmsi          int64
+	Imei          string
+	OriginalCount int
+	Docs          []bson.M
+
+	// titik ringkas (tanpa dokumen penuh) selama Douglas-Peucker
+	points []simplifyPoint
+}
+
+// simplifyPoint — _id dan posisi satu dokumen ais_dynamic, cukup untuk Douglas-Peucker
+type simplifyPoint struct {
+	ID    interface{}
+	Point geo.Point
+}
+
+// simplifyPointProjection membaca hanya field yang dibutuhkan simplifyPoint dan pengelompokan track
+var simplifyPointProjection = bson.M{
+	"_id":               1,
+	"mmsi":              1,
+	"imei":              1,
+	"ts":                1,
+	"decoded.Latitude":  1,
+	"decoded.Longitude": 1,
+}
+
+// newSimplifyPoint membaca simplifyPoint dari dokumen hasil simplifyPointProjection
+func newSimplifyPoint(doc bson.M) (simplifyPoint, bool) {
+	decoded := mapOf(doc["decoded"])
+	lat, okLat := floatOf(decoded["Latitude"])
+	lon, okLon := floatOf(decoded["Longitude"])
+	if !okLat || !okLon {
+		return simplifyPoint{}, false
+	}
+	return simplifyPoint{ID: doc["_id"], Point: geo.Point{Lat: lat, Lon: lon}}, true
+}
+
+// VesselTrack — hasil GraphQL satu vessel
+type VesselTrack struct {
+	Mmsi          int64             `json:"mmsi"`
+	Imei          *string           `json:"imei,omitempty"`
+	OriginalCount int               `json:"originalCount"`
+	ReturnedCount int               `json:"returnedCount"`
+	Positions     []*VesselPosition `json:"positions"`
+}
+
+type VesselTrackResult struct {
+	OriginalCount int            `json:"originalCount"`
+	ReturnedCount int            `json:"returnedCount"`
+	Tracks        []*VesselTrack `json:"tracks"`
+}
+
+// NewVesselTrackResult mengubah Track dari repository menjadi hasil bertipe
+func NewVesselTrackResult(tracks []*Track) *VesselTrackResult {
+	result := &VesselTrackResult{Tracks: make([]*VesselTrack, 0, len(tracks))}
+	for _, track := range tracks {
+		vesselTrack := &VesselTrack{
+			Mmsi:          track.Mmsi,
+			OriginalCount: track.OriginalCount,
+			Positions:     make([]*VesselPosition, 0, len(track.Docs)),
+		}
+		if track.Imei != "" {
+			imei := track.Imei
+			vesselTrack.Imei = &imei
+		}
+		for _, doc := range track.Docs {
+			if position, ok := NewVesselPosition(doc); ok {
+				vesselTrack.Positions = append(vesselTrack.Positions, position)
+			}
+		}
+		vesselTrack.ReturnedCount = len(vesselTrack.Positions)
+
+		result.OriginalCount += vesselTrack.OriginalCount
+		result.ReturnedCount += vesselTrack.ReturnedCount
+		result.Tracks = append(result.Tracks, vesselTrack)
+	}
+	return result
+}
+
+// simplifyTrack menjalankan Douglas-Peucker pada titik track dan mengembalikan
+// _id dokumen yang dipertahankan (urut waktu naik)
+func simplifyTrack(points []simplifyPoint, toleranceMeters float64) []interface{} {
+	geoPoints := make([]geo.Point, len(points))
+	for i, point := range points {
+		geoPoints[i] = point.Point
+	}
+
+	indices := geo.SimplifyIndices(geoPoints, toleranceMeters)
+	ids := make([]interface{}, len(indices))
+	for i, index := range indices {
+		ids[i] = points[index].ID
+	}
+	return ids
+}
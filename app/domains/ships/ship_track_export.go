@@ -0,0 +1,156 @@
+package ships
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+const (
+	// rentang waktu maksimal satu export
+	maxTrackExportRange = 366 * 24 * time.Hour
+	// batas hasil export lewat GraphQL (ditampung di memori); REST tidak dibatasi
+	maxTrackExportBytes = 20 << 20
+)
+
+var errTrackExportTooLarge = fmt.Errorf("hasil export melebihi %d MB, gunakan REST /api/v1/tracks/export", maxTrackExportBytes>>20)
+
+// TrackExportQuery — vessel, rentang waktu, dan format file
+type TrackExportQuery struct {
+	TrackQuery
+	Format models.TrackFileFormat
+}
+
+// Validate memeriksa vessel, rentang waktu, dan format
+func (q TrackExportQuery) Validate() error {
+	if q.Mmsi == nil && (q.Imei == nil || *q.Imei == "") {
+		return errors.New("mmsi atau imei wajib diisi")
+	}
+	if !q.End.After(q.Start) {
+		return errors.New("end harus setelah start")
+	}
+	if q.End.Sub(q.Start) > maxTrackExportRange {
+		return fmt.Errorf("rentang waktu maksimal %d hari", int(maxTrackExportRange.Hours()/24))
+	}
+	if !q.Format.IsValid() {
+		return fmt.Errorf("format %s tidak didukung", q.Format)
+	}
+	return nil
+}
+
+// Subject adalah nama vessel di file, mis. "MMSI 525012345" atau "IMEI 8612..."
+func (q TrackExportQuery) Subject() string {
+	if q.Mmsi != nil {
+		return "MMSI " + strconv.FormatInt(*q.Mmsi, 10)
+	}
+	return "IMEI " + *q.Imei
+}
+
+// Filename mis. track_525012345_20250101T000000Z_20250102T000000Z.gpx
+func (q TrackExportQuery) Filename() string {
+	id := ""
+	if q.Mmsi != nil {
+		id = strconv.FormatInt(*q.Mmsi, 10)
+	} else {
+		id = *q.Imei
+	}
+	const layout = "20060102T150405Z"
+	return fmt.Sprintf("track_%s_%s_%s.%s", id, q.Start.UTC().Format(layout), q.End.UTC().Format(layout), trackExtensions[q.Format])
+}
+
+// ContentType MIME untuk format track
+func (q TrackExportQuery) ContentType() string {
+	return trackContentTypes[q.Format]
+}
+
+var trackExtensions = map[models.TrackFileFormat]string{
+	models.TrackFileFormatGpx:     "gpx",
+	models.TrackFileFormatKml:     "kml",
+	models.TrackFileFormatGeojson: "geojson",
+	models.TrackFileFormatCSV:     "csv",
+}
+
+var trackContentTypes = map[models.TrackFileFormat]string{
+	models.TrackFileFormatGpx:     "application/gpx+xml",
+	models.TrackFileFormatKml:     "application/vnd.google-earth.kml+xml",
+	models.TrackFileFormatGeojson: "application/geo+json",
+	models.TrackFileFormatCSV:     "text/csv; charset=utf-8",
+}
+
+// TrackExporter menulis track satu vessel ke GPX, KML, GeoJSON atau CSV
+// langsung dari cursor Mongo
+type TrackExporter struct {
+	shipMongotory ShipMongotory
+}
+
+func NewTrackExporter(shipMongotory ShipMongotory) *TrackExporter {
+	return &TrackExporter{shipMongotory: shipMongotory}
+}
+
+// Write menulis file ke w dan mengembalikan jumlah titik. Tidak ada byte yang
+// ditulis sebelum titik pertama diterima, sehingga error query (mis. Mongo
+// tidak tersedia) masih bisa dikirim sebagai respons biasa oleh pemanggil.
+func (e *TrackExporter) Write(ctx context.Context, query TrackExportQuery, w io.Writer) (int, error) {
+	if err := query.Validate(); err != nil {
+		return 0, err
+	}
+
+	writer := newTrackWriter(query.Format, w)
+	count := 0
+	err := e.shipMongotory.StreamTrack(ctx, query.TrackQuery, func(position *VesselPosition) error {
+		if count == 0 {
+			if err := writer.begin(query); err != nil {
+				return err
+			}
+		}
+		count++
+		return writer.point(position)
+	})
+	if err != nil {
+		return count, err
+	}
+
+	if count == 0 {
+		if err := writer.begin(query); err != nil {
+			return 0, err
+		}
+	}
+	return count, writer.end()
+}
+
+// Export dipakai GraphQL: file ditampung di memori dengan batas maxTrackExportBytes
+func (e *TrackExporter) Export(ctx context.Context, query TrackExportQuery) (*models.TrackExport, error) {
+	buffer := &limitedBuffer{limit: maxTrackExportBytes}
+	count, err := e.Write(ctx, query, buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.TrackExport{
+		Format:      query.Format,
+		Filename:    query.Filename(),
+		ContentType: query.ContentType(),
+		Encoding:    "utf-8",
+		PointCount:  count,
+		Content:     buffer.String(),
+	}, nil
+}
+
+// limitedBuffer gagal ditulis setelah melewati limit byte
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if b.Len()+len(p) > b.limit {
+		return 0, errTrackExportTooLarge
+	}
+	return b.Buffer.Write(p)
+}
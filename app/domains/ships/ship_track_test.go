@@ -0,0 +1,35 @@
+package ships
+
+import (
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestSimplifyTrack(t *testing.T) {
+	docs := []bson.M{
+		{"_id": 1, "decoded": bson.M{"Latitude": 0.0, "Longitude": 0.0}},
+		{"_id": 2, "decoded": bson.M{"Latitude": 0.0, "Longitude": 0.01}},
+		{"_id": 3, "decoded": bson.M{"Latitude": nil}},
+		{"_id": 4, "decoded": bson.M{"Latitude": 0.001, "Longitude": 0.02}},
+		{"_id": 5, "decoded": bson.M{"Latitude": 0.0, "Longitude": 0.03}},
+		{"_id": 6, "decoded": bson.M{"Latitude": int32(0), "Longitude": 0.04}},
+	}
+
+	var points []simplifyPoint
+	for _, doc := range docs {
+		if point, ok := newSimplifyPoint(doc); ok {
+			points = append(points, point)
+		}
+	}
+	if len(points) != 5 {
+		t.Fatalf("points = %d, want 5 (document without position dropped)", len(points))
+	}
+
+	got := simplifyTrack(points, 60)
+	want := []interface{}{1, 4, 6}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("simplifyTrack = %v, want %v", got, want)
+	}
+}
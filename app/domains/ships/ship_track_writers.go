@@ -0,0 +1,322 @@
+package ships
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+// knot ke m/s untuk speed GPX
+const metersPerSecondPerKnot = geo.MetersPerNauticalMile / 3600
+
+// trackWriter menulis satu format track. begin dipanggil sekali sebelum titik
+// pertama (atau sebelum end bila track kosong).
+type trackWriter interface {
+	begin(query TrackExportQuery) error
+	point(position *VesselPosition) error
+	end() error
+}
+
+func newTrackWriter(format models.TrackFileFormat, w io.Writer) trackWriter {
+	out := bufio.NewWriterSize(w, 64<<10)
+	switch format {
+	case models.TrackFileFormatGpx:
+		return &gpxTrackWriter{out: out}
+	case models.TrackFileFormatKml:
+		return &kmlTrackWriter{out: out}
+	case models.TrackFileFormatGeojson:
+		return &geoJSONTrackWriter{out: out}
+	default:
+		return &csvTrackWriter{out: out, csv: csv.NewWriter(out)}
+	}
+}
+
+// trackPoint adalah bentuk ringkas posisi untuk format yang harus menahan
+// titik sampai akhir (KML, LineString GeoJSON); jauh lebih kecil dari dokumen Mongo
+type trackPoint struct {
+	ts       time.Time
+	lat, lon float64
+	sog, cog *float64
+}
+
+func trackPointOf(position *VesselPosition) trackPoint {
+	return trackPoint{
+		ts:  time.UnixMilli(position.Ts).UTC(),
+		lat: position.Latitude,
+		lon: position.Longitude,
+		sog: position.Sog,
+		cog: position.Cog,
+	}
+}
+
+func formatFloat(v float64, precision int) string {
+	return strconv.FormatFloat(v, 'f', precision, 64)
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// ─── GPX 1.1 ──
+
+// gpxTrackWriter menulis satu trk/trkseg; speed (m/s) dan course memakai
+// Garmin TrackPointExtension v2 karena GPX 1.1 tidak punya elemen keduanya
+type gpxTrackWriter struct {
+	out *bufio.Writer
+}
+
+func (g *gpxTrackWriter) begin(query TrackExportQuery) error {
+	name := escapeXML(query.Subject())
+	_, err := g.out.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="untirta_api" xmlns="http://www.topografix.com/GPX/1/1" xmlns:gpxtpx="http://www.garmin.com/xmlschemas/TrackPointExtension/v2" xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance" xsi:schemaLocation="http://www.topografix.com/GPX/1/1 http://www.topografix.com/GPX/1/1/gpx.xsd http://www.garmin.com/xmlschemas/TrackPointExtension/v2 http://www8.garmin.com/xmlschemas/TrackPointExtensionv2.xsd">
+  <metadata>
+    <name>` + name + `</name>
+    <time>` + time.Now().UTC().Format(time.RFC3339) + `</time>
+  </metadata>
+  <trk>
+    <name>` + name + `</name>
+    <trkseg>
+`)
+	return err
+}
+
+func (g *gpxTrackWriter) point(position *VesselPosition) error {
+	g.out.WriteString(`      <trkpt lat="` + formatFloat(position.Latitude, 6) + `" lon="` + formatFloat(position.Longitude, 6) + `">` + "\n")
+	g.out.WriteString(`        <time>` + time.UnixMilli(position.Ts).UTC().Format(time.RFC3339Nano) + `</time>` + "\n")
+	if position.Sog != nil || position.Cog != nil {
+		g.out.WriteString("        <extensions><gpxtpx:TrackPointExtension>")
+		if position.Sog != nil {
+			g.out.WriteString(`<gpxtpx:speed>` + formatFloat(*position.Sog*metersPerSecondPerKnot, 3) + `</gpxtpx:speed>`)
+		}
+		if position.Cog != nil {
+			g.out.WriteString(`<gpxtpx:course>` + formatFloat(*position.Cog, 1) + `</gpxtpx:course>`)
+		}
+		g.out.WriteString("</gpxtpx:TrackPointExtension></extensions>\n")
+	}
+	_, err := g.out.WriteString("      </trkpt>\n")
+	return err
+}
+
+func (g *gpxTrackWriter) end() error {
+	if _, err := g.out.WriteString("    </trkseg>\n  </trk>\n</gpx>\n"); err != nil {
+		return err
+	}
+	return g.out.Flush()
+}
+
+// ─── KML gx:Track ──
+
+// kmlTrackWriter menulis satu Placemark gx:Track dengan TimeSpan awal–akhir
+// titik. Skema gx:Track mengharuskan semua <when> lalu semua <gx:coord>, jadi
+// titik ditahan dalam bentuk ringkas sampai end.
+type kmlTrackWriter struct {
+	out    *bufio.Writer
+	name   string
+	points []trackPoint
+}
+
+func (k *kmlTrackWriter) begin(query TrackExportQuery) error {
+	k.name = escapeXML(query.Subject())
+	return nil
+}
+
+func (k *kmlTrackWriter) point(position *VesselPosition) error {
+	k.points = append(k.points, trackPointOf(position))
+	return nil
+}
+
+func (k *kmlTrackWriter) end() error {
+	out := k.out
+	out.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2" xmlns:gx="http://www.google.com/kml/ext/2.2">
+  <Document>
+    <name>` + k.name + `</name>
+    <Schema id="trackSchema">
+      <gx:SimpleArrayField name="speed" type="float"><displayName>SOG (knot)</displayName></gx:SimpleArrayField>
+      <gx:SimpleArrayField name="course" type="float"><displayName>COG (derajat)</displayName></gx:SimpleArrayField>
+    </Schema>
+    <Placemark>
+      <name>` + k.name + `</name>
+`)
+	if len(k.points) > 0 {
+		out.WriteString("      <TimeSpan><begin>" + k.points[0].ts.Format(time.RFC3339) + "</begin><end>" + k.points[len(k.points)-1].ts.Format(time.RFC3339) + "</end></TimeSpan>\n")
+	}
+	out.WriteString("      <gx:Track>\n        <altitudeMode>clampToGround</altitudeMode>\n")
+	for _, p := range k.points {
+		out.WriteString("        <when>" + p.ts.Format(time.RFC3339Nano) + "</when>\n")
+	}
+	for _, p := range k.points {
+		out.WriteString("        <gx:coord>" + formatFloat(p.lon, 6) + " " + formatFloat(p.lat, 6) + " 0</gx:coord>\n")
+	}
+	out.WriteString("        <ExtendedData>\n          <SchemaData schemaUrl=\"#trackSchema\">\n")
+	k.writeArray("speed", func(p trackPoint) *float64 { return p.sog })
+	k.writeArray("course", func(p trackPoint) *float64 { return p.cog })
+	out.WriteString("          </SchemaData>\n        </ExtendedData>\n      </gx:Track>\n    </Placemark>\n  </Document>\n</kml>\n")
+	return out.Flush()
+}
+
+// writeArray menulis satu gx:SimpleArrayData; nilai kosong tetap ditulis agar
+// jumlahnya sama dengan <when>
+func (k *kmlTrackWriter) writeArray(name string, value func(trackPoint) *float64) {
+	k.out.WriteString(`            <gx:SimpleArrayData name="` + name + `">` + "\n")
+	for _, p := range k.points {
+		if v := value(p); v != nil {
+			k.out.WriteString("              <gx:value>" + formatFloat(*v, 1) + "</gx:value>\n")
+		} else {
+			k.out.WriteString("              <gx:value/>\n")
+		}
+	}
+	k.out.WriteString("            </gx:SimpleArrayData>\n")
+}
+
+// ─── GeoJSON ──
+
+// geoJSONTrackWriter menulis FeatureCollection: satu Point per posisi
+// (properti time, sog, cog, heading) di-stream, lalu satu LineString track di
+// akhir dari koordinat yang ditahan
+type geoJSONTrackWriter struct {
+	out         *bufio.Writer
+	subject     string
+	coordinates [][2]float64
+	first, last time.Time
+}
+
+type geoJSONTrackProperties struct {
+	Time               string   `json:"time"`
+	Sog                *float64 `json:"sog,omitempty"`
+	Cog                *float64 `json:"cog,omitempty"`
+	Heading            *int     `json:"heading,omitempty"`
+	NavigationalStatus *string  `json:"navigationalStatus,omitempty"`
+}
+
+func (j *geoJSONTrackWriter) begin(query TrackExportQuery) error {
+	j.subject = query.Subject()
+	_, err := j.out.WriteString(`{"type":"FeatureCollection","features":[` + "\n")
+	return err
+}
+
+func (j *geoJSONTrackWriter) point(position *VesselPosition) error {
+	ts := time.UnixMilli(position.Ts).UTC()
+	if len(j.coordinates) == 0 {
+		j.first = ts
+	}
+	j.last = ts
+	j.coordinates = append(j.coordinates, [2]float64{position.Longitude, position.Latitude})
+
+	properties := geoJSONTrackProperties{
+		Time:    ts.Format(time.RFC3339Nano),
+		Sog:     position.Sog,
+		Cog:     position.Cog,
+		Heading: position.Heading,
+	}
+	if position.NavigationalStatus != nil {
+		status := position.NavigationalStatus.String()
+		properties.NavigationalStatus = &status
+	}
+	data, err := json.Marshal(properties)
+	if err != nil {
+		return err
+	}
+	j.out.WriteString(`{"type":"Feature","geometry":{"type":"Point","coordinates":[` + formatFloat(position.Longitude, 6) + `,` + formatFloat(position.Latitude, 6) + `]},"properties":`)
+	j.out.Write(data)
+	_, err = j.out.WriteString("},\n")
+	return err
+}
+
+func (j *geoJSONTrackWriter) end() error {
+	properties := map[string]interface{}{
+		"name":       j.subject,
+		"pointCount": len(j.coordinates),
+	}
+	if len(j.coordinates) > 0 {
+		properties["start"] = j.first.Format(time.RFC3339Nano)
+		properties["end"] = j.last.Format(time.RFC3339Nano)
+	}
+	data, err := json.Marshal(properties)
+	if err != nil {
+		return err
+	}
+
+	// LineString butuh minimal 2 posisi; track satu titik cukup dengan Point di atas
+	j.out.WriteString(`{"type":"Feature","geometry":`)
+	if len(j.coordinates) >= 2 {
+		j.out.WriteString(`{"type":"LineString","coordinates":[`)
+		for i, c := range j.coordinates {
+			if i > 0 {
+				j.out.WriteByte(',')
+			}
+			j.out.WriteString("[" + formatFloat(c[0], 6) + "," + formatFloat(c[1], 6) + "]")
+		}
+		j.out.WriteString("]}")
+	} else {
+		j.out.WriteString("null")
+	}
+	j.out.WriteString(`,"properties":`)
+	j.out.Write(data)
+	if _, err := j.out.WriteString("}\n]}\n"); err != nil {
+		return err
+	}
+	return j.out.Flush()
+}
+
+// ─── CSV ──
+
+var csvTrackHeader = []string{"mmsi", "imei", "time", "epoch_ms", "latitude", "longitude", "sog_knots", "cog_deg", "heading_deg", "navigational_status"}
+
+type csvTrackWriter struct {
+	out *bufio.Writer
+	csv *csv.Writer
+}
+
+func (c *csvTrackWriter) begin(query TrackExportQuery) error {
+	return c.csv.Write(csvTrackHeader)
+}
+
+func (c *csvTrackWriter) point(position *VesselPosition) error {
+	optional := func(v *float64, precision int) string {
+		if v == nil {
+			return ""
+		}
+		return formatFloat(*v, precision)
+	}
+	record := []string{
+		strconv.FormatInt(position.Mmsi, 10),
+		"",
+		time.UnixMilli(position.Ts).UTC().Format(time.RFC3339Nano),
+		strconv.FormatInt(position.Ts, 10),
+		formatFloat(position.Latitude, 6),
+		formatFloat(position.Longitude, 6),
+		optional(position.Sog, 1),
+		optional(position.Cog, 1),
+		"",
+		"",
+	}
+	if position.Imei != nil {
+		record[1] = *position.Imei
+	}
+	if position.Heading != nil {
+		record[8] = strconv.Itoa(*position.Heading)
+	}
+	if position.NavigationalStatus != nil {
+		record[9] = position.NavigationalStatus.String()
+	}
+	return c.csv.Write(record)
+}
+
+func (c *csvTrackWriter) end() error {
+	c.csv.Flush()
+	if err := c.csv.Error(); err != nil {
+		return err
+	}
+	return c.out.Flush()
+}
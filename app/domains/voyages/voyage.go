@@ -0,0 +1,18 @@
+package voyages
+
+import (
+	"context"
+
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+type VoyageRepository interface {
+	GetVoyageByID(ctx context.Context, id int32) (*models.Voyage, error)
+	// start dan end dalam epoch ms; mengembalikan voyage yang bersinggungan dengan window
+	GetVoyagesByMmsi(ctx context.Context, mmsi int64, start int64, end int64) ([]*models.Voyage, error)
+	PageVoyage(ctx context.Context, pagination models.Pagination) (models.Pagination, error)
+	// ReplaceVoyages menyimpan hasil deteksi baru untuk window. Voyage lama dengan
+	// kind dan startTime yang sama diperbarui (id dan uuid tetap); voyage lama
+	// lain yang bersinggungan dengan window dihapus permanen.
+	ReplaceVoyages(ctx context.Context, mmsi int64, start int64, end int64, voyages []*models.Voyage) error
+}
@@ -0,0 +1,319 @@
+package voyages
+
+import (
+	"context"
+	"time"
+
+	geofences "github.com/khoirulhasin/untirta_api/app/domains/geofances"
+	"github.com/khoirulhasin/untirta_api/app/domains/ships"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+const (
+	defaultMovingSpeed = 1.0 // knot
+	defaultMinStop     = 15 * time.Minute
+	defaultMaxGap      = time.Hour
+)
+
+// DetectionOptions adalah parameter segmentasi voyage
+type DetectionOptions struct {
+	// kecepatan (knot) di bawah ini dianggap diam
+	MovingSpeed float64
+	// lama diam minimal agar dianggap STOP
+	MinStop time.Duration
+	// jeda data lebih dari ini memutus segmen
+	MaxGap time.Duration
+	// geofence yang dianggap pelabuhan
+	PortGeofenceIDs []int
+}
+
+// NewDetectionOptions mengisi nilai default untuk field input yang kosong
+func NewDetectionOptions(input *models.VoyageDetectionInput) DetectionOptions {
+	opts := DetectionOptions{
+		MovingSpeed: defaultMovingSpeed,
+		MinStop:     defaultMinStop,
+		MaxGap:      defaultMaxGap,
+	}
+	if input == nil {
+		return opts
+	}
+	if input.MovingSpeed != nil {
+		opts.MovingSpeed = *input.MovingSpeed
+	}
+	if input.MinStopSeconds != nil {
+		opts.MinStop = time.Duration(*input.MinStopSeconds) * time.Second
+	}
+	if input.MaxGapSeconds != nil {
+		opts.MaxGap = time.Duration(*input.MaxGapSeconds) * time.Second
+	}
+	opts.PortGeofenceIDs = input.PortGeofenceIds
+	return opts
+}
+
+// Detector membaca history ais_dynamic, menjalankan segmentasi dan menyimpan hasilnya
+type Detector struct {
+	voyageRepository   VoyageRepository
+	shipMongotory      ships.ShipMongotory
+	geofenceRepository geofences.GeofenceRepository
+}
+
+func NewDetector(voyageRepository VoyageRepository, shipMongotory ships.ShipMongotory, geofenceRepository geofences.GeofenceRepository) *Detector {
+	return &Detector{
+		voyageRepository:   voyageRepository,
+		shipMongotory:      shipMongotory,
+		geofenceRepository: geofenceRepository,
+	}
+}
+
+// Detect menjalankan segmentasi per MMSI lalu mengganti voyage yang bersinggungan
+// dengan window. Window diperlebar ke awal voyage lama yang terpotong agar voyage
+// tersebut dihitung ulang utuh, bukan hilang sebagian.
+func (d *Detector) Detect(ctx context.Context, mmsiList []int64, start, end time.Time, opts DetectionOptions) ([]*models.Voyage, error) {
+	ports := make([]*geofences.GeofenceDB, 0, len(opts.PortGeofenceIDs))
+	for _, id := range opts.PortGeofenceIDs {
+		port, err := d.geofenceRepository.GetGeofenceByID(ctx, int32(id))
+		if err != nil {
+			return nil, err
+		}
+		ports = append(ports, port)
+	}
+
+	var result []*models.Voyage
+	for _, mmsi := range mmsiList {
+		from := start
+		existing, err := d.voyageRepository.GetVoyagesByMmsi(ctx, mmsi, start.UnixMilli(), end.UnixMilli())
+		if err != nil {
+			return nil, err
+		}
+		for _, voyage := range existing {
+			if t := time.UnixMilli(voyage.StartTime); t.Before(from) {
+				from = t
+			}
+		}
+
+		durationTimeInput := models.DurationTimeInput{Start: from.Unix(), End: end.Unix()}
+		tracks, err := d.shipMongotory.GetShipsByDatetime(durationTimeInput, []int64{mmsi}, ships.TrackOptions{})
+		if err != nil {
+			return nil, err
+		}
+
+		var positions []*ships.VesselPosition
+		for _, track := range tracks {
+			for _, doc := range track.Docs {
+				if position, ok := ships.NewVesselPosition(doc); ok {
+					positions = append(positions, position)
+				}
+			}
+		}
+
+		voyages := SegmentVoyages(mmsi, positions, ports, opts, end)
+		if err := d.voyageRepository.ReplaceVoyages(ctx, mmsi, from.UnixMilli(), end.UnixMilli(), voyages); err != nil {
+			return nil, err
+		}
+		result = append(result, voyages...)
+	}
+
+	return result, nil
+}
+
+type sample struct {
+	point   geo.Point
+	ts      time.Time
+	speed   float64 // knot
+	port    int     // 0 = di luar geofence pelabuhan
+	stopped bool
+}
+
+type stopRange struct {
+	from, to int
+}
+
+// SegmentVoyages memecah posisi satu vessel (urut waktu naik) menjadi VOYAGE dan STOP.
+//
+//   - posisi dengan kecepatan < MovingSpeed dianggap diam; di dalam geofence
+//     pelabuhan, manuver di antara dua posisi diam pada pelabuhan yang sama ikut dianggap diam
+//   - rangkaian posisi diam selama >= MinStop menjadi STOP, yang lebih singkat ikut voyage
+//   - jeda data > MaxGap menutup segmen yang sedang berjalan
+//
+// Voyage dan STOP yang bersebelahan berbagi titik batas sehingga jarak tidak terputus.
+// Segmen terakhir ditandai ongoing bila data terakhirnya masih dalam MaxGap dari windowEnd.
+func SegmentVoyages(mmsi int64, positions []*ships.VesselPosition, ports []*geofences.GeofenceDB, opts DetectionOptions, windowEnd time.Time) []*models.Voyage {
+	var voyages []*models.Voyage
+	for _, chunk := range splitOnGaps(newSamples(positions, ports), opts.MaxGap) {
+		markStopped(chunk, opts.MovingSpeed)
+
+		from := 0
+		for _, stop := range findStops(chunk, opts.MinStop) {
+			if voyage := newVoyage(mmsi, models.VoyageKindVoyage, chunk[from:stop.from+1]); voyage != nil {
+				voyages = append(voyages, voyage)
+			}
+			voyages = append(voyages, newVoyage(mmsi, models.VoyageKindStop, chunk[stop.from:stop.to+1]))
+			from = stop.to
+		}
+		if voyage := newVoyage(mmsi, models.VoyageKindVoyage, chunk[from:]); voyage != nil {
+			voyages = append(voyages, voyage)
+		}
+	}
+
+	if n := len(voyages); n > 0 && windowEnd.Sub(time.UnixMilli(voyages[n-1].EndTime)) <= opts.MaxGap {
+		voyages[n-1].Ongoing = true
+	}
+
+	return voyages
+}
+
+func newSamples(positions []*ships.VesselPosition, ports []*geofences.GeofenceDB) []*sample {
+	samples := make([]*sample, 0, len(positions))
+	for _, position := range positions {
+		s := &sample{
+			point: geo.Point{Lat: position.Latitude, Lon: position.Longitude},
+			ts:    time.UnixMilli(position.Ts),
+			speed: -1,
+		}
+		if position.Sog != nil {
+			s.speed = *position.Sog
+		}
+		for _, port := range ports {
+			if port.Contains(s.point) {
+				s.port = int(port.ID)
+				break
+			}
+		}
+		samples = append(samples, s)
+	}
+
+	// SOG tidak tersedia: pakai kecepatan rata-rata leg sebelumnya (atau leg berikutnya untuk titik pertama)
+	for i, s := range samples {
+		if s.speed >= 0 {
+			continue
+		}
+		switch {
+		case i > 0:
+			s.speed = legSpeed(samples[i-1], s)
+		case len(samples) > 1:
+			s.speed = legSpeed(s, samples[1])
+		default:
+			s.speed = 0
+		}
+	}
+
+	return samples
+}
+
+// legSpeed mengembalikan kecepatan rata-rata (knot) di antara dua titik
+func legSpeed(a, b *sample) float64 {
+	hours := b.ts.Sub(a.ts).Hours()
+	if hours <= 0 {
+		return 0
+	}
+	return geo.DistanceMeters(a.point, b.point) / geo.MetersPerNauticalMile / hours
+}
+
+func splitOnGaps(samples []*sample, maxGap time.Duration) [][]*sample {
+	var chunks [][]*sample
+	from := 0
+	for i := 1; i <= len(samples); i++ {
+		if i == len(samples) || samples[i].ts.Sub(samples[i-1].ts) > maxGap {
+			chunks = append(chunks, samples[from:i])
+			from = i
+		}
+	}
+	return chunks
+}
+
+func markStopped(chunk []*sample, movingSpeed float64) {
+	lastStopped := -1
+	portRunStart := 0
+	for i, s := range chunk {
+		if i > 0 && s.port != chunk[i-1].port {
+			portRunStart = i
+		}
+		if s.speed >= movingSpeed {
+			continue
+		}
+		s.stopped = true
+		// masih di pelabuhan yang sama sejak posisi diam sebelumnya: manuver di antaranya ikut diam
+		if s.port != 0 && lastStopped >= portRunStart {
+			for j := lastStopped + 1; j < i; j++ {
+				chunk[j].stopped = true
+			}
+		}
+		lastStopped = i
+	}
+}
+
+func findStops(chunk []*sample, minStop time.Duration) []stopRange {
+	var stops []stopRange
+	for i := 0; i < len(chunk); {
+		if !chunk[i].stopped {
+			i++
+			continue
+		}
+		j := i
+		for j+1 < len(chunk) && chunk[j+1].stopped {
+			j++
+		}
+		if chunk[j].ts.Sub(chunk[i].ts) >= minStop {
+			stops = append(stops, stopRange{from: i, to: j})
+		}
+		i = j + 1
+	}
+	return stops
+}
+
+// newVoyage menghitung statistik satu segmen; segmen VOYAGE dengan kurang dari dua titik dibuang
+func newVoyage(mmsi int64, kind models.VoyageKind, segment []*sample) *models.Voyage {
+	if len(segment) == 0 || (kind == models.VoyageKindVoyage && len(segment) < 2) {
+		return nil
+	}
+
+	first, last := segment[0], segment[len(segment)-1]
+	voyage := &models.Voyage{
+		Mmsi:           mmsi,
+		Kind:           kind,
+		StartTime:      first.ts.UnixMilli(),
+		EndTime:        last.ts.UnixMilli(),
+		StartLatitude:  first.point.Lat,
+		StartLongitude: first.point.Lon,
+		EndLatitude:    last.point.Lat,
+		EndLongitude:   last.point.Lon,
+		PositionCount:  len(segment),
+	}
+
+	meters := 0.0
+	for i, s := range segment {
+		if i > 0 {
+			meters += geo.DistanceMeters(segment[i-1].point, s.point)
+		}
+		if s.speed > voyage.MaxSpeed {
+			voyage.MaxSpeed = s.speed
+		}
+	}
+	voyage.DistanceNm = meters / geo.MetersPerNauticalMile
+	if hours := last.ts.Sub(first.ts).Hours(); hours > 0 {
+		voyage.AverageSpeed = voyage.DistanceNm / hours
+	}
+
+	if kind == models.VoyageKindStop {
+		for _, s := range segment {
+			if s.port != 0 {
+				port := s.port
+				voyage.StartGeofenceID = &port
+				voyage.EndGeofenceID = &port
+				break
+			}
+		}
+		return voyage
+	}
+
+	if first.port != 0 {
+		port := first.port
+		voyage.StartGeofenceID = &port
+	}
+	if last.port != 0 {
+		port := last.port
+		voyage.EndGeofenceID = &port
+	}
+	return voyage
+}
@@ -0,0 +1,128 @@
+package voyages
+
+import (
+	"context"
+
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/pkg"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"gorm.io/gorm"
+)
+
+type voyageRepository struct {
+	db *gorm.DB
+}
+
+func NewVoyageRepository(db *gorm.DB) *voyageRepository {
+	return &voyageRepository{
+		db,
+	}
+}
+
+var _ VoyageRepository = &voyageRepository{}
+
+func (r *voyageRepository) GetVoyageByID(ctx context.Context, id int32) (*models.Voyage, error) {
+
+	var voyage = &models.Voyage{}
+
+	err := r.db.WithContext(ctx).Where("id = ?", id).Take(&voyage).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return voyage, nil
+}
+
+func (r *voyageRepository) GetVoyagesByMmsi(ctx context.Context, mmsi int64, start int64, end int64) ([]*models.Voyage, error) {
+
+	var voyages []*models.Voyage
+
+	err := r.db.WithContext(ctx).
+		Where("mmsi = ? AND start_time <= ? AND end_time >= ?", mmsi, end, start).
+		Order("start_time ASC").
+		Find(&voyages).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return voyages, nil
+}
+
+func (r *voyageRepository) PageVoyage(ctx context.Context, pagination models.Pagination) (models.Pagination, error) {
+	var voyages []models.Voyage
+
+	var err = r.db.WithContext(ctx).
+		Scopes(pkg.Paginate(&models.Voyage{}, &pagination, r.db)).
+		Find(&voyages).Error
+	pagination.Rows = make([]any, len(voyages))
+	for i, voyage := range voyages {
+		pagination.Rows[i] = voyage
+	}
+
+	if err != nil {
+		return pagination, err
+	}
+
+	return pagination, nil
+
+}
+
+func (r *voyageRepository) ReplaceVoyages(ctx context.Context, mmsi int64, start int64, end int64, voyages []*models.Voyage) error {
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var existing []*models.Voyage
+		err := tx.Where("mmsi = ? AND start_time <= ? AND end_time >= ?", mmsi, end, start).
+			Find(&existing).Error
+		if err != nil {
+			return err
+		}
+
+		byKey := make(map[voyageKey]*models.Voyage, len(existing))
+		for _, voyage := range existing {
+			byKey[voyageKey{voyage.Kind, voyage.StartTime}] = voyage
+		}
+
+		for _, voyage := range voyages {
+			key := voyageKey{voyage.Kind, voyage.StartTime}
+			old, ok := byKey[key]
+			if !ok {
+				if err := tx.Create(&voyage).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			delete(byKey, key)
+
+			voyage.ID, voyage.UUID, voyage.CreatedAt, voyage.CreatedBy = old.ID, old.UUID, old.CreatedAt, old.CreatedBy
+			err := tx.Model(&old).
+				Select("*").
+				Omit("id", "uuid", "created_at", "created_by", "deleted_at").
+				Updates(voyage).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		// voyage lama yang tidak terdeteksi lagi; dibuat ulang oleh detektor bila
+		// muncul kembali sehingga tidak perlu disimpan sebagai soft delete
+		ids := make([]int, 0, len(byKey))
+		for _, voyage := range byKey {
+			ids = append(ids, voyage.ID)
+		}
+		if len(ids) > 0 {
+			if err := tx.Unscoped().Where("id IN ?", ids).Delete(&models.Voyage{}).Error; err != nil {
+				return err
+			}
+		}
+
+		// sisa soft delete dari versi sebelumnya yang mengganti semua voyage tiap deteksi
+		return tx.Unscoped().
+			Where("mmsi = ? AND start_time <= ? AND end_time >= ? AND deleted_at <> 0", mmsi, end, start).
+			Delete(&models.Voyage{}).Error
+	})
+}
+
+// voyageKey mengenali voyage yang sama antar deteksi
+type voyageKey struct {
+	kind      models.VoyageKind
+	startTime int64
+}
@@ -0,0 +1,119 @@
+package voyages
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/domains/ships"
+	"github.com/khoirulhasin/untirta_api/app/models"
+)
+
+const defaultWorkerLookback = 24 * time.Hour
+
+// WorkerConfig dibaca dari environment:
+//
+//	VOYAGE_DETECT_INTERVAL    interval deteksi, mis. "1h" (kosong = nonaktif)
+//	VOYAGE_DETECT_LOOKBACK    panjang window per putaran, default "24h"
+//	VOYAGE_PORT_GEOFENCE_IDS  id geofence pelabuhan, dipisah koma
+type WorkerConfig struct {
+	Interval        time.Duration
+	Lookback        time.Duration
+	PortGeofenceIDs []int
+}
+
+// LoadWorkerConfig membaca konfigurasi worker voyage dari environment
+func LoadWorkerConfig() (WorkerConfig, error) {
+	config := WorkerConfig{Lookback: defaultWorkerLookback}
+
+	if raw := strings.TrimSpace(os.Getenv("VOYAGE_DETECT_INTERVAL")); raw != "" {
+		interval, err := time.ParseDuration(raw)
+		if err != nil {
+			return config, fmt.Errorf("invalid VOYAGE_DETECT_INTERVAL: %w", err)
+		}
+		config.Interval = interval
+	}
+
+	if raw := strings.TrimSpace(os.Getenv("VOYAGE_DETECT_LOOKBACK")); raw != "" {
+		lookback, err := time.ParseDuration(raw)
+		if err != nil || lookback <= 0 {
+			return config, fmt.Errorf("invalid VOYAGE_DETECT_LOOKBACK: %q", raw)
+		}
+		config.Lookback = lookback
+	}
+
+	for _, raw := range strings.Split(os.Getenv("VOYAGE_PORT_GEOFENCE_IDS"), ",") {
+		raw = strings.TrimSpace(raw)
+		if raw == "" {
+			continue
+		}
+		id, err := strconv.Atoi(raw)
+		if err != nil {
+			return config, fmt.Errorf("invalid VOYAGE_PORT_GEOFENCE_IDS: %w", err)
+		}
+		config.PortGeofenceIDs = append(config.PortGeofenceIDs, id)
+	}
+
+	return config, nil
+}
+
+// Worker menjalankan deteksi voyage secara berkala untuk semua MMSI yang
+// mengirim posisi dalam window lookback
+type Worker struct {
+	detector      *Detector
+	shipMongotory ships.ShipMongotory
+	config        WorkerConfig
+}
+
+func NewWorker(detector *Detector, shipMongotory ships.ShipMongotory, config WorkerConfig) *Worker {
+	return &Worker{
+		detector:      detector,
+		shipMongotory: shipMongotory,
+		config:        config,
+	}
+}
+
+func (w *Worker) Start(ctx context.Context) {
+	if w.config.Interval <= 0 {
+		log.Printf("voyage detection worker disabled (VOYAGE_DETECT_INTERVAL not set)")
+		return
+	}
+
+	log.Printf("voyage detection worker started: interval %s, lookback %s", w.config.Interval, w.config.Lookback)
+	ticker := time.NewTicker(w.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		w.run(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *Worker) run(ctx context.Context) {
+	end := time.Now()
+	start := end.Add(-w.config.Lookback)
+
+	mmsiList, err := w.shipMongotory.GetMmsiByDatetime(models.DurationTimeInput{Start: start.Unix(), End: end.Unix()})
+	if err != nil {
+		log.Printf("voyage detection: list mmsi failed: %v", err)
+		return
+	}
+
+	opts := NewDetectionOptions(nil)
+	opts.PortGeofenceIDs = w.config.PortGeofenceIDs
+
+	voyages, err := w.detector.Detect(ctx, mmsiList, start, end, opts)
+	if err != nil {
+		log.Printf("voyage detection failed: %v", err)
+		return
+	}
+	log.Printf("voyage detection: %d segment(s) from %d vessel(s)", len(voyages), len(mmsiList))
+}
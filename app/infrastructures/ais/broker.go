@@ -0,0 +1,53 @@
+package ais
+
+import (
+	"sync"
+)
+
+// Broker menyebarkan dokumen yang baru tersimpan ke subscriber (GraphQL
+// subscription, evaluator alert, ...). Subscriber yang lambat tidak menahan
+// ingest: dokumen untuknya dibuang bila buffer penuh.
+type Broker struct {
+	mu     sync.RWMutex
+	nextID int
+	subs   map[int]chan *Document
+}
+
+func NewBroker() *Broker {
+	return &Broker{subs: make(map[int]chan *Document)}
+}
+
+// Subscribe mendaftarkan subscriber baru. Fungsi kedua wajib dipanggil untuk berhenti.
+func (b *Broker) Subscribe(buffer int) (<-chan *Document, func()) {
+	ch := make(chan *Document, buffer)
+
+	b.mu.Lock()
+	id := b.nextID
+	b.nextID++
+	b.subs[id] = ch
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, id)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+func (b *Broker) Publish(docs []*Document) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for _, ch := range b.subs {
+		for _, doc := range docs {
+			select {
+			case ch <- doc:
+			default:
+			}
+		}
+	}
+}
@@ -0,0 +1,183 @@
+package ais
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	CollectionDynamic = "ais_dynamic"
+	CollectionStatic  = "ais_static"
+	CollectionMob     = "ais_mob"
+
+	// fragment yang tidak lengkap dibuang setelah durasi ini
+	fragmentTTL = 30 * time.Second
+)
+
+// Document adalah bentuk dokumen yang disimpan ke koleksi ais_* dan dibaca
+// oleh ShipMongotory / ShipMongodistory
+type Document struct {
+	MMSI        int64     `json:"mmsi" bson:"mmsi"`
+	MessageType int       `json:"message_type" bson:"message_type"`
+	Decoded     any       `json:"decoded" bson:"decoded"`
+	TS          time.Time `json:"ts" bson:"ts"`
+	Channel     string    `json:"channel,omitempty" bson:"channel,omitempty"`
+	Talker      string    `json:"talker,omitempty" bson:"talker,omitempty"`
+	Station     string    `json:"station,omitempty" bson:"station,omitempty"`
+	Imei        string    `json:"imei,omitempty" bson:"imei,omitempty"`
+	Raw         []string  `json:"raw" bson:"raw"`
+}
+
+// Collections menentukan koleksi tujuan sesuai message type dan jenis MMSI
+func (d *Document) Collections() []string {
+	switch d.MessageType {
+	case 14:
+		return []string{CollectionMob}
+	case 1, 2, 3, 18:
+		if IsSartMmsi(d.MMSI) {
+			return []string{CollectionMob}
+		}
+		return []string{CollectionDynamic}
+	case 19:
+		return []string{CollectionDynamic, CollectionStatic}
+	case 5, 24:
+		return []string{CollectionStatic}
+	}
+	return nil
+}
+
+// Position mengembalikan lat/lon dari pesan posisi (1/2/3, 18, 19)
+func (d *Document) Position() (lat, lon float64, ok bool) {
+	switch m := d.Decoded.(type) {
+	case *PositionReport:
+		return m.Latitude, m.Longitude, true
+	case *StandardClassBPositionReport:
+		return m.Latitude, m.Longitude, true
+	case *ExtendedClassBPositionReport:
+		return m.Latitude, m.Longitude, true
+	}
+	return 0, 0, false
+}
+
+// Sog mengembalikan speed over ground (knot) dari pesan posisi; 102.3 = tidak tersedia
+func (d *Document) Sog() (sog float64, ok bool) {
+	switch m := d.Decoded.(type) {
+	case *PositionReport:
+		sog = m.Sog
+	case *StandardClassBPositionReport:
+		sog = m.Sog
+	case *ExtendedClassBPositionReport:
+		sog = m.Sog
+	default:
+		return 0, false
+	}
+	return sog, sog < 102.3
+}
+
+// IsSartMmsi — 970xxxxxx (AIS-SART), 972xxxxxx (MOB), 974xxxxxx (EPIRB-AIS)
+func IsSartMmsi(mmsi int64) bool {
+	prefix := mmsi / 1000000
+	return prefix == 970 || prefix == 972 || prefix == 974
+}
+
+type fragmentGroup struct {
+	parts    []*Sentence
+	received int
+	firstAt  time.Time
+}
+
+// Decoder menyusun ulang pesan multi-part lalu men-decode payload-nya.
+// Satu Decoder dipakai per sumber data (file upload, stasiun receiver).
+type Decoder struct {
+	mu     sync.Mutex
+	groups map[string]*fragmentGroup
+	now    func() time.Time
+}
+
+func NewDecoder() *Decoder {
+	return &Decoder{
+		groups: make(map[string]*fragmentGroup),
+		now:    time.Now,
+	}
+}
+
+// Feed memproses satu baris. Hasilnya nil tanpa error bila baris masih
+// menunggu fragment berikutnya.
+func (d *Decoder) Feed(line string) (*Document, error) {
+	s, err := ParseSentence(line)
+	if err != nil {
+		return nil, err
+	}
+
+	now := d.now().UTC()
+	parts := []*Sentence{s}
+
+	if s.Total > 1 {
+		parts = d.assemble(s, now)
+		if parts == nil {
+			return nil, nil
+		}
+	}
+
+	var armored strings.Builder
+	raw := make([]string, len(parts))
+	for i, part := range parts {
+		armored.WriteString(part.Payload)
+		raw[i] = part.Raw
+	}
+
+	p, err := newPayload(armored.String(), parts[len(parts)-1].FillBits)
+	if err != nil {
+		return nil, err
+	}
+
+	msgType, mmsi, decoded, err := decodeMessage(p)
+	if err != nil {
+		return nil, fmt.Errorf("type %d: %w", msgType, err)
+	}
+
+	ts := now
+	if parts[0].Timestamp != nil {
+		ts = *parts[0].Timestamp
+	}
+
+	return &Document{
+		MMSI:        mmsi,
+		MessageType: msgType,
+		Decoded:     decoded,
+		TS:          ts,
+		Channel:     s.Channel,
+		Talker:      s.Talker,
+		Raw:         raw,
+	}, nil
+}
+
+func (d *Decoder) assemble(s *Sentence, now time.Time) []*Sentence {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for key, g := range d.groups {
+		if now.Sub(g.firstAt) > fragmentTTL {
+			delete(d.groups, key)
+		}
+	}
+
+	key := fmt.Sprintf("%s|%s|%s|%d", s.Talker, s.SequenceID, s.Channel, s.Total)
+	g, ok := d.groups[key]
+	if !ok || s.Fragment == 1 {
+		g = &fragmentGroup{parts: make([]*Sentence, s.Total), firstAt: now}
+		d.groups[key] = g
+	}
+	if g.parts[s.Fragment-1] == nil {
+		g.received++
+	}
+	g.parts[s.Fragment-1] = s
+
+	if g.received < s.Total {
+		return nil
+	}
+	delete(d.groups, key)
+	return g.parts
+}
@@ -0,0 +1,110 @@
+package ais
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestDecoderPositionReport(t *testing.T) {
+	doc, err := NewDecoder().Feed("!AIVDM,1,1,,B,177KQJ5000G?tO`K>RA1wUbN0TKH,0*5C")
+	if err != nil {
+		t.Fatalf("Feed: %v", err)
+	}
+	if doc.MMSI != 477553000 || doc.MessageType != 1 || doc.Talker != "AIVDM" || doc.Channel != "B" {
+		t.Fatalf("header = %d/%d/%s/%s", doc.MMSI, doc.MessageType, doc.Talker, doc.Channel)
+	}
+	report, ok := doc.Decoded.(*PositionReport)
+	if !ok {
+		t.Fatalf("decoded = %T, want *PositionReport", doc.Decoded)
+	}
+	if report.NavigationalStatus != 5 || report.TrueHeading != 181 || report.Timestamp != 15 {
+		t.Errorf("status/heading/second = %d/%d/%d, want 5/181/15", report.NavigationalStatus, report.TrueHeading, report.Timestamp)
+	}
+	if math.Abs(report.Latitude-47.582833) > 1e-5 || math.Abs(report.Longitude+122.345832) > 1e-5 {
+		t.Errorf("position = %f,%f, want 47.582833,-122.345832", report.Latitude, report.Longitude)
+	}
+	if report.Sog != 0 || report.Cog != 51 {
+		t.Errorf("sog/cog = %v/%v, want 0/51", report.Sog, report.Cog)
+	}
+	if got := doc.Collections(); len(got) != 1 || got[0] != CollectionDynamic {
+		t.Errorf("collections = %v", got)
+	}
+}
+
+func TestDecoderMultipartStatic(t *testing.T) {
+	decoder := NewDecoder()
+	doc, err := decoder.Feed("!AIVDM,2,1,1,A,55?MbV02;H;s<HtKR20EHE:0@T4@Dn2222222216L961O5Gf0NSQEp6ClRp8,0*1C")
+	if err != nil || doc != nil {
+		t.Fatalf("first fragment = %v, %v; want nil, nil", doc, err)
+	}
+	doc, err = decoder.Feed("!AIVDM,2,2,1,A,88888888880,2*25")
+	if err != nil {
+		t.Fatalf("second fragment: %v", err)
+	}
+	static, ok := doc.Decoded.(*ShipStaticData)
+	if !ok {
+		t.Fatalf("decoded = %T, want *ShipStaticData", doc.Decoded)
+	}
+	if doc.MMSI != 351759000 || static.ImoNumber != 9134270 || static.Type != 70 {
+		t.Errorf("mmsi/imo/type = %d/%d/%d", doc.MMSI, static.ImoNumber, static.Type)
+	}
+	if static.CallSign != "3FOF8" || static.Name != "EVER DIADEM" || static.Destination != "NEW YORK" {
+		t.Errorf("callsign/name/destination = %q/%q/%q", static.CallSign, static.Name, static.Destination)
+	}
+	if len(doc.Raw) != 2 {
+		t.Errorf("raw = %v, want both fragments", doc.Raw)
+	}
+}
+
+func TestDecoderMultipartMismatchedSequence(t *testing.T) {
+	decoder := NewDecoder()
+	if _, err := decoder.Feed("!AIVDM,2,1,1,A,55?MbV02;H;s<HtKR20EHE:0@T4@Dn2222222216L961O5Gf0NSQEp6ClRp8,0*1C"); err != nil {
+		t.Fatal(err)
+	}
+	// fragment kedua dengan sequence id lain tidak boleh melengkapi pesan
+	doc, err := decoder.Feed("!AIVDM,2,2,2,A,88888888880,2*26")
+	if err != nil || doc != nil {
+		t.Fatalf("mismatched fragment = %v, %v; want nil, nil", doc, err)
+	}
+}
+
+func TestDecoderErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want error
+	}{
+		{"checksum", "!AIVDM,1,1,,B,177KQJ5000G?tO`K>RA1wUbN0TKH,0*5D", ErrChecksum},
+		{"not ais", "$GPGGA,123519,4807.038,N,01131.000,E,1,08,0.9,545.4,M,46.9,M,,*47", ErrNotAis},
+		{"malformed", "!AIVDM,1,1,,B", ErrMalformed},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDecoder().Feed(tt.line)
+			if !errors.Is(err, tt.want) {
+				t.Errorf("err = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestPayloadSixBit(t *testing.T) {
+	// "1" = 000001, "w" = 111111
+	p, err := newPayload("1w", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.len() != 12 {
+		t.Fatalf("len = %d, want 12", p.len())
+	}
+	if got := p.uint(0, 6); got != 1 {
+		t.Errorf("uint(0,6) = %d, want 1", got)
+	}
+	if got := p.int(6, 6); got != -1 {
+		t.Errorf("int(6,6) = %d, want -1", got)
+	}
+	if got := p.uint(4, 4); got != 0b0111 {
+		t.Errorf("uint(4,4) = %b, want 111", got)
+	}
+}
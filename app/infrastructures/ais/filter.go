@@ -0,0 +1,47 @@
+package ais
+
+import (
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
+)
+
+// PositionFilter menyaring dokumen posisi untuk subscriber. Field kosong berarti
+// tidak difilter; semua field yang terisi harus cocok.
+type PositionFilter struct {
+	MmsiList []int64
+	Imei     string
+	Areas    []geo.Area
+}
+
+func (f *PositionFilter) Match(doc *Document) bool {
+	if f == nil {
+		return true
+	}
+	if len(f.MmsiList) > 0 {
+		found := false
+		for _, mmsi := range f.MmsiList {
+			if mmsi == doc.MMSI {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.Imei != "" && f.Imei != doc.Imei {
+		return false
+	}
+	if len(f.Areas) > 0 {
+		lat, lon, ok := doc.Position()
+		if !ok {
+			return false
+		}
+		p := geo.Point{Lat: lat, Lon: lon}
+		for _, area := range f.Areas {
+			if !area.Contains(p) {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,127 @@
+package ais
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"strings"
+)
+
+// maksimum contoh error yang dikembalikan ke client
+const maxErrorSamples = 20
+
+// Sink adalah tujuan penyimpanan dokumen hasil decode (lihat ships.ShipMongotory)
+type Sink interface {
+	InsertAisDocuments(ctx context.Context, collection string, docs []*Document) error
+}
+
+// IngestResult merangkum hasil satu kali ingest
+type IngestResult struct {
+	Sentences    int      `json:"sentences"`
+	Messages     int      `json:"messages"`
+	Stored       int      `json:"stored"`
+	Skipped      int      `json:"skipped"`
+	Errors       int      `json:"errors"`
+	ErrorSamples []string `json:"errorSamples"`
+}
+
+type Ingestor struct {
+	sink      Sink
+	positions *Broker
+	mobs      *Broker
+}
+
+func NewIngestor(sink Sink) *Ingestor {
+	return &Ingestor{
+		sink:      sink,
+		positions: NewBroker(),
+		mobs:      NewBroker(),
+	}
+}
+
+// SubscribePositions menerima setiap dokumen ais_dynamic yang berhasil disimpan
+func (i *Ingestor) SubscribePositions(buffer int) (<-chan *Document, func()) {
+	return i.positions.Subscribe(buffer)
+}
+
+// SubscribeMobs menerima setiap dokumen ais_mob (type 14, SART/MOB/EPIRB) yang berhasil disimpan
+func (i *Ingestor) SubscribeMobs(buffer int) (<-chan *Document, func()) {
+	return i.mobs.Subscribe(buffer)
+}
+
+// IngestLines men-decode kumpulan baris NMEA lalu menyimpannya per koleksi
+func (i *Ingestor) IngestLines(ctx context.Context, lines []string) (*IngestResult, error) {
+	return i.IngestReader(ctx, strings.NewReader(strings.Join(lines, "\n")))
+}
+
+// IngestReader membaca file log NMEA baris per baris
+func (i *Ingestor) IngestReader(ctx context.Context, r io.Reader) (*IngestResult, error) {
+	decoder := NewDecoder()
+	result := &IngestResult{ErrorSamples: []string{}}
+	batches := make(map[string][]*Document)
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		result.Sentences++
+
+		doc, err := decoder.Feed(line)
+		if err != nil {
+			if errors.Is(err, ErrUnsupported) || errors.Is(err, ErrNotAis) {
+				result.Skipped++
+				continue
+			}
+			result.Errors++
+			if len(result.ErrorSamples) < maxErrorSamples {
+				result.ErrorSamples = append(result.ErrorSamples, err.Error()+": "+line)
+			}
+			continue
+		}
+		if doc == nil {
+			continue
+		}
+
+		result.Messages++
+		for _, collection := range doc.Collections() {
+			batches[collection] = append(batches[collection], doc)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return result, err
+	}
+
+	for collection, docs := range batches {
+		if err := i.Store(ctx, collection, docs); err != nil {
+			return result, err
+		}
+		result.Stored += len(docs)
+	}
+
+	log.Printf("AIS ingest: %d sentences, %d messages, %d stored, %d errors",
+		result.Sentences, result.Messages, result.Stored, result.Errors)
+
+	return result, nil
+}
+
+// Store menyimpan dokumen ke satu koleksi
+func (i *Ingestor) Store(ctx context.Context, collection string, docs []*Document) error {
+	if len(docs) == 0 {
+		return nil
+	}
+	if err := i.sink.InsertAisDocuments(ctx, collection, docs); err != nil {
+		return err
+	}
+	switch collection {
+	case CollectionDynamic:
+		i.positions.Publish(docs)
+	case CollectionMob:
+		i.mobs.Publish(docs)
+	}
+	return nil
+}
@@ -0,0 +1,251 @@
+package ais
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"log"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	minReconnectDelay = 2 * time.Second
+	maxReconnectDelay = 1 * time.Minute
+)
+
+// Listener menjalankan semua stasiun receiver AIS (UDP/TCP, server/client)
+type Listener struct {
+	stations []*Station
+}
+
+func NewListener(ingestor *Ingestor, configs []StationConfig) *Listener {
+	stations := make([]*Station, len(configs))
+	for i, config := range configs {
+		stations[i] = newStation(config, ingestor)
+	}
+	return &Listener{stations: stations}
+}
+
+// Start dipanggil dari main.go dan berjalan sampai ctx dibatalkan
+func (l *Listener) Start(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, station := range l.stations {
+		wg.Add(2)
+		go func(s *Station) {
+			defer wg.Done()
+			s.runFlusher(ctx)
+		}(station)
+		go func(s *Station) {
+			defer wg.Done()
+			s.run(ctx)
+		}(station)
+	}
+	log.Printf("AIS listener started with %d station(s)", len(l.stations))
+	wg.Wait()
+}
+
+// Statuses mengembalikan counter semua stasiun
+func (l *Listener) Statuses() []*StationStatus {
+	statuses := make([]*StationStatus, len(l.stations))
+	for i, station := range l.stations {
+		statuses[i] = station.Status()
+	}
+	return statuses
+}
+
+func (s *Station) run(ctx context.Context) {
+	delay := minReconnectDelay
+	for {
+		startedAt := time.Now()
+		var err error
+		switch {
+		case s.config.Protocol == "udp" && s.config.Mode == "server":
+			err = s.serveUDP(ctx)
+		case s.config.Protocol == "udp":
+			err = s.dialUDP(ctx)
+		case s.config.Mode == "server":
+			err = s.serveTCP(ctx)
+		default:
+			err = s.dialTCP(ctx)
+		}
+
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			log.Printf("AIS station %s: %v (retry in %s)", s.config.Name, err, delay)
+			s.setError(err)
+		}
+
+		// Koneksi yang sempat stabil tidak perlu menunggu backoff panjang
+		if time.Since(startedAt) > maxReconnectDelay {
+			delay = minReconnectDelay
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > maxReconnectDelay {
+			delay = maxReconnectDelay
+		}
+	}
+}
+
+func (s *Station) serveUDP(ctx context.Context) error {
+	conn, err := net.ListenPacket("udp", s.config.Address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	defer closeOnDone(ctx, conn)()
+
+	s.setListening(true)
+	defer s.setListening(false)
+
+	// satu Decoder per alamat pengirim agar fragment multipart dari sumber
+	// berbeda tidak tergabung; pengirim yang diam lebih dari fragmentTTL dibuang
+	sources := make(map[string]*udpSource)
+	prunedAt := time.Now()
+	buf := make([]byte, 64*1024)
+	for {
+		n, addr, err := conn.ReadFrom(buf)
+		if err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if now.Sub(prunedAt) > fragmentTTL {
+			for key, source := range sources {
+				if now.Sub(source.lastAt) > fragmentTTL {
+					delete(sources, key)
+				}
+			}
+			prunedAt = now
+		}
+		source, ok := sources[addr.String()]
+		if !ok {
+			source = &udpSource{decoder: NewDecoder()}
+			sources[addr.String()] = source
+		}
+		source.lastAt = now
+
+		for _, line := range strings.Split(string(buf[:n]), "\n") {
+			s.handleLine(source.decoder, line)
+		}
+	}
+}
+
+// udpSource — Decoder milik satu alamat pengirim UDP
+type udpSource struct {
+	decoder *Decoder
+	lastAt  time.Time
+}
+
+func (s *Station) dialUDP(ctx context.Context) error {
+	conn, err := net.Dial("udp", s.config.Address)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	defer closeOnDone(ctx, conn)()
+
+	// Datagram kosong untuk mendaftarkan alamat kita ke server feed
+	if _, err := conn.Write([]byte("\r\n")); err != nil {
+		return err
+	}
+
+	s.addConnection(1)
+	defer s.addConnection(-1)
+
+	decoder := NewDecoder()
+	buf := make([]byte, 64*1024)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			return err
+		}
+		for _, line := range strings.Split(string(buf[:n]), "\n") {
+			s.handleLine(decoder, line)
+		}
+	}
+}
+
+func (s *Station) serveTCP(ctx context.Context) error {
+	ln, err := net.Listen("tcp", s.config.Address)
+	if err != nil {
+		return err
+	}
+	defer ln.Close()
+	defer closeOnDone(ctx, ln)()
+
+	s.setListening(true)
+	defer s.setListening(false)
+
+	for {
+		conn, err := ln.Accept()
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				continue
+			}
+			return err
+		}
+		go func() {
+			if err := s.readStream(ctx, conn); err != nil && ctx.Err() == nil {
+				log.Printf("AIS station %s: connection %s closed: %v", s.config.Name, conn.RemoteAddr(), err)
+			}
+		}()
+	}
+}
+
+func (s *Station) dialTCP(ctx context.Context) error {
+	dialer := net.Dialer{Timeout: 10 * time.Second, KeepAlive: 30 * time.Second}
+	conn, err := dialer.DialContext(ctx, "tcp", s.config.Address)
+	if err != nil {
+		return err
+	}
+	return s.readStream(ctx, conn)
+}
+
+func (s *Station) readStream(ctx context.Context, conn net.Conn) error {
+	defer conn.Close()
+	defer closeOnDone(ctx, conn)()
+
+	s.addConnection(1)
+	defer s.addConnection(-1)
+
+	decoder := NewDecoder()
+	scanner := bufio.NewScanner(conn)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		s.handleLine(decoder, scanner.Text())
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return errors.New("connection closed by peer")
+}
+
+type closer interface {
+	Close() error
+}
+
+// closeOnDone menutup koneksi saat ctx dibatalkan agar Read/Accept yang
+// sedang blocking ikut berhenti. Fungsi hasilnya menghentikan watcher.
+func closeOnDone(ctx context.Context, c closer) func() {
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			c.Close()
+		case <-done:
+		}
+	}()
+	return func() { close(done) }
+}
@@ -0,0 +1,239 @@
+package ais
+
+// Nama field mengikuti dokumen yang sudah ada di koleksi ais_* (decoded.Latitude,
+// decoded.Longitude, decoded.Text, ...) sehingga query lama tetap bisa membacanya.
+
+type Dimension struct {
+	A int `json:"A" bson:"A"` // ke haluan (m)
+	B int `json:"B" bson:"B"` // ke buritan (m)
+	C int `json:"C" bson:"C"` // ke kiri (m)
+	D int `json:"D" bson:"D"` // ke kanan (m)
+}
+
+type Eta struct {
+	Month  int `json:"Month" bson:"Month"`
+	Day    int `json:"Day" bson:"Day"`
+	Hour   int `json:"Hour" bson:"Hour"`
+	Minute int `json:"Minute" bson:"Minute"`
+}
+
+// PositionReport — message type 1, 2, 3 (Class A)
+type PositionReport struct {
+	NavigationalStatus        int     `json:"NavigationalStatus" bson:"NavigationalStatus"`
+	RateOfTurn                int     `json:"RateOfTurn" bson:"RateOfTurn"`
+	Sog                       float64 `json:"Sog" bson:"Sog"`
+	PositionAccuracy          bool    `json:"PositionAccuracy" bson:"PositionAccuracy"`
+	Longitude                 float64 `json:"Longitude" bson:"Longitude"`
+	Latitude                  float64 `json:"Latitude" bson:"Latitude"`
+	Cog                       float64 `json:"Cog" bson:"Cog"`
+	TrueHeading               int     `json:"TrueHeading" bson:"TrueHeading"`
+	Timestamp                 int     `json:"Timestamp" bson:"Timestamp"`
+	SpecialManoeuvreIndicator int     `json:"SpecialManoeuvreIndicator" bson:"SpecialManoeuvreIndicator"`
+	Raim                      bool    `json:"Raim" bson:"Raim"`
+}
+
+// ShipStaticData — message type 5
+type ShipStaticData struct {
+	AisVersion           int       `json:"AisVersion" bson:"AisVersion"`
+	ImoNumber            int64     `json:"ImoNumber" bson:"ImoNumber"`
+	CallSign             string    `json:"CallSign" bson:"CallSign"`
+	Name                 string    `json:"Name" bson:"Name"`
+	Type                 int       `json:"Type" bson:"Type"`
+	Dimension            Dimension `json:"Dimension" bson:"Dimension"`
+	FixType              int       `json:"FixType" bson:"FixType"`
+	Eta                  Eta       `json:"Eta" bson:"Eta"`
+	MaximumStaticDraught float64   `json:"MaximumStaticDraught" bson:"MaximumStaticDraught"`
+	Destination          string    `json:"Destination" bson:"Destination"`
+	Dte                  bool      `json:"Dte" bson:"Dte"`
+}
+
+// SafetyBroadcast — message type 14 (MOB, SART, EPIRB test/active)
+type SafetyBroadcast struct {
+	Text string `json:"Text" bson:"Text"`
+}
+
+// StandardClassBPositionReport — message type 18
+type StandardClassBPositionReport struct {
+	Sog              float64 `json:"Sog" bson:"Sog"`
+	PositionAccuracy bool    `json:"PositionAccuracy" bson:"PositionAccuracy"`
+	Longitude        float64 `json:"Longitude" bson:"Longitude"`
+	Latitude         float64 `json:"Latitude" bson:"Latitude"`
+	Cog              float64 `json:"Cog" bson:"Cog"`
+	TrueHeading      int     `json:"TrueHeading" bson:"TrueHeading"`
+	Timestamp        int     `json:"Timestamp" bson:"Timestamp"`
+	ClassBUnit       bool    `json:"ClassBUnit" bson:"ClassBUnit"`
+	ClassBDisplay    bool    `json:"ClassBDisplay" bson:"ClassBDisplay"`
+	ClassBDsc        bool    `json:"ClassBDsc" bson:"ClassBDsc"`
+	ClassBBand       bool    `json:"ClassBBand" bson:"ClassBBand"`
+	ClassBMsg22      bool    `json:"ClassBMsg22" bson:"ClassBMsg22"`
+	AssignedMode     bool    `json:"AssignedMode" bson:"AssignedMode"`
+	Raim             bool    `json:"Raim" bson:"Raim"`
+}
+
+// ExtendedClassBPositionReport — message type 19
+type ExtendedClassBPositionReport struct {
+	Sog              float64   `json:"Sog" bson:"Sog"`
+	PositionAccuracy bool      `json:"PositionAccuracy" bson:"PositionAccuracy"`
+	Longitude        float64   `json:"Longitude" bson:"Longitude"`
+	Latitude         float64   `json:"Latitude" bson:"Latitude"`
+	Cog              float64   `json:"Cog" bson:"Cog"`
+	TrueHeading      int       `json:"TrueHeading" bson:"TrueHeading"`
+	Timestamp        int       `json:"Timestamp" bson:"Timestamp"`
+	Name             string    `json:"Name" bson:"Name"`
+	Type             int       `json:"Type" bson:"Type"`
+	Dimension        Dimension `json:"Dimension" bson:"Dimension"`
+	FixType          int       `json:"FixType" bson:"FixType"`
+	Raim             bool      `json:"Raim" bson:"Raim"`
+	Dte              bool      `json:"Dte" bson:"Dte"`
+	AssignedMode     bool      `json:"AssignedMode" bson:"AssignedMode"`
+}
+
+// StaticDataReport — message type 24, part A (nama) atau part B (tipe, callsign, dimensi)
+type StaticDataReport struct {
+	PartNumber     int       `json:"PartNumber" bson:"PartNumber"`
+	Name           string    `json:"Name,omitempty" bson:"Name,omitempty"`
+	Type           int       `json:"Type,omitempty" bson:"Type,omitempty"`
+	VendorID       string    `json:"VendorID,omitempty" bson:"VendorID,omitempty"`
+	CallSign       string    `json:"CallSign,omitempty" bson:"CallSign,omitempty"`
+	Dimension      Dimension `json:"Dimension" bson:"Dimension"`
+	MothershipMmsi int64     `json:"MothershipMmsi,omitempty" bson:"MothershipMmsi,omitempty"`
+}
+
+// decodeMessage mengubah payload menjadi struct sesuai message type
+func decodeMessage(p *payload) (int, int64, any, error) {
+	msgType := int(p.uint(0, 6))
+	mmsi := int64(p.uint(8, 30))
+
+	switch msgType {
+	case 1, 2, 3:
+		if p.len() < 149 {
+			return msgType, mmsi, nil, ErrMalformed
+		}
+		return msgType, mmsi, &PositionReport{
+			NavigationalStatus:        int(p.uint(38, 4)),
+			RateOfTurn:                int(p.int(42, 8)),
+			Sog:                       float64(p.uint(50, 10)) / 10,
+			PositionAccuracy:          p.bool(60),
+			Longitude:                 float64(p.int(61, 28)) / 600000,
+			Latitude:                  float64(p.int(89, 27)) / 600000,
+			Cog:                       float64(p.uint(116, 12)) / 10,
+			TrueHeading:               int(p.uint(128, 9)),
+			Timestamp:                 int(p.uint(137, 6)),
+			SpecialManoeuvreIndicator: int(p.uint(143, 2)),
+			Raim:                      p.bool(148),
+		}, nil
+
+	case 5:
+		if p.len() < 420 {
+			return msgType, mmsi, nil, ErrMalformed
+		}
+		return msgType, mmsi, &ShipStaticData{
+			AisVersion: int(p.uint(38, 2)),
+			ImoNumber:  int64(p.uint(40, 30)),
+			CallSign:   p.string(70, 42),
+			Name:       p.string(112, 120),
+			Type:       int(p.uint(232, 8)),
+			Dimension: Dimension{
+				A: int(p.uint(240, 9)),
+				B: int(p.uint(249, 9)),
+				C: int(p.uint(258, 6)),
+				D: int(p.uint(264, 6)),
+			},
+			FixType: int(p.uint(270, 4)),
+			Eta: Eta{
+				Month:  int(p.uint(274, 4)),
+				Day:    int(p.uint(278, 5)),
+				Hour:   int(p.uint(283, 5)),
+				Minute: int(p.uint(288, 6)),
+			},
+			MaximumStaticDraught: float64(p.uint(294, 8)) / 10,
+			Destination:          p.string(302, 120),
+			Dte:                  p.bool(422),
+		}, nil
+
+	case 14:
+		if p.len() < 46 {
+			return msgType, mmsi, nil, ErrMalformed
+		}
+		return msgType, mmsi, &SafetyBroadcast{
+			Text: p.string(40, p.len()-40),
+		}, nil
+
+	case 18:
+		if p.len() < 148 {
+			return msgType, mmsi, nil, ErrMalformed
+		}
+		return msgType, mmsi, &StandardClassBPositionReport{
+			Sog:              float64(p.uint(46, 10)) / 10,
+			PositionAccuracy: p.bool(56),
+			Longitude:        float64(p.int(57, 28)) / 600000,
+			Latitude:         float64(p.int(85, 27)) / 600000,
+			Cog:              float64(p.uint(112, 12)) / 10,
+			TrueHeading:      int(p.uint(124, 9)),
+			Timestamp:        int(p.uint(133, 6)),
+			ClassBUnit:       p.bool(141),
+			ClassBDisplay:    p.bool(142),
+			ClassBDsc:        p.bool(143),
+			ClassBBand:       p.bool(144),
+			ClassBMsg22:      p.bool(145),
+			AssignedMode:     p.bool(146),
+			Raim:             p.bool(147),
+		}, nil
+
+	case 19:
+		if p.len() < 308 {
+			return msgType, mmsi, nil, ErrMalformed
+		}
+		return msgType, mmsi, &ExtendedClassBPositionReport{
+			Sog:              float64(p.uint(46, 10)) / 10,
+			PositionAccuracy: p.bool(56),
+			Longitude:        float64(p.int(57, 28)) / 600000,
+			Latitude:         float64(p.int(85, 27)) / 600000,
+			Cog:              float64(p.uint(112, 12)) / 10,
+			TrueHeading:      int(p.uint(124, 9)),
+			Timestamp:        int(p.uint(133, 6)),
+			Name:             p.string(143, 120),
+			Type:             int(p.uint(263, 8)),
+			Dimension: Dimension{
+				A: int(p.uint(271, 9)),
+				B: int(p.uint(280, 9)),
+				C: int(p.uint(289, 6)),
+				D: int(p.uint(295, 6)),
+			},
+			FixType:      int(p.uint(301, 4)),
+			Raim:         p.bool(305),
+			Dte:          p.bool(306),
+			AssignedMode: p.bool(307),
+		}, nil
+
+	case 24:
+		if p.len() < 160 {
+			return msgType, mmsi, nil, ErrMalformed
+		}
+		report := &StaticDataReport{PartNumber: int(p.uint(38, 2))}
+		switch report.PartNumber {
+		case 0:
+			report.Name = p.string(40, 120)
+		case 1:
+			report.Type = int(p.uint(40, 8))
+			report.VendorID = p.string(48, 18)
+			report.CallSign = p.string(90, 42)
+			// MMSI 98XXXYYYY = auxiliary craft, field dimensi berisi MMSI kapal induk
+			if mmsi/10000000 == 98 {
+				report.MothershipMmsi = int64(p.uint(132, 30))
+			} else {
+				report.Dimension = Dimension{
+					A: int(p.uint(132, 9)),
+					B: int(p.uint(141, 9)),
+					C: int(p.uint(150, 6)),
+					D: int(p.uint(156, 6)),
+				}
+			}
+		default:
+			return msgType, mmsi, nil, ErrMalformed
+		}
+		return msgType, mmsi, report, nil
+	}
+
+	return msgType, mmsi, nil, ErrUnsupported
+}
@@ -0,0 +1,58 @@
+package ais
+
+import "testing"
+
+func TestValidShipMmsi(t *testing.T) {
+	tests := []struct {
+		mmsi int64
+		want bool
+	}{
+		{525005123, true},  // kapal Indonesia
+		{477553000, true},  // kapal Hong Kong
+		{366999712, true},  // kapal Amerika Serikat
+		{111525001, true},  // pesawat SAR 111MID
+		{985251234, true},  // craft associated 98MID
+		{852512345, true},  // handheld VHF 8MID
+		{123456789, false}, // bawaan transponder
+		{987654321, false},
+		{555555555, false}, // digit berulang
+		{111111111, false},
+		{525000000, false}, // MID diikuti 000000
+		{200123456, false}, // MID tidak dikenal
+		{111200001, false},
+		{2050000, false},    // stasiun pantai 00MID, kurang dari 9 digit
+		{992051234, false},  // AtoN 99MID
+		{12345678, false},   // 8 digit
+		{1000000000, false}, // 10 digit
+		{0, false},
+		{-525005123, false},
+	}
+
+	for _, tt := range tests {
+		if got := ValidShipMmsi(tt.mmsi); got != tt.want {
+			t.Errorf("ValidShipMmsi(%d) = %v, want %v", tt.mmsi, got, tt.want)
+		}
+	}
+}
+
+func TestFlagOf(t *testing.T) {
+	tests := []struct {
+		mmsi int64
+		want string
+	}{
+		{525005123, "ID"},
+		{111525001, "ID"},
+		{2050000, "BE"}, // 002050000 stasiun pantai
+		{992051234, "BE"},
+		{852512345, "ID"},
+		{200123456, ""},
+		{1000000000, ""},
+		{-1, ""},
+	}
+
+	for _, tt := range tests {
+		if got := FlagOf(tt.mmsi); got != tt.want {
+			t.Errorf("FlagOf(%d) = %q, want %q", tt.mmsi, got, tt.want)
+		}
+	}
+}
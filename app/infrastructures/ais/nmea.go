@@ -0,0 +1,154 @@
+package ais
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotAis      = errors.New("not an AIVDM/AIVDO sentence")
+	ErrMalformed   = errors.New("malformed NMEA sentence")
+	ErrChecksum    = errors.New("NMEA checksum mismatch")
+	ErrUnsupported = errors.New("unsupported AIS message type")
+)
+
+// Sentence adalah satu baris !AIVDM / !AIVDO yang sudah dipecah per field
+type Sentence struct {
+	Raw        string
+	Talker     string // AIVDM | AIVDO
+	Total      int
+	Fragment   int
+	SequenceID string
+	Channel    string
+	Payload    string
+	FillBits   int
+	// Timestamp diambil dari tag block (c:) atau prefix epoch di log, jika ada
+	Timestamp *time.Time
+}
+
+// ParseSentence mem-parse satu baris NMEA, termasuk tag block (\c:...\) dan
+// prefix epoch yang sering muncul di file log hasil capture.
+func ParseSentence(line string) (*Sentence, error) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return nil, ErrNotAis
+	}
+
+	var ts *time.Time
+
+	// Tag block: \s:station,c:1569820047*hh\!AIVDM,...
+	if strings.HasPrefix(line, "\\") {
+		end := strings.Index(line[1:], "\\")
+		if end < 0 {
+			return nil, ErrMalformed
+		}
+		ts = parseTagBlockTime(line[1 : end+1])
+		line = line[end+2:]
+	}
+
+	idx := strings.Index(line, "!")
+	if idx < 0 {
+		return nil, ErrNotAis
+	}
+
+	// Prefix epoch: "1569820047.123 !AIVDM,..."
+	if idx > 0 && ts == nil {
+		ts = parseEpochPrefix(strings.TrimSpace(line[:idx]))
+	}
+	line = line[idx:]
+
+	star := strings.LastIndex(line, "*")
+	if star < 0 || len(line) < star+3 {
+		return nil, ErrMalformed
+	}
+
+	body := line[1:star]
+	want, err := strconv.ParseUint(line[star+1:star+3], 16, 8)
+	if err != nil {
+		return nil, ErrMalformed
+	}
+	if checksum(body) != byte(want) {
+		return nil, ErrChecksum
+	}
+
+	fields := strings.Split(body, ",")
+	if len(fields) < 7 {
+		return nil, ErrMalformed
+	}
+
+	talker := fields[0]
+	if len(talker) != 5 || (talker[2:] != "VDM" && talker[2:] != "VDO") {
+		return nil, ErrNotAis
+	}
+
+	total, err := strconv.Atoi(fields[1])
+	if err != nil || total < 1 {
+		return nil, ErrMalformed
+	}
+	fragment, err := strconv.Atoi(fields[2])
+	if err != nil || fragment < 1 || fragment > total {
+		return nil, ErrMalformed
+	}
+	fill, err := strconv.Atoi(fields[6])
+	if err != nil || fill < 0 || fill > 5 {
+		return nil, ErrMalformed
+	}
+
+	return &Sentence{
+		Raw:        line[:star+3],
+		Talker:     talker,
+		Total:      total,
+		Fragment:   fragment,
+		SequenceID: fields[3],
+		Channel:    fields[4],
+		Payload:    fields[5],
+		FillBits:   fill,
+		Timestamp:  ts,
+	}, nil
+}
+
+func checksum(body string) byte {
+	var sum byte
+	for i := 0; i < len(body); i++ {
+		sum ^= body[i]
+	}
+	return sum
+}
+
+func parseTagBlockTime(block string) *time.Time {
+	if star := strings.Index(block, "*"); star >= 0 {
+		block = block[:star]
+	}
+	for _, part := range strings.Split(block, ",") {
+		if !strings.HasPrefix(part, "c:") {
+			continue
+		}
+		return parseEpochPrefix(part[2:])
+	}
+	return nil
+}
+
+func parseEpochPrefix(s string) *time.Time {
+	if s == "" {
+		return nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v <= 0 {
+		return nil
+	}
+	// Beberapa receiver menulis epoch dalam milidetik
+	if v > 1e11 {
+		v /= 1000
+	}
+	sec := int64(v)
+	t := time.Unix(sec, int64((v-float64(sec))*1e9)).UTC()
+	return &t
+}
+
+// String dipakai untuk log error decoding
+func (s *Sentence) String() string {
+	return fmt.Sprintf("%s %d/%d seq=%s ch=%s", s.Talker, s.Fragment, s.Total, s.SequenceID, s.Channel)
+}
@@ -0,0 +1,77 @@
+package ais
+
+import (
+	"strings"
+)
+
+// payload menyimpan bit-bit hasil de-armoring karakter 6-bit AIS
+type payload struct {
+	bits []byte // satu elemen per bit (0/1)
+}
+
+func newPayload(armored string, fillBits int) (*payload, error) {
+	bits := make([]byte, 0, len(armored)*6)
+	for i := 0; i < len(armored); i++ {
+		c := armored[i]
+		if c < 48 || c > 119 || (c > 87 && c < 96) {
+			return nil, ErrMalformed
+		}
+		v := c - 48
+		if v > 40 {
+			v -= 8
+		}
+		for b := 5; b >= 0; b-- {
+			bits = append(bits, (v>>uint(b))&1)
+		}
+	}
+	if fillBits > 0 && fillBits <= len(bits) {
+		bits = bits[:len(bits)-fillBits]
+	}
+	return &payload{bits: bits}, nil
+}
+
+func (p *payload) len() int {
+	return len(p.bits)
+}
+
+// uint membaca n bit unsigned mulai dari posisi start. Bit di luar panjang
+// payload dianggap 0 agar pesan yang sedikit terpotong tetap bisa dibaca.
+func (p *payload) uint(start, n int) uint64 {
+	var v uint64
+	for i := start; i < start+n; i++ {
+		v <<= 1
+		if i < len(p.bits) {
+			v |= uint64(p.bits[i])
+		}
+	}
+	return v
+}
+
+func (p *payload) int(start, n int) int64 {
+	v := p.uint(start, n)
+	if v&(1<<uint(n-1)) != 0 {
+		return int64(v) - (1 << uint(n))
+	}
+	return int64(v)
+}
+
+func (p *payload) bool(start int) bool {
+	return p.uint(start, 1) == 1
+}
+
+// string membaca teks 6-bit ASCII AIS, '@' dan spasi di akhir dibuang
+func (p *payload) string(start, n int) string {
+	var sb strings.Builder
+	for i := start; i+6 <= start+n && i < len(p.bits); i += 6 {
+		c := byte(p.uint(i, 6))
+		if c < 32 {
+			c += 64
+		}
+		sb.WriteByte(c)
+	}
+	s := sb.String()
+	if at := strings.IndexByte(s, '@'); at >= 0 {
+		s = s[:at]
+	}
+	return strings.TrimRight(s, " ")
+}
@@ -0,0 +1,272 @@
+package ais
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// jendela rata-rata messages per second
+	rateWindow = 60
+	// stasiun dianggap diam bila tidak ada pesan selama durasi default ini
+	defaultSilentAfter = 5 * time.Minute
+	// batas dokumen di buffer sebelum dipaksa flush
+	maxPending = 500
+)
+
+// StationConfig adalah konfigurasi satu receiver, dibaca dari env AIS_STATIONS (JSON array)
+//
+//	AIS_STATIONS=[{"name":"merak","protocol":"udp","mode":"server","address":"0.0.0.0:10110"}]
+type StationConfig struct {
+	Name               string `json:"name"`
+	Protocol           string `json:"protocol"` // udp | tcp
+	Mode               string `json:"mode"`     // server | client
+	Address            string `json:"address"`
+	Imei               string `json:"imei,omitempty"` // opsional: perangkat milik sendiri, dipasang pada kalimat VDO
+	SilentAfterSeconds int    `json:"silentAfterSeconds,omitempty"`
+}
+
+// LoadStationConfigs membaca konfigurasi stasiun dari environment
+func LoadStationConfigs() ([]StationConfig, error) {
+	raw := strings.TrimSpace(os.Getenv("AIS_STATIONS"))
+	if raw == "" {
+		return nil, nil
+	}
+
+	var configs []StationConfig
+	if err := json.Unmarshal([]byte(raw), &configs); err != nil {
+		return nil, fmt.Errorf("invalid AIS_STATIONS: %w", err)
+	}
+
+	for i := range configs {
+		c := &configs[i]
+		c.Protocol = strings.ToLower(c.Protocol)
+		c.Mode = strings.ToLower(c.Mode)
+		if c.Name == "" {
+			c.Name = fmt.Sprintf("%s-%s-%s", c.Protocol, c.Mode, c.Address)
+		}
+		if c.Protocol != "udp" && c.Protocol != "tcp" {
+			return nil, fmt.Errorf("station %s: protocol must be udp or tcp", c.Name)
+		}
+		if c.Mode != "server" && c.Mode != "client" {
+			return nil, fmt.Errorf("station %s: mode must be server or client", c.Name)
+		}
+		if c.Address == "" {
+			return nil, fmt.Errorf("station %s: address is required", c.Name)
+		}
+	}
+
+	return configs, nil
+}
+
+// StationStatus adalah snapshot counter satu stasiun
+type StationStatus struct {
+	Name              string  `json:"name"`
+	Protocol          string  `json:"protocol"`
+	Mode              string  `json:"mode"`
+	Address           string  `json:"address"`
+	Connected         bool    `json:"connected"`
+	Connections       int     `json:"connections"`
+	MessagesPerSecond float64 `json:"messagesPerSecond"`
+	Sentences         int64   `json:"sentences"`
+	Messages          int64   `json:"messages"`
+	DecodeErrors      int64   `json:"decodeErrors"`
+	LastMessageAt     *int64  `json:"lastMessageAt,omitempty"`
+	Silent            bool    `json:"silent"`
+	LastError         *string `json:"lastError,omitempty"`
+	StartedAt         int64   `json:"startedAt"`
+}
+
+// Station menyimpan counter dan buffer dokumen satu receiver
+type Station struct {
+	config   StationConfig
+	ingestor *Ingestor
+
+	mu            sync.Mutex
+	buckets       [rateWindow]int64
+	bucketAt      [rateWindow]int64
+	sentences     int64
+	messages      int64
+	decodeErrors  int64
+	lastMessageAt time.Time
+	connections   int
+	listening     bool
+	lastError     string
+	startedAt     time.Time
+	pending       []*Document
+	flushSignal   chan struct{}
+}
+
+func newStation(config StationConfig, ingestor *Ingestor) *Station {
+	return &Station{
+		config:      config,
+		ingestor:    ingestor,
+		startedAt:   time.Now(),
+		flushSignal: make(chan struct{}, 1),
+	}
+}
+
+// handleLine men-decode satu baris dari feed lalu menaruhnya di buffer
+func (s *Station) handleLine(decoder *Decoder, line string) {
+	line = strings.TrimSpace(line)
+	if line == "" {
+		return
+	}
+
+	doc, err := decoder.Feed(line)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sentences++
+	if err != nil {
+		if !errors.Is(err, ErrUnsupported) && !errors.Is(err, ErrNotAis) {
+			s.decodeErrors++
+		}
+		return
+	}
+	if doc == nil {
+		return
+	}
+
+	doc.Station = s.config.Name
+	// IMEI hanya untuk posisi kapal sendiri (VDO); VDM adalah kapal lain di sekitar receiver
+	if strings.HasSuffix(doc.Talker, "VDO") {
+		doc.Imei = s.config.Imei
+	}
+
+	now := time.Now()
+	sec := now.Unix()
+	i := sec % rateWindow
+	if s.bucketAt[i] != sec {
+		s.bucketAt[i] = sec
+		s.buckets[i] = 0
+	}
+	s.buckets[i]++
+	s.messages++
+	s.lastMessageAt = now
+	s.pending = append(s.pending, doc)
+
+	if len(s.pending) >= maxPending {
+		select {
+		case s.flushSignal <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// runFlusher menulis buffer ke Mongo secara periodik
+func (s *Station) runFlusher(ctx context.Context) {
+	ticker := time.NewTicker(1 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.flush(context.Background())
+			return
+		case <-ticker.C:
+			s.flush(ctx)
+		case <-s.flushSignal:
+			s.flush(ctx)
+		}
+	}
+}
+
+func (s *Station) flush(ctx context.Context) {
+	s.mu.Lock()
+	docs := s.pending
+	s.pending = nil
+	s.mu.Unlock()
+
+	if len(docs) == 0 {
+		return
+	}
+
+	batches := make(map[string][]*Document)
+	for _, doc := range docs {
+		for _, collection := range doc.Collections() {
+			batches[collection] = append(batches[collection], doc)
+		}
+	}
+
+	for collection, batch := range batches {
+		if err := s.ingestor.Store(ctx, collection, batch); err != nil {
+			log.Printf("AIS station %s: failed to store %d docs to %s: %v", s.config.Name, len(batch), collection, err)
+			s.setError(err)
+		}
+	}
+}
+
+func (s *Station) setError(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastError = err.Error()
+}
+
+func (s *Station) setListening(listening bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listening = listening
+}
+
+func (s *Station) addConnection(delta int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.connections += delta
+}
+
+// Status mengembalikan snapshot counter stasiun
+func (s *Station) Status() *StationStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	var total int64
+	for i := 0; i < rateWindow; i++ {
+		if now.Unix()-s.bucketAt[i] < rateWindow {
+			total += s.buckets[i]
+		}
+	}
+
+	silentAfter := defaultSilentAfter
+	if s.config.SilentAfterSeconds > 0 {
+		silentAfter = time.Duration(s.config.SilentAfterSeconds) * time.Second
+	}
+
+	status := &StationStatus{
+		Name:              s.config.Name,
+		Protocol:          s.config.Protocol,
+		Mode:              s.config.Mode,
+		Address:           s.config.Address,
+		Connections:       s.connections,
+		Connected:         s.connections > 0 || s.listening,
+		MessagesPerSecond: float64(total) / rateWindow,
+		Sentences:         s.sentences,
+		Messages:          s.messages,
+		DecodeErrors:      s.decodeErrors,
+		StartedAt:         s.startedAt.UnixMilli(),
+	}
+
+	if !s.lastMessageAt.IsZero() {
+		last := s.lastMessageAt.UnixMilli()
+		status.LastMessageAt = &last
+		status.Silent = now.Sub(s.lastMessageAt) > silentAfter
+	} else {
+		status.Silent = now.Sub(s.startedAt) > silentAfter
+	}
+
+	if s.lastError != "" {
+		lastError := s.lastError
+		status.LastError = &lastError
+	}
+
+	return status
+}
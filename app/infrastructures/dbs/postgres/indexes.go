@@ -0,0 +1,32 @@
+package postgres
+
+import (
+	"gorm.io/gorm"
+)
+
+// Indeks yang tidak bisa dinyatakan lewat tag gorm. Insiden terbuka
+// (NEW/ACKNOWLEDGED/IN_PROGRESS) dengan dedup_key yang sama dari data lama
+// ditutup lebih dulu, kecuali yang terakhir terlihat, agar indeks unik bisa dibuat.
+var indexMigrations = []string{
+	`UPDATE incidents SET status = 'RESOLVED', resolved_at = (EXTRACT(EPOCH FROM NOW()) * 1000)::bigint
+	WHERE deleted_at = 0 AND status IN ('NEW', 'ACKNOWLEDGED', 'IN_PROGRESS')
+		AND id NOT IN (
+			SELECT DISTINCT ON (dedup_key) id FROM incidents
+			WHERE deleted_at = 0 AND status IN ('NEW', 'ACKNOWLEDGED', 'IN_PROGRESS')
+			ORDER BY dedup_key, last_seen_at DESC, id DESC
+		)`,
+	`CREATE UNIQUE INDEX IF NOT EXISTS idx_incidents_open_dedup_key ON incidents (dedup_key)
+	WHERE deleted_at = 0 AND status IN ('NEW', 'ACKNOWLEDGED', 'IN_PROGRESS')`,
+}
+
+// migrateIndexes dijalankan setelah AutoMigrate
+func migrateIndexes(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range indexMigrations {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"log"
+	"os"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// Kolom geography dibuat lewat fungsi IMMUTABLE yang mengembalikan NULL untuk
+// data tidak valid (lat/lng di luar rentang, ring lama tanpa pembungkus), agar
+// insert tidak gagal; baris NULL ditangani fallback di repository.
+var postgisMigrations = []string{
+	`CREATE OR REPLACE FUNCTION marker_geography(lat double precision, lng double precision)
+	RETURNS geography LANGUAGE sql IMMUTABLE AS $$
+		SELECT CASE WHEN lat BETWEEN -90 AND 90 AND lng BETWEEN -180 AND 180
+			THEN ST_SetSRID(ST_MakePoint(lng, lat), 4326)::geography END
+	$$`,
+	`CREATE OR REPLACE FUNCTION geofence_geography(geo_type text, coordinates jsonb)
+	RETURNS geography LANGUAGE plpgsql IMMUTABLE AS $$
+	BEGIN
+		RETURN ST_SetSRID(ST_GeomFromGeoJSON(json_build_object('type', geo_type, 'coordinates', coordinates)::text), 4326)::geography;
+	EXCEPTION WHEN others THEN
+		RETURN NULL;
+	END
+	$$`,
+	`ALTER TABLE markers ADD COLUMN IF NOT EXISTS geog geography(Point, 4326)
+		GENERATED ALWAYS AS (marker_geography(lat, lng)) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_markers_geog ON markers USING GIST (geog)`,
+	`ALTER TABLE geofences ADD COLUMN IF NOT EXISTS geog geography
+		GENERATED ALWAYS AS (geofence_geography(geo_type, coordinates)) STORED`,
+	`CREATE INDEX IF NOT EXISTS idx_geofences_geog ON geofences USING GIST (geog)`,
+}
+
+// EnablePostGIS memasang ekstensi postgis dan kolom geography markers/geofences.
+// POSTGIS_ENABLED=false mematikannya; kegagalan apa pun hanya di-log dan
+// aplikasi tetap berjalan dengan perhitungan jarak lama.
+func EnablePostGIS(db *gorm.DB) bool {
+	if strings.EqualFold(os.Getenv("POSTGIS_ENABLED"), "false") {
+		log.Println("PostGIS disabled by POSTGIS_ENABLED, using fallback geometry queries")
+		return false
+	}
+
+	if err := db.Exec(`CREATE EXTENSION IF NOT EXISTS postgis`).Error; err != nil {
+		log.Printf("PostGIS not available, using fallback geometry queries: %v", err)
+		return false
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, statement := range postgisMigrations {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("PostGIS migration failed, using fallback geometry queries: %v", err)
+		return false
+	}
+
+	log.Println("PostGIS enabled for markers and geofences")
+	return true
+}
@@ -8,6 +8,7 @@ import (
 	"os"
 
 	geofences "github.com/khoirulhasin/untirta_api/app/domains/geofances"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/pkg"
 	"github.com/khoirulhasin/untirta_api/app/models"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -41,6 +42,9 @@ func Connect() *gorm.DB {
 		panic(err)
 	}
 
+	// PostGIS opsional, setelah tabel markers dan geofences ada
+	pkg.SetPostGIS(EnablePostGIS(db))
+
 	flag.Parse()
 
 	// Auto migrate if requested
@@ -64,7 +68,7 @@ func Connect() *gorm.DB {
 }
 
 func Automigrate(db *gorm.DB) error {
-	return db.AutoMigrate(
+	err := db.AutoMigrate(
 		models.User{},
 		models.Role{},
 		models.Users2role{},
@@ -79,5 +83,16 @@ func Automigrate(db *gorm.DB) error {
 		models.MarkerType{},
 		models.Cam{},
 		geofences.GeofenceDB{},
+		models.Voyage{},
+		models.Alert{},
+		models.GeofenceEvent{},
+		geofences.GeofenceAlertRuleDB{},
+		models.Incident{},
+		models.IncidentEvent{},
 	)
+	if err != nil {
+		return err
+	}
+
+	return migrateIndexes(db)
 }
@@ -32,6 +32,12 @@ var pgErrorMessages = map[string]string{
 	"0A000": "Fitur tidak tersedia pada versi PostgreSQL yang digunakan",
 }
 
+// IsUniqueViolation memeriksa apakah err adalah pelanggaran constraint unik (23505)
+func IsUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
+
 func ParsePGError(err error) string {
 	var pgErr *pgconn.PgError
 	if errors.As(err, &pgErr) {
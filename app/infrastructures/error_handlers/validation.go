@@ -0,0 +1,74 @@
+package error_handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// batas jumlah kesalahan yang dilaporkan per input
+const maxFieldErrors = 20
+
+// FieldError adalah kesalahan validasi pada satu field input, mis. "coordinates[0][3]"
+type FieldError struct {
+	Field   string
+	Message string
+}
+
+func (e *FieldError) Error() string {
+	return e.Field + ": " + e.Message
+}
+
+// ValidationErrors dikembalikan domain saat input tidak valid. Resolver
+// mengubahnya menjadi satu GraphQL error per field lewat ParseValidationError.
+type ValidationErrors []*FieldError
+
+func (e ValidationErrors) Error() string {
+	messages := make([]string, len(e))
+	for i, fieldErr := range e {
+		messages[i] = fieldErr.Error()
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Add menambah satu kesalahan; kesalahan setelah batas maxFieldErrors dibuang
+func (e *ValidationErrors) Add(field string, format string, args ...any) {
+	if len(*e) >= maxFieldErrors {
+		return
+	}
+	*e = append(*e, &FieldError{Field: field, Message: fmt.Sprintf(format, args...)})
+}
+
+// Err mengembalikan nil bila tidak ada kesalahan
+func (e ValidationErrors) Err() error {
+	if len(e) == 0 {
+		return nil
+	}
+	return e
+}
+
+// ParseValidationError mengubah ValidationErrors menjadi gqlerror.List dengan
+// extensions {code: "VALIDATION", field}; error lain dikembalikan apa adanya
+func ParseValidationError(ctx context.Context, err error) error {
+	var validationErrors ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return err
+	}
+
+	list := make(gqlerror.List, 0, len(validationErrors))
+	for _, fieldErr := range validationErrors {
+		list = append(list, &gqlerror.Error{
+			Message: fieldErr.Error(),
+			Path:    graphql.GetPath(ctx),
+			Extensions: map[string]any{
+				"code":  "VALIDATION",
+				"field": fieldErr.Field,
+			},
+		})
+	}
+	return list
+}
@@ -0,0 +1,88 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Sel grid cluster = 1/4 lebar tile web map pada zoom yang sama (~64px dari 256px)
+const clusterCellShift = 2
+
+// ClusterPoint adalah titik yang akan di-cluster (vessel: MMSI, marker: ID)
+type ClusterPoint struct {
+	ID int64
+	Point
+}
+
+// Cluster adalah hasil grid clustering. MemberIDs hanya diisi untuk cluster kecil.
+type Cluster struct {
+	ID        string  `json:"id"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+	Count     int     `json:"count"`
+	MemberIDs []int64 `json:"memberIds,omitempty"`
+}
+
+// ClusterCellSize mengembalikan ukuran sel grid (derajat) untuk zoom peta
+func ClusterCellSize(zoom int) float64 {
+	return 360 / math.Pow(2, float64(zoom+clusterCellShift))
+}
+
+// ClusterMaxTileZoom — tile cache tidak boleh lebih kecil dari sel cluster agar
+// satu sel tidak terbelah di dua tile
+func ClusterMaxTileZoom(zoom int) int {
+	return zoom + clusterCellShift
+}
+
+// GridCluster mengelompokkan titik per sel grid. Centroid adalah rata-rata anggota.
+func GridCluster(points []ClusterPoint, zoom int, maxMembers int) []*Cluster {
+	cell := ClusterCellSize(zoom)
+
+	type cellKey struct{ x, y int }
+	type bucket struct {
+		sumLat, sumLon float64
+		ids            []int64
+	}
+	buckets := make(map[cellKey]*bucket)
+	var keys []cellKey
+
+	for _, p := range points {
+		key := cellKey{
+			x: int(math.Floor((p.Lon + 180) / cell)),
+			y: int(math.Floor((p.Lat + 90) / cell)),
+		}
+		b, ok := buckets[key]
+		if !ok {
+			b = &bucket{}
+			buckets[key] = b
+			keys = append(keys, key)
+		}
+		b.sumLat += p.Lat
+		b.sumLon += p.Lon
+		b.ids = append(b.ids, p.ID)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].x != keys[j].x {
+			return keys[i].x < keys[j].x
+		}
+		return keys[i].y < keys[j].y
+	})
+
+	clusters := make([]*Cluster, 0, len(keys))
+	for _, key := range keys {
+		b := buckets[key]
+		c := &Cluster{
+			ID:        fmt.Sprintf("%d/%d/%d", zoom, key.x, key.y),
+			Latitude:  b.sumLat / float64(len(b.ids)),
+			Longitude: b.sumLon / float64(len(b.ids)),
+			Count:     len(b.ids),
+		}
+		if len(b.ids) <= maxMembers {
+			c.MemberIDs = b.ids
+		}
+		clusters = append(clusters, c)
+	}
+	return clusters
+}
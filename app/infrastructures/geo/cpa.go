@@ -0,0 +1,88 @@
+package geo
+
+import (
+	"math"
+	"time"
+)
+
+// Motion adalah posisi beserta course & speed over ground pada satu waktu
+type Motion struct {
+	Point
+	// knot
+	Speed float64
+	// derajat true north
+	Course float64
+	At     time.Time
+}
+
+// DeadReckon memproyeksikan posisi ke waktu at dengan course & speed tetap
+func (m Motion) DeadReckon(at time.Time) Motion {
+	hours := at.Sub(m.At).Hours()
+	if hours == 0 || m.Speed <= 0 {
+		m.At = at
+		return m
+	}
+	meters := m.Speed * hours * MetersPerNauticalMile
+	course := m.Course
+	if meters < 0 {
+		meters, course = -meters, math.Mod(course+180, 360)
+	}
+	m.Point = Destination(m.Point, course, meters)
+	m.At = at
+	return m
+}
+
+// Approach adalah hasil perhitungan closest point of approach
+type Approach struct {
+	// jarak saat ini (m)
+	DistanceMeters float64
+	// bearing dari own ke target (derajat)
+	Bearing float64
+	// jarak terdekat (m)
+	CpaMeters float64
+	// waktu ke CPA; negatif berarti CPA sudah lewat (kapal saling menjauh)
+	Tcpa time.Duration
+}
+
+// ClosestApproach menghitung CPA/TCPA dua kapal dengan course & speed tetap.
+// Kedua posisi diproyeksikan dulu ke waktu yang sama (waktu own), lalu dihitung
+// pada bidang datar lokal di sekitar own — cukup akurat untuk jarak puluhan NM.
+func ClosestApproach(own, target Motion) Approach {
+	target = target.DeadReckon(own.At)
+
+	approach := Approach{
+		DistanceMeters: DistanceMeters(own.Point, target.Point),
+		Bearing:        Bearing(own.Point, target.Point),
+	}
+
+	// posisi relatif (NM) di bidang lokal
+	dLon := target.Lon - own.Lon
+	if dLon > 180 {
+		dLon -= 360
+	} else if dLon < -180 {
+		dLon += 360
+	}
+	rx := dLon * 60 * math.Cos(toRad((own.Lat+target.Lat)/2))
+	ry := (target.Lat - own.Lat) * 60
+
+	// kecepatan relatif (knot)
+	vx := target.Speed*math.Sin(toRad(target.Course)) - own.Speed*math.Sin(toRad(own.Course))
+	vy := target.Speed*math.Cos(toRad(target.Course)) - own.Speed*math.Cos(toRad(own.Course))
+
+	v2 := vx*vx + vy*vy
+	if v2 < 1e-9 {
+		// gerak relatif nol: jarak tetap
+		approach.CpaMeters = approach.DistanceMeters
+		return approach
+	}
+
+	tcpaHours := -(rx*vx + ry*vy) / v2
+	approach.Tcpa = time.Duration(tcpaHours * float64(time.Hour))
+	if tcpaHours <= 0 {
+		approach.CpaMeters = approach.DistanceMeters
+		return approach
+	}
+	cx, cy := rx+vx*tcpaHours, ry+vy*tcpaHours
+	approach.CpaMeters = math.Hypot(cx, cy) * MetersPerNauticalMile
+	return approach
+}
@@ -0,0 +1,98 @@
+package geo
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestClosestApproach(t *testing.T) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	// 10 NM ke timur di ekuator = 1/6 derajat bujur
+	east10 := Point{Lat: 0, Lon: 10.0 / 60}
+
+	tests := []struct {
+		name        string
+		own, target Motion
+		wantCpaNm   float64
+		wantTcpa    time.Duration
+	}{
+		{
+			name:      "head-on",
+			own:       Motion{Point: Point{0, 0}, Speed: 10, Course: 90, At: at},
+			target:    Motion{Point: east10, Speed: 10, Course: 270, At: at},
+			wantCpaNm: 0,
+			wantTcpa:  30 * time.Minute,
+		},
+		{
+			name:      "stationary target abeam",
+			own:       Motion{Point: Point{0, 0}, Speed: 10, Course: 0, At: at},
+			target:    Motion{Point: Point{Lat: 0, Lon: 2.0 / 60}, Speed: 0, At: at},
+			wantCpaNm: 2,
+			wantTcpa:  0,
+		},
+		{
+			name:      "overtaking from astern",
+			own:       Motion{Point: east10, Speed: 10, Course: 90, At: at},
+			target:    Motion{Point: Point{Lat: 1.0 / 60, Lon: 0}, Speed: 20, Course: 90, At: at},
+			wantCpaNm: 1,
+			wantTcpa:  time.Hour,
+		},
+		{
+			name:      "same course and speed keeps distance",
+			own:       Motion{Point: Point{0, 0}, Speed: 12, Course: 45, At: at},
+			target:    Motion{Point: east10, Speed: 12, Course: 45, At: at},
+			wantCpaNm: 10,
+			wantTcpa:  0,
+		},
+		{
+			name:      "diverging: CPA already passed",
+			own:       Motion{Point: Point{0, 0}, Speed: 10, Course: 270, At: at},
+			target:    Motion{Point: east10, Speed: 10, Course: 90, At: at},
+			wantCpaNm: 10,
+			wantTcpa:  -30 * time.Minute,
+		},
+		{
+			name: "target report older than own is dead-reckoned",
+			own:  Motion{Point: Point{0, 0}, Speed: 10, Course: 90, At: at},
+			// 15 menit lalu target berada 12.5 NM ke timur = 10 NM sekarang
+			target:    Motion{Point: Point{Lat: 0, Lon: 12.5 / 60}, Speed: 10, Course: 270, At: at.Add(-15 * time.Minute)},
+			wantCpaNm: 0,
+			wantTcpa:  30 * time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ClosestApproach(tt.own, tt.target)
+			if cpaNm := got.CpaMeters / MetersPerNauticalMile; math.Abs(cpaNm-tt.wantCpaNm) > 0.05 {
+				t.Errorf("CPA = %.3f NM, want %.3f", cpaNm, tt.wantCpaNm)
+			}
+			if diff := got.Tcpa - tt.wantTcpa; diff > 15*time.Second || diff < -15*time.Second {
+				t.Errorf("TCPA = %s, want %s", got.Tcpa, tt.wantTcpa)
+			}
+		})
+	}
+}
+
+func TestDeadReckon(t *testing.T) {
+	at := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	m := Motion{Point: Point{0, 0}, Speed: 60, Course: 0, At: at}
+
+	// 60 knot selama 1 jam ke utara = 60 NM = 1 derajat lintang
+	got := m.DeadReckon(at.Add(time.Hour))
+	if math.Abs(got.Lat-1) > 0.01 || math.Abs(got.Lon) > 1e-9 || !got.At.Equal(at.Add(time.Hour)) {
+		t.Errorf("forward = %+v, want lat 1", got)
+	}
+
+	// waktu mundur memproyeksikan ke arah sebaliknya
+	got = m.DeadReckon(at.Add(-time.Hour))
+	if math.Abs(got.Lat+1) > 0.01 {
+		t.Errorf("backward lat = %f, want -1", got.Lat)
+	}
+
+	// kapal diam tidak berpindah
+	still := Motion{Point: Point{5, 5}, At: at}
+	if got := still.DeadReckon(at.Add(time.Hour)); got.Point != still.Point {
+		t.Errorf("stationary moved to %+v", got.Point)
+	}
+}
@@ -0,0 +1,177 @@
+package geo
+
+import "math"
+
+const (
+	// radius bumi rata-rata (m)
+	EarthRadiusMeters = 6371008.8
+	// 1 nautical mile dalam meter
+	MetersPerNauticalMile = 1852.0
+)
+
+// Point adalah koordinat WGS84 dalam derajat
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// BoundingBox dalam derajat. MinLon > MaxLon berarti kotak melewati antimeridian.
+type BoundingBox struct {
+	MinLat float64 `json:"minLat"`
+	MinLon float64 `json:"minLon"`
+	MaxLat float64 `json:"maxLat"`
+	MaxLon float64 `json:"maxLon"`
+}
+
+func (b BoundingBox) Contains(p Point) bool {
+	if p.Lat < b.MinLat || p.Lat > b.MaxLat {
+		return false
+	}
+	if b.MinLon <= b.MaxLon {
+		return p.Lon >= b.MinLon && p.Lon <= b.MaxLon
+	}
+	return p.Lon >= b.MinLon || p.Lon <= b.MaxLon
+}
+
+// ValidPosition membuang posisi "not available" AIS (lat 91, lon 181) dan nol-nol
+func ValidPosition(p Point) bool {
+	if p.Lat == 0 && p.Lon == 0 {
+		return false
+	}
+	return p.Lat >= -90 && p.Lat <= 90 && p.Lon >= -180 && p.Lon <= 180
+}
+
+func toRad(deg float64) float64 { return deg * math.Pi / 180 }
+
+func toDeg(rad float64) float64 { return rad * 180 / math.Pi }
+
+// DistanceMeters menghitung jarak great-circle (haversine)
+func DistanceMeters(a, b Point) float64 {
+	lat1, lat2 := toRad(a.Lat), toRad(b.Lat)
+	dLat := lat2 - lat1
+	dLon := toRad(b.Lon - a.Lon)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * EarthRadiusMeters * math.Asin(math.Min(1, math.Sqrt(h)))
+}
+
+// Bearing menghitung initial bearing dari a ke b (derajat, 0-360)
+func Bearing(a, b Point) float64 {
+	lat1, lat2 := toRad(a.Lat), toRad(b.Lat)
+	dLon := toRad(b.Lon - a.Lon)
+	y := math.Sin(dLon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dLon)
+	return math.Mod(toDeg(math.Atan2(y, x))+360, 360)
+}
+
+// Destination menghitung titik tujuan dari p dengan bearing (derajat) dan jarak (m)
+func Destination(p Point, bearing, meters float64) Point {
+	lat1, lon1 := toRad(p.Lat), toRad(p.Lon)
+	brng := toRad(bearing)
+	d := meters / EarthRadiusMeters
+	lat2 := math.Asin(math.Sin(lat1)*math.Cos(d) + math.Cos(lat1)*math.Sin(d)*math.Cos(brng))
+	lon2 := lon1 + math.Atan2(math.Sin(brng)*math.Sin(d)*math.Cos(lat1), math.Cos(d)-math.Sin(lat1)*math.Sin(lat2))
+	return Point{Lat: toDeg(lat2), Lon: math.Mod(toDeg(lon2)+540, 360) - 180}
+}
+
+// PointInRing menguji titik terhadap satu ring polygon (ray casting).
+// Ring berisi titik berurutan, boleh tertutup atau tidak.
+func PointInRing(p Point, ring []Point) bool {
+	inside := false
+	n := len(ring)
+	for i, j := 0, n-1; i < n; j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Lat > p.Lat) != (b.Lat > p.Lat) &&
+			p.Lon < (b.Lon-a.Lon)*(p.Lat-a.Lat)/(b.Lat-a.Lat)+a.Lon {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+// PointInPolygon — ring pertama adalah batas luar, sisanya lubang
+func PointInPolygon(p Point, rings [][]Point) bool {
+	if len(rings) == 0 || !PointInRing(p, rings[0]) {
+		return false
+	}
+	for _, hole := range rings[1:] {
+		if PointInRing(p, hole) {
+			return false
+		}
+	}
+	return true
+}
+
+// Area adalah wilayah yang bisa diuji terhadap titik (bbox, geofence, ...)
+type Area interface {
+	Contains(p Point) bool
+}
+
+var _ Area = BoundingBox{}
+
+// Interpolate menghitung titik linear antara a dan b (f 0..1) pada lat/lon,
+// memilih arah terpendek bila melewati antimeridian
+func Interpolate(a, b Point, f float64) Point {
+	dLon := b.Lon - a.Lon
+	if dLon > 180 {
+		dLon -= 360
+	} else if dLon < -180 {
+		dLon += 360
+	}
+	lon := a.Lon + dLon*f
+	if lon > 180 {
+		lon -= 360
+	} else if lon < -180 {
+		lon += 360
+	}
+	return Point{Lat: a.Lat + (b.Lat-a.Lat)*f, Lon: lon}
+}
+
+// BoundingBoxAround mengembalikan kotak yang memuat lingkaran berjari-jari meters di sekitar p
+func BoundingBoxAround(p Point, meters float64) BoundingBox {
+	d := meters / EarthRadiusMeters
+	lat := toRad(p.Lat)
+	minLat, maxLat := lat-d, lat+d
+	if maxLat >= math.Pi/2 || minLat <= -math.Pi/2 {
+		// lingkaran memuat kutub: ambil semua bujur
+		return BoundingBox{MinLat: toDeg(math.Max(minLat, -math.Pi/2)), MinLon: -180, MaxLat: toDeg(math.Min(maxLat, math.Pi/2)), MaxLon: 180}
+	}
+	dLon := toDeg(math.Asin(math.Sin(d) / math.Cos(lat)))
+	wrap := func(lon float64) float64 { return math.Mod(lon+540, 360) - 180 }
+	return BoundingBox{MinLat: toDeg(minLat), MinLon: wrap(p.Lon - dLon), MaxLat: toDeg(maxLat), MaxLon: wrap(p.Lon + dLon)}
+}
+
+// DistanceToLineMeters menghitung jarak terdekat titik ke polyline (m).
+// Dihitung pada bidang datar lokal di sekitar p, cukup untuk buffer beberapa km.
+func DistanceToLineMeters(p Point, line []Point) float64 {
+	if len(line) == 0 {
+		return math.Inf(1)
+	}
+	if len(line) == 1 {
+		return DistanceMeters(p, line[0])
+	}
+
+	cosLat := math.Cos(toRad(p.Lat))
+	project := func(q Point) (float64, float64) {
+		dLon := q.Lon - p.Lon
+		if dLon > 180 {
+			dLon -= 360
+		} else if dLon < -180 {
+			dLon += 360
+		}
+		return toRad(dLon) * cosLat * EarthRadiusMeters, toRad(q.Lat-p.Lat) * EarthRadiusMeters
+	}
+
+	best := math.Inf(1)
+	ax, ay := project(line[0])
+	for _, q := range line[1:] {
+		bx, by := project(q)
+		dx, dy := bx-ax, by-ay
+		t := 0.0
+		if l2 := dx*dx + dy*dy; l2 > 0 {
+			t = math.Max(0, math.Min(1, -(ax*dx+ay*dy)/l2))
+		}
+		best = math.Min(best, math.Hypot(ax+t*dx, ay+t*dy))
+		ax, ay = bx, by
+	}
+	return best
+}
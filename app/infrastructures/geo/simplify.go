@@ -0,0 +1,66 @@
+package geo
+
+import "math"
+
+// SimplifyIndices menjalankan Douglas-Peucker dengan toleransi dalam meter dan
+// mengembalikan indeks titik yang dipertahankan (urut). Titik pertama dan
+// terakhir selalu dipertahankan.
+func SimplifyIndices(points []Point, toleranceMeters float64) []int {
+	n := len(points)
+	if n <= 2 || toleranceMeters <= 0 {
+		indices := make([]int, n)
+		for i := range indices {
+			indices[i] = i
+		}
+		return indices
+	}
+
+	keep := make([]bool, n)
+	keep[0], keep[n-1] = true, true
+
+	// iteratif agar track panjang tidak membuat stack rekursi dalam
+	type segment struct{ first, last int }
+	stack := []segment{{0, n - 1}}
+	for len(stack) > 0 {
+		s := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		maxDistance, index := 0.0, -1
+		for i := s.first + 1; i < s.last; i++ {
+			d := crossTrackMeters(points[i], points[s.first], points[s.last])
+			if d > maxDistance {
+				maxDistance, index = d, i
+			}
+		}
+		if index >= 0 && maxDistance > toleranceMeters {
+			keep[index] = true
+			stack = append(stack, segment{s.first, index}, segment{index, s.last})
+		}
+	}
+
+	indices := make([]int, 0, n)
+	for i, k := range keep {
+		if k {
+			indices = append(indices, i)
+		}
+	}
+	return indices
+}
+
+// crossTrackMeters — jarak titik p ke segmen a-b pada proyeksi equirectangular
+// lokal (cukup akurat untuk segmen track kapal)
+func crossTrackMeters(p, a, b Point) float64 {
+	cosLat := math.Cos(toRad(a.Lat))
+	project := func(q Point) (float64, float64) {
+		return toRad(q.Lon-a.Lon) * cosLat * EarthRadiusMeters, toRad(q.Lat-a.Lat) * EarthRadiusMeters
+	}
+	px, py := project(p)
+	bx, by := project(b)
+
+	lengthSq := bx*bx + by*by
+	if lengthSq == 0 {
+		return math.Hypot(px, py)
+	}
+	t := math.Max(0, math.Min(1, (px*bx+py*by)/lengthSq))
+	return math.Hypot(px-t*bx, py-t*by)
+}
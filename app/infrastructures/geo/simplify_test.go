@@ -0,0 +1,78 @@
+package geo
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSimplifyIndices(t *testing.T) {
+	// 0.001 derajat lintang ≈ 111 m
+	tests := []struct {
+		name      string
+		points    []Point
+		tolerance float64
+		want      []int
+	}{
+		{
+			name:      "collinear points collapse to endpoints",
+			points:    []Point{{0, 0}, {0, 0.01}, {0, 0.02}, {0, 0.03}},
+			tolerance: 10,
+			want:      []int{0, 3},
+		},
+		{
+			name:      "spike above tolerance is kept",
+			points:    []Point{{0, 0}, {0, 0.01}, {0.001, 0.02}, {0, 0.03}, {0, 0.04}},
+			tolerance: 60,
+			want:      []int{0, 2, 4},
+		},
+		{
+			// setelah spike dipertahankan, titik 1 berjarak ~56 m dari segmen 0-2
+			name:      "tolerance below neighbour offset keeps neighbours",
+			points:    []Point{{0, 0}, {0, 0.01}, {0.001, 0.02}, {0, 0.03}, {0, 0.04}},
+			tolerance: 50,
+			want:      []int{0, 1, 2, 3, 4},
+		},
+		{
+			name:      "spike below tolerance is dropped",
+			points:    []Point{{0, 0}, {0, 0.01}, {0.001, 0.02}, {0, 0.03}, {0, 0.04}},
+			tolerance: 200,
+			want:      []int{0, 4},
+		},
+		{
+			name:      "zero tolerance keeps everything",
+			points:    []Point{{0, 0}, {0, 0.01}, {0, 0.02}},
+			tolerance: 0,
+			want:      []int{0, 1, 2},
+		},
+		{
+			name:      "two points",
+			points:    []Point{{0, 0}, {1, 1}},
+			tolerance: 10,
+			want:      []int{0, 1},
+		},
+		{
+			name:      "empty",
+			points:    nil,
+			tolerance: 10,
+			want:      []int{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SimplifyIndices(tt.points, tt.tolerance); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SimplifyIndices = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCrossTrackMetersBeyondSegment(t *testing.T) {
+	// titik di luar ujung segmen diukur ke ujung terdekat, bukan ke garis tak hingga
+	a, b := Point{0, 0}, Point{0, 0.01}
+	p := Point{0, 0.02}
+	got := crossTrackMeters(p, a, b)
+	want := DistanceMeters(p, b)
+	if diff := got - want; diff > 1 || diff < -1 {
+		t.Errorf("crossTrackMeters = %.1f, want %.1f", got, want)
+	}
+}
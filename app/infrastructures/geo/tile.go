@@ -0,0 +1,111 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+)
+
+// Tile adalah sel grid derajat berukuran 360/2^Z. Dipakai sebagai kunci cache
+// (bukan tile web mercator).
+type Tile struct {
+	Z int
+	X int
+	Y int
+}
+
+func (t Tile) Size() float64 {
+	return 360 / math.Pow(2, float64(t.Z))
+}
+
+func (t Tile) BoundingBox() BoundingBox {
+	size := t.Size()
+	return BoundingBox{
+		MinLat: math.Max(-90, float64(t.Y)*size-90),
+		MinLon: float64(t.X)*size - 180,
+		MaxLat: math.Min(90, float64(t.Y+1)*size-90),
+		MaxLon: math.Min(180, float64(t.X+1)*size-180),
+	}
+}
+
+func (t Tile) String() string {
+	return fmt.Sprintf("%d/%d/%d", t.Z, t.X, t.Y)
+}
+
+// maksimum zoom grid (~0.09 derajat per tile)
+const maxTileZoom = 12
+
+// TilesFor memilih zoom terbesar sehingga bbox tertutup paling banyak
+// maxTiles tile, lalu mengembalikan tile-tile tersebut
+func TilesFor(b BoundingBox, maxTiles int) []Tile {
+	return TilesUpTo(b, maxTiles, maxTileZoom)
+}
+
+// TilesUpTo sama dengan TilesFor dengan batas zoom grid maxZoom
+func TilesUpTo(b BoundingBox, maxTiles int, maxZoom int) []Tile {
+	boxes := []BoundingBox{b}
+	if b.MinLon > b.MaxLon {
+		// melewati antimeridian: pecah jadi dua
+		boxes = []BoundingBox{
+			{MinLat: b.MinLat, MinLon: b.MinLon, MaxLat: b.MaxLat, MaxLon: 180},
+			{MinLat: b.MinLat, MinLon: -180, MaxLat: b.MaxLat, MaxLon: b.MaxLon},
+		}
+	}
+
+	var tiles []Tile
+	if maxZoom > maxTileZoom {
+		maxZoom = maxTileZoom
+	}
+	for z := maxZoom; z >= 0; z-- {
+		tiles = tiles[:0]
+		// pada zoom rendah kedua potongan antimeridian bisa jatuh di tile yang sama
+		seen := make(map[Tile]bool)
+		for _, box := range boxes {
+			for _, tile := range tilesAt(box, z) {
+				if !seen[tile] {
+					seen[tile] = true
+					tiles = append(tiles, tile)
+				}
+			}
+		}
+		if len(tiles) <= maxTiles {
+			break
+		}
+	}
+	return tiles
+}
+
+// TileAt mengembalikan tile yang memuat titik p pada zoom z
+func TileAt(p Point, z int) Tile {
+	n := int(math.Pow(2, float64(z)))
+	size := 360 / float64(n)
+	// sumbu lat hanya 180 derajat: n/2 baris
+	rows := int(math.Ceil(float64(n) / 2))
+	return Tile{
+		Z: z,
+		X: clampIndex(int(math.Floor((p.Lon+180)/size)), n-1),
+		Y: clampIndex(int(math.Floor((p.Lat+90)/size)), rows-1),
+	}
+}
+
+func tilesAt(b BoundingBox, z int) []Tile {
+	min := TileAt(Point{Lat: b.MinLat, Lon: b.MinLon}, z)
+	max := TileAt(Point{Lat: b.MaxLat, Lon: b.MaxLon}, z)
+
+	tiles := make([]Tile, 0, (max.X-min.X+1)*(max.Y-min.Y+1))
+	for x := min.X; x <= max.X; x++ {
+		for y := min.Y; y <= max.Y; y++ {
+			tiles = append(tiles, Tile{Z: z, X: x, Y: y})
+		}
+	}
+	return tiles
+}
+
+func clampIndex(v, max int) int {
+	if v < 0 {
+		return 0
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
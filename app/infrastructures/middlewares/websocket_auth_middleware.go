@@ -0,0 +1,43 @@
+package middlewares
+
+import (
+	"context"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+	"gorm.io/gorm"
+)
+
+// WebsocketInitFunc mengautentikasi koneksi subscription. Browser tidak bisa
+// mengirim header Authorization saat upgrade websocket, jadi token diambil dari
+// payload connection_init: {"Authorization": "Bearer <token>"}.
+func WebsocketInitFunc(db *gorm.DB) transport.WebsocketInitFunc {
+	return func(ctx context.Context, initPayload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+		authHeader := initPayload.Authorization()
+		if authHeader == "" {
+			// Bisa jadi sudah diautentikasi lewat header HTTP oleh AuthMiddleware
+			return ctx, &initPayload, nil
+		}
+
+		parts := strings.Split(authHeader, " ")
+		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" || parts[1] == "" {
+			return ctx, nil, gqlerror.Errorf("Authorization harus berformat 'Bearer <token>'")
+		}
+
+		userID, err := validateAndGetUserID(parts[1])
+		if err != nil {
+			return ctx, nil, err
+		}
+
+		user, err := getUserByID(db, userID)
+		if err != nil {
+			return ctx, nil, err
+		}
+		if user == nil {
+			return ctx, nil, gqlerror.Errorf("User %d tidak ditemukan", userID)
+		}
+
+		return context.WithValue(ctx, userCtxKey, user), &initPayload, nil
+	}
+}
@@ -0,0 +1,15 @@
+package pkg
+
+import "sync/atomic"
+
+// postgisEnabled diisi saat koneksi postgres dibuka. Repository memakai query
+// spasial (ST_DWithin/ST_Contains) bila aktif dan perhitungan lama bila tidak.
+var postgisEnabled atomic.Bool
+
+func SetPostGIS(enabled bool) {
+	postgisEnabled.Store(enabled)
+}
+
+func PostGIS() bool {
+	return postgisEnabled.Load()
+}
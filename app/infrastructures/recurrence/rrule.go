@@ -0,0 +1,241 @@
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency RRULE yang didukung
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// MaxCount membatasi COUNT agar ekspansi seri tetap murah
+const MaxCount = 10000
+
+var weekdayCodes = map[string]time.Weekday{
+	"SU": time.Sunday,
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+}
+
+var byDayPattern = regexp.MustCompile(`^([+-]?\d{1,2})?(SU|MO|TU|WE|TH|FR|SA)$`)
+
+// WeekdayNum adalah satu nilai BYDAY, mis. MO, 2TU atau -1FR.
+// N = 0 berarti setiap hari tersebut dalam periode.
+type WeekdayNum struct {
+	N   int
+	Day time.Weekday
+}
+
+// Rule adalah subset RRULE iCalendar (RFC 5545): FREQ DAILY/WEEKLY/MONTHLY/YEARLY
+// dengan INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY, BYMONTH dan WKST.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	Count      int
+	ByDay      []WeekdayNum
+	ByMonthDay []int
+	ByMonth    []time.Month
+	WeekStart  time.Weekday
+
+	// UNTIL disimpan mentah karena bentuk tanpa "Z" bergantung pada timezone seri
+	until string
+}
+
+// Parse membaca RRULE seperti "FREQ=WEEKLY;BYDAY=SA,SU". Awalan "RRULE:" boleh ada.
+func Parse(value string) (*Rule, error) {
+	value = strings.ToUpper(strings.TrimSpace(value))
+	value = strings.TrimPrefix(value, "RRULE:")
+	if value == "" {
+		return nil, errors.New("RRULE kosong")
+	}
+
+	rule := &Rule{Interval: 1, WeekStart: time.Monday}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(value, ";") {
+		if part == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		if !ok || val == "" {
+			return nil, fmt.Errorf("bagian RRULE %q tidak valid", part)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("%s muncul lebih dari sekali", key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			rule.Freq = Frequency(val)
+			switch rule.Freq {
+			case Daily, Weekly, Monthly, Yearly:
+			default:
+				err = fmt.Errorf("FREQ %s tidak didukung, gunakan DAILY, WEEKLY, MONTHLY atau YEARLY", val)
+			}
+		case "INTERVAL":
+			rule.Interval, err = parseRange(key, val, 1, 1000)
+		case "COUNT":
+			rule.Count, err = parseRange(key, val, 1, MaxCount)
+		case "UNTIL":
+			if _, err = parseUntil(val, time.UTC); err == nil {
+				rule.until = val
+			}
+		case "BYDAY":
+			for _, item := range strings.Split(val, ",") {
+				match := byDayPattern.FindStringSubmatch(item)
+				if match == nil {
+					return nil, fmt.Errorf("BYDAY %q tidak valid", item)
+				}
+				day := WeekdayNum{Day: weekdayCodes[match[2]]}
+				if match[1] != "" {
+					day.N, _ = strconv.Atoi(match[1])
+					if day.N == 0 || day.N < -53 || day.N > 53 {
+						return nil, fmt.Errorf("BYDAY %q tidak valid", item)
+					}
+				}
+				rule.ByDay = append(rule.ByDay, day)
+			}
+		case "BYMONTHDAY":
+			for _, item := range strings.Split(val, ",") {
+				day, err := parseRange(key, item, -31, 31)
+				if err != nil {
+					return nil, err
+				}
+				if day == 0 {
+					return nil, errors.New("BYMONTHDAY tidak boleh 0")
+				}
+				rule.ByMonthDay = append(rule.ByMonthDay, day)
+			}
+		case "BYMONTH":
+			for _, item := range strings.Split(val, ",") {
+				month, err := parseRange(key, item, 1, 12)
+				if err != nil {
+					return nil, err
+				}
+				rule.ByMonth = append(rule.ByMonth, time.Month(month))
+			}
+		case "WKST":
+			day, ok := weekdayCodes[val]
+			if !ok {
+				err = fmt.Errorf("WKST %q tidak valid", val)
+			}
+			rule.WeekStart = day
+		default:
+			err = fmt.Errorf("bagian RRULE %s tidak didukung", key)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if rule.Freq == "" {
+		return nil, errors.New("RRULE wajib memiliki FREQ")
+	}
+	if rule.Count > 0 && rule.until != "" {
+		return nil, errors.New("COUNT dan UNTIL tidak boleh dipakai bersamaan")
+	}
+	if rule.Freq == Weekly && len(rule.ByMonthDay) > 0 {
+		return nil, errors.New("BYMONTHDAY tidak boleh dipakai dengan FREQ=WEEKLY")
+	}
+	if rule.Freq == Daily || rule.Freq == Weekly {
+		for _, day := range rule.ByDay {
+			if day.N != 0 {
+				return nil, errors.New("BYDAY dengan urutan hanya untuk FREQ=MONTHLY atau YEARLY")
+			}
+		}
+	}
+	sort.Slice(rule.ByMonth, func(i, j int) bool { return rule.ByMonth[i] < rule.ByMonth[j] })
+
+	return rule, nil
+}
+
+// String menulis ulang rule dalam bentuk kanonik (tanpa awalan "RRULE:")
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if r.until != "" {
+		parts = append(parts, "UNTIL="+r.until)
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = strings.ToUpper(day.Day.String()[:2])
+			if day.N != 0 {
+				days[i] = strconv.Itoa(day.N) + days[i]
+			}
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, day := range r.ByMonthDay {
+			days[i] = strconv.Itoa(day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonth) > 0 {
+		months := make([]string, len(r.ByMonth))
+		for i, month := range r.ByMonth {
+			months[i] = strconv.Itoa(int(month))
+		}
+		parts = append(parts, "BYMONTH="+strings.Join(months, ","))
+	}
+	if r.WeekStart != time.Monday {
+		parts = append(parts, "WKST="+strings.ToUpper(r.WeekStart.String()[:2]))
+	}
+	return strings.Join(parts, ";")
+}
+
+func parseRange(key, value string, min, max int) (int, error) {
+	n, err := strconv.Atoi(strings.TrimPrefix(value, "+"))
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("%s %q harus bilangan bulat %d..%d", key, value, min, max)
+	}
+	return n, nil
+}
+
+// parseUntil menerima UNTIL dalam bentuk 20250131T235959Z (UTC),
+// 20250131T235959 (waktu lokal seri) atau 20250131 (sampai akhir hari itu)
+func parseUntil(value string, location *time.Location) (time.Time, error) {
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		return t, untilError(value, err)
+	}
+	if t, err := time.ParseInLocation("20060102T150405", value, location); err == nil {
+		return t, nil
+	}
+	t, err := time.ParseInLocation("20060102", value, location)
+	if err != nil {
+		return time.Time{}, untilError(value, err)
+	}
+	return t.AddDate(0, 0, 1).Add(-time.Second), nil
+}
+
+func untilError(value string, err error) error {
+	if err != nil {
+		return fmt.Errorf("UNTIL %q tidak valid, gunakan YYYYMMDD atau YYYYMMDDTHHMMSSZ", value)
+	}
+	return nil
+}
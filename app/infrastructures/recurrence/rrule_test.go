@@ -0,0 +1,84 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"FREQ=DAILY", "FREQ=DAILY"},
+		{"RRULE:freq=weekly;byday=sa,su", "FREQ=WEEKLY;BYDAY=SA,SU"},
+		{"FREQ=MONTHLY;INTERVAL=1;BYDAY=-1SU", "FREQ=MONTHLY;BYDAY=-1SU"},
+		{"FREQ=MONTHLY;INTERVAL=2;COUNT=10;BYDAY=1SU,-1SU", "FREQ=MONTHLY;INTERVAL=2;COUNT=10;BYDAY=1SU,-1SU"},
+		{"FREQ=YEARLY;BYMONTH=10,3;BYDAY=+2SU", "FREQ=YEARLY;BYDAY=2SU;BYMONTH=3,10"},
+		{"FREQ=MONTHLY;BYMONTHDAY=31,-1", "FREQ=MONTHLY;BYMONTHDAY=31,-1"},
+		{"FREQ=WEEKLY;WKST=SU;UNTIL=20250131T235959Z", "FREQ=WEEKLY;UNTIL=20250131T235959Z;WKST=SU"},
+		{"FREQ=DAILY;UNTIL=20250131;", "FREQ=DAILY;UNTIL=20250131"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			rule, err := Parse(tt.value)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			if got := rule.String(); got != tt.want {
+				t.Errorf("String() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseByDay(t *testing.T) {
+	rule, err := Parse("FREQ=MONTHLY;BYDAY=MO,2TU,-1SU")
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := []WeekdayNum{{0, time.Monday}, {2, time.Tuesday}, {-1, time.Sunday}}
+	if len(rule.ByDay) != len(want) {
+		t.Fatalf("ByDay = %v, want %v", rule.ByDay, want)
+	}
+	for i := range want {
+		if rule.ByDay[i] != want[i] {
+			t.Errorf("ByDay[%d] = %v, want %v", i, rule.ByDay[i], want[i])
+		}
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []string{
+		"",
+		"RRULE:",
+		"BYDAY=MO",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=10001",
+		"FREQ=DAILY;COUNT=2;UNTIL=20250131",
+		"FREQ=DAILY;UNTIL=2025-01-31",
+		"FREQ=MONTHLY;BYDAY=0SU",
+		"FREQ=MONTHLY;BYDAY=54SU",
+		"FREQ=MONTHLY;BYDAY=SUN",
+		"FREQ=MONTHLY;BYMONTHDAY=0",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=YEARLY;BYMONTH=13",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=DAILY;BYDAY=-1SU",
+		"FREQ=DAILY;WKST=XX",
+		"FREQ=DAILY;BYHOUR=9",
+		"FREQ=DAILY;COUNT",
+	}
+
+	for _, value := range tests {
+		t.Run(value, func(t *testing.T) {
+			if rule, err := Parse(value); err == nil {
+				t.Errorf("Parse(%q) = %q, want error", value, rule.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package recurrence
+
+import (
+	"errors"
+	"time"
+)
+
+// maxEmptyPeriods menghentikan ekspansi rule yang jarang atau tidak pernah cocok
+// (mis. BYMONTH=2;BYMONTHDAY=30); cukup untuk 29 Februari pada FREQ=DAILY
+const maxEmptyPeriods = 4000
+
+// Occurrence adalah satu kejadian seri
+type Occurrence struct {
+	Start time.Time
+	End   time.Time
+}
+
+// Series adalah rule yang dijangkarkan ke DTSTART. Setiap kejadian mulai pada
+// jam dinding DTSTART di timezone seri (tetap benar melewati pergantian DST)
+// dan berlangsung selama duration.
+type Series struct {
+	rule     *Rule
+	start    time.Time
+	duration time.Duration
+	until    time.Time
+}
+
+// NewSeries membuat seri dari rule, DTSTART dan durasi tiap kejadian
+func NewSeries(rule *Rule, start time.Time, duration time.Duration, location *time.Location) (*Series, error) {
+	if duration < 0 {
+		return nil, errors.New("durasi kejadian tidak boleh negatif")
+	}
+	series := &Series{rule: rule, start: start.In(location), duration: duration}
+	if rule.until != "" {
+		until, err := parseUntil(rule.until, location)
+		if err != nil {
+			return nil, err
+		}
+		if until.Before(series.start) {
+			return nil, errors.New("UNTIL harus setelah waktu mulai")
+		}
+		series.until = until
+	}
+	if _, ok := series.First(); !ok {
+		return nil, errors.New("RRULE tidak menghasilkan kejadian apa pun")
+	}
+	return series, nil
+}
+
+// First mengembalikan kejadian pertama seri
+func (s *Series) First() (Occurrence, bool) {
+	var first Occurrence
+	found := false
+	s.each(time.Time{}, func(start time.Time) bool {
+		first, found = s.occurrence(start), true
+		return false
+	})
+	return first, found
+}
+
+// Last mengembalikan kejadian terakhir; false bila seri tidak berujung
+func (s *Series) Last() (Occurrence, bool) {
+	if s.rule.Count == 0 && s.until.IsZero() {
+		return Occurrence{}, false
+	}
+
+	var last Occurrence
+	found := false
+	collect := func(start time.Time) bool {
+		last, found = s.occurrence(start), true
+		return true
+	}
+	if !s.until.IsZero() {
+		// mulai dekat UNTIL; mundur ke awal seri bila periode terakhir kosong
+		s.each(s.until.AddDate(-1, 0, 0), collect)
+	}
+	if !found {
+		s.each(time.Time{}, collect)
+	}
+	return last, found
+}
+
+// OccurrenceAt mengembalikan kejadian dengan start <= at <= end, bila ada
+func (s *Series) OccurrenceAt(at time.Time) (Occurrence, bool) {
+	var current Occurrence
+	found := false
+	s.each(at.Add(-s.duration), func(start time.Time) bool {
+		if start.After(at) {
+			return false
+		}
+		if !at.After(start.Add(s.duration)) {
+			current, found = s.occurrence(start), true
+			return false
+		}
+		return true
+	})
+	return current, found
+}
+
+// Occurrences mengembalikan maksimal limit kejadian yang belum berakhir pada from
+func (s *Series) Occurrences(from time.Time, limit int) []Occurrence {
+	occurrences := []Occurrence{}
+	if limit <= 0 {
+		return occurrences
+	}
+	s.each(from.Add(-s.duration), func(start time.Time) bool {
+		occurrence := s.occurrence(start)
+		if !occurrence.End.Before(from) {
+			occurrences = append(occurrences, occurrence)
+		}
+		return len(occurrences) < limit
+	})
+	return occurrences
+}
+
+func (s *Series) occurrence(start time.Time) Occurrence {
+	return Occurrence{Start: start, End: start.Add(s.duration)}
+}
+
+// each memanggil fn untuk setiap waktu mulai kejadian secara berurutan sampai
+// fn mengembalikan false atau seri habis. Tanpa COUNT, periode sebelum from
+// dilompati karena tidak perlu dihitung.
+func (s *Series) each(from time.Time, fn func(start time.Time) bool) {
+	period := 0
+	if s.rule.Count == 0 && from.After(s.start) {
+		period = s.periodsBetween(s.start, from.In(s.start.Location())) / s.rule.Interval
+	}
+
+	count, empty := 0, 0
+	for ; empty <= maxEmptyPeriods; period++ {
+		first, starts := s.expand(period)
+		if !s.until.IsZero() && first.After(s.until) {
+			return
+		}
+		if len(starts) == 0 {
+			empty++
+			continue
+		}
+		empty = 0
+
+		for _, start := range starts {
+			if start.Before(s.start) {
+				continue
+			}
+			if !s.until.IsZero() && start.After(s.until) {
+				return
+			}
+			count++
+			if !fn(start) {
+				return
+			}
+			if s.rule.Count > 0 && count >= s.rule.Count {
+				return
+			}
+		}
+	}
+}
+
+// periodsBetween menghitung jumlah periode FREQ (hari/minggu/bulan/tahun) dari a ke b
+func (s *Series) periodsBetween(a, b time.Time) int {
+	switch s.rule.Freq {
+	case Daily:
+		return days(dateOf(a), dateOf(b))
+	case Weekly:
+		return days(weekOf(dateOf(a), s.rule.WeekStart), weekOf(dateOf(b), s.rule.WeekStart)) / 7
+	case Monthly:
+		return (b.Year()-a.Year())*12 + int(b.Month()-a.Month())
+	default:
+		return b.Year() - a.Year()
+	}
+}
+
+// expand mengembalikan awal periode ke-n (dalam satuan INTERVAL) beserta waktu
+// mulai kandidat kejadian di periode itu, terurut
+func (s *Series) expand(n int) (time.Time, []time.Time) {
+	step := n * s.rule.Interval
+	startDate := dateOf(s.start)
+
+	type span struct{ first, last time.Time }
+	var spans []span
+	switch s.rule.Freq {
+	case Daily:
+		day := startDate.AddDate(0, 0, step)
+		spans = []span{{day, day}}
+	case Weekly:
+		week := weekOf(startDate, s.rule.WeekStart).AddDate(0, 0, 7*step)
+		spans = []span{{week, week.AddDate(0, 0, 6)}}
+	case Monthly:
+		month := time.Date(startDate.Year(), startDate.Month()+time.Month(step), 1, 0, 0, 0, 0, time.UTC)
+		spans = []span{{month, month.AddDate(0, 1, -1)}}
+	case Yearly:
+		year := startDate.Year() + step
+		if len(s.rule.ByMonth) == 0 {
+			spans = []span{{time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC)}}
+			break
+		}
+		// dengan BYMONTH, urutan BYDAY (mis. 1SU) relatif terhadap bulan
+		for _, m := range s.rule.ByMonth {
+			month := time.Date(year, m, 1, 0, 0, 0, 0, time.UTC)
+			spans = append(spans, span{month, month.AddDate(0, 1, -1)})
+		}
+	}
+
+	location := s.start.Location()
+	hour, minute, second := s.start.Clock()
+	first := time.Date(spans[0].first.Year(), spans[0].first.Month(), spans[0].first.Day(), 0, 0, 0, 0, location)
+
+	var starts []time.Time
+	for _, sp := range spans {
+		for day := sp.first; !day.After(sp.last); day = day.AddDate(0, 0, 1) {
+			if s.matches(day, sp.first, sp.last) {
+				starts = append(starts, time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, 0, location))
+			}
+		}
+	}
+	return first, starts
+}
+
+// matches memeriksa tanggal day (di dalam rentang periode first..last) terhadap
+// BYMONTH, BYMONTHDAY dan BYDAY; tanpa BYMONTHDAY/BYDAY dipakai hari dari DTSTART
+func (s *Series) matches(day, first, last time.Time) bool {
+	rule := s.rule
+	if len(rule.ByMonth) > 0 && !containsMonth(rule.ByMonth, day.Month()) {
+		return false
+	}
+
+	if len(rule.ByMonthDay) > 0 {
+		daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+		ok := false
+		for _, d := range rule.ByMonthDay {
+			if d == day.Day() || (d < 0 && daysInMonth+d+1 == day.Day()) {
+				ok = true
+				break
+			}
+		}
+		if !ok {
+			return false
+		}
+	}
+
+	if len(rule.ByDay) > 0 {
+		ok := false
+		for _, d := range rule.ByDay {
+			if d.Day != day.Weekday() {
+				continue
+			}
+			switch {
+			case d.N == 0:
+				ok = true
+			case d.N > 0:
+				ok = days(first, day)/7+1 == d.N
+			default:
+				ok = days(day, last)/7+1 == -d.N
+			}
+			if ok {
+				break
+			}
+		}
+		return ok
+	}
+	if len(rule.ByMonthDay) > 0 {
+		return true
+	}
+
+	switch rule.Freq {
+	case Weekly:
+		return day.Weekday() == s.start.Weekday()
+	case Monthly:
+		return day.Day() == s.start.Day()
+	case Yearly:
+		return day.Day() == s.start.Day() && (len(rule.ByMonth) > 0 || day.Month() == s.start.Month())
+	}
+	return true
+}
+
+// dateOf mengembalikan tanggal kalender t sebagai tengah malam UTC agar
+// aritmetika hari tidak terpengaruh DST
+func dateOf(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func weekOf(date time.Time, weekStart time.Weekday) time.Time {
+	offset := (int(date.Weekday()) - int(weekStart) + 7) % 7
+	return date.AddDate(0, 0, -offset)
+}
+
+func days(a, b time.Time) int {
+	return int(b.Sub(a).Hours() / 24)
+}
+
+func containsMonth(months []time.Month, month time.Month) bool {
+	for _, m := range months {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
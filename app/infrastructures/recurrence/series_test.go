@@ -0,0 +1,375 @@
+package recurrence
+
+import (
+	"testing"
+	"time"
+)
+
+const timeLayout = "2006-01-02 15:04"
+
+func TestSeriesOccurrences(t *testing.T) {
+	newYork := mustLocation(t, "America/New_York")
+
+	tests := []struct {
+		name     string
+		rule     string
+		start    string
+		location *time.Location
+		duration time.Duration
+		from     string
+		limit    int
+		// waktu mulai kejadian dengan offset timezone seri
+		want []string
+	}{
+		{
+			name:     "RFC 5545 daily for 10 occurrences",
+			rule:     "FREQ=DAILY;COUNT=10",
+			start:    "1997-09-02 09:00",
+			location: newYork,
+			limit:    20,
+			want: []string{
+				"1997-09-02T09:00:00-04:00", "1997-09-03T09:00:00-04:00", "1997-09-04T09:00:00-04:00",
+				"1997-09-05T09:00:00-04:00", "1997-09-06T09:00:00-04:00", "1997-09-07T09:00:00-04:00",
+				"1997-09-08T09:00:00-04:00", "1997-09-09T09:00:00-04:00", "1997-09-10T09:00:00-04:00",
+				"1997-09-11T09:00:00-04:00",
+			},
+		},
+		{
+			name:     "RFC 5545 every other week on Tuesday and Thursday",
+			rule:     "FREQ=WEEKLY;INTERVAL=2;WKST=SU;BYDAY=TU,TH;COUNT=8",
+			start:    "1997-09-02 09:00",
+			location: newYork,
+			limit:    20,
+			want: []string{
+				"1997-09-02T09:00:00-04:00", "1997-09-04T09:00:00-04:00", "1997-09-16T09:00:00-04:00",
+				"1997-09-18T09:00:00-04:00", "1997-09-30T09:00:00-04:00", "1997-10-02T09:00:00-04:00",
+				"1997-10-14T09:00:00-04:00", "1997-10-16T09:00:00-04:00",
+			},
+		},
+		{
+			name:     "RFC 5545 WKST=MO",
+			rule:     "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=MO",
+			start:    "1997-08-05 09:00",
+			location: newYork,
+			limit:    20,
+			want: []string{
+				"1997-08-05T09:00:00-04:00", "1997-08-10T09:00:00-04:00",
+				"1997-08-19T09:00:00-04:00", "1997-08-24T09:00:00-04:00",
+			},
+		},
+		{
+			name:     "RFC 5545 WKST=SU",
+			rule:     "FREQ=WEEKLY;INTERVAL=2;COUNT=4;BYDAY=TU,SU;WKST=SU",
+			start:    "1997-08-05 09:00",
+			location: newYork,
+			limit:    20,
+			want: []string{
+				"1997-08-05T09:00:00-04:00", "1997-08-17T09:00:00-04:00",
+				"1997-08-19T09:00:00-04:00", "1997-08-31T09:00:00-04:00",
+			},
+		},
+		{
+			name:     "RFC 5545 monthly on the first Friday",
+			rule:     "FREQ=MONTHLY;COUNT=10;BYDAY=1FR",
+			start:    "1997-09-05 09:00",
+			location: newYork,
+			limit:    20,
+			want: []string{
+				"1997-09-05T09:00:00-04:00", "1997-10-03T09:00:00-04:00", "1997-11-07T09:00:00-05:00",
+				"1997-12-05T09:00:00-05:00", "1998-01-02T09:00:00-05:00", "1998-02-06T09:00:00-05:00",
+				"1998-03-06T09:00:00-05:00", "1998-04-03T09:00:00-05:00", "1998-05-01T09:00:00-04:00",
+				"1998-06-05T09:00:00-04:00",
+			},
+		},
+		{
+			name:     "RFC 5545 every other month on the first and last Sunday",
+			rule:     "FREQ=MONTHLY;INTERVAL=2;COUNT=10;BYDAY=1SU,-1SU",
+			start:    "1997-09-07 09:00",
+			location: newYork,
+			limit:    20,
+			want: []string{
+				"1997-09-07T09:00:00-04:00", "1997-09-28T09:00:00-04:00", "1997-11-02T09:00:00-05:00",
+				"1997-11-30T09:00:00-05:00", "1998-01-04T09:00:00-05:00", "1998-01-25T09:00:00-05:00",
+				"1998-03-01T09:00:00-05:00", "1998-03-29T09:00:00-05:00", "1998-05-03T09:00:00-04:00",
+				"1998-05-31T09:00:00-04:00",
+			},
+		},
+		{
+			name:     "monthly on the last Sunday",
+			rule:     "FREQ=MONTHLY;BYDAY=-1SU",
+			start:    "2024-01-01 08:00",
+			location: time.UTC,
+			limit:    4,
+			want: []string{
+				"2024-01-28T08:00:00Z", "2024-02-25T08:00:00Z", "2024-03-31T08:00:00Z", "2024-04-28T08:00:00Z",
+			},
+		},
+		{
+			name:     "RFC 5545 yearly on the last Sunday of October",
+			rule:     "FREQ=YEARLY;BYDAY=-1SU;BYMONTH=10",
+			start:    "1967-10-29 02:00",
+			location: time.UTC,
+			limit:    3,
+			want:     []string{"1967-10-29T02:00:00Z", "1968-10-27T02:00:00Z", "1969-10-26T02:00:00Z"},
+		},
+		{
+			name:     "31st of the month skips shorter months",
+			rule:     "FREQ=MONTHLY;COUNT=5",
+			start:    "2024-01-31 10:00",
+			location: time.UTC,
+			limit:    10,
+			want: []string{
+				"2024-01-31T10:00:00Z", "2024-03-31T10:00:00Z", "2024-05-31T10:00:00Z",
+				"2024-07-31T10:00:00Z", "2024-08-31T10:00:00Z",
+			},
+		},
+		{
+			name:     "BYMONTHDAY=31 skips shorter months",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=31",
+			start:    "2024-01-15 10:00",
+			location: time.UTC,
+			limit:    3,
+			want:     []string{"2024-01-31T10:00:00Z", "2024-03-31T10:00:00Z", "2024-05-31T10:00:00Z"},
+		},
+		{
+			name:     "BYMONTHDAY=-1 is the last day of every month",
+			rule:     "FREQ=MONTHLY;BYMONTHDAY=-1",
+			start:    "2024-01-31 10:00",
+			location: time.UTC,
+			limit:    4,
+			want:     []string{"2024-01-31T10:00:00Z", "2024-02-29T10:00:00Z", "2024-03-31T10:00:00Z", "2024-04-30T10:00:00Z"},
+		},
+		{
+			name:     "29 February only in leap years",
+			rule:     "FREQ=YEARLY;COUNT=3",
+			start:    "2024-02-29 00:00",
+			location: time.UTC,
+			limit:    5,
+			want:     []string{"2024-02-29T00:00:00Z", "2028-02-29T00:00:00Z", "2032-02-29T00:00:00Z"},
+		},
+		{
+			name:     "daily keeps wall clock across spring DST",
+			rule:     "FREQ=DAILY;COUNT=3",
+			start:    "2024-03-09 09:00",
+			location: newYork,
+			limit:    5,
+			want:     []string{"2024-03-09T09:00:00-05:00", "2024-03-10T09:00:00-04:00", "2024-03-11T09:00:00-04:00"},
+		},
+		{
+			name:     "weekly keeps wall clock across autumn DST",
+			rule:     "FREQ=WEEKLY;BYDAY=SA",
+			start:    "2024-10-26 23:30",
+			location: newYork,
+			limit:    3,
+			want:     []string{"2024-10-26T23:30:00-04:00", "2024-11-02T23:30:00-04:00", "2024-11-09T23:30:00-05:00"},
+		},
+		{
+			name:     "UNTIL date form includes the whole day",
+			rule:     "FREQ=DAILY;UNTIL=20240103",
+			start:    "2024-01-01 22:00",
+			location: newYork,
+			limit:    10,
+			want:     []string{"2024-01-01T22:00:00-05:00", "2024-01-02T22:00:00-05:00", "2024-01-03T22:00:00-05:00"},
+		},
+		{
+			name:     "UNTIL in UTC",
+			rule:     "FREQ=DAILY;UNTIL=20240103T020000Z",
+			start:    "2024-01-01 22:00",
+			location: newYork,
+			limit:    10,
+			want:     []string{"2024-01-01T22:00:00-05:00"},
+		},
+		{
+			name:     "COUNT with from after start counts from DTSTART",
+			rule:     "FREQ=DAILY;COUNT=5",
+			start:    "2024-01-01 09:00",
+			location: time.UTC,
+			duration: time.Hour,
+			from:     "2024-01-03 12:00",
+			limit:    10,
+			want:     []string{"2024-01-04T09:00:00Z", "2024-01-05T09:00:00Z"},
+		},
+		{
+			name:     "COUNT with from inside an occurrence",
+			rule:     "FREQ=DAILY;COUNT=5",
+			start:    "2024-01-01 09:00",
+			location: time.UTC,
+			duration: time.Hour,
+			from:     "2024-01-03 09:30",
+			limit:    10,
+			want:     []string{"2024-01-03T09:00:00Z", "2024-01-04T09:00:00Z", "2024-01-05T09:00:00Z"},
+		},
+		{
+			name:     "COUNT with from after the last occurrence",
+			rule:     "FREQ=WEEKLY;COUNT=2",
+			start:    "2024-01-01 09:00",
+			location: time.UTC,
+			from:     "2024-02-01 00:00",
+			limit:    10,
+			want:     []string{},
+		},
+		{
+			name:     "from skips periods without COUNT",
+			rule:     "FREQ=MONTHLY;INTERVAL=3;BYMONTHDAY=31",
+			start:    "2020-01-31 06:00",
+			location: time.UTC,
+			from:     "2024-04-01 00:00",
+			limit:    2,
+			want:     []string{"2024-07-31T06:00:00Z", "2024-10-31T06:00:00Z"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := mustSeries(t, tt.rule, tt.start, tt.location, tt.duration)
+			from := time.Time{}
+			if tt.from != "" {
+				from = mustTime(t, tt.from, tt.location)
+			}
+
+			got := series.Occurrences(from, tt.limit)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Occurrences() = %v, want %v", starts(got), tt.want)
+			}
+			for i, occurrence := range got {
+				if s := occurrence.Start.Format(time.RFC3339); s != tt.want[i] {
+					t.Errorf("Occurrences()[%d].Start = %s, want %s", i, s, tt.want[i])
+				}
+				if d := occurrence.End.Sub(occurrence.Start); d != tt.duration {
+					t.Errorf("Occurrences()[%d] duration = %s, want %s", i, d, tt.duration)
+				}
+			}
+		})
+	}
+}
+
+func TestSeriesOccurrenceAt(t *testing.T) {
+	newYork := mustLocation(t, "America/New_York")
+	// 22:00-02:00 setiap hari; malam 9→10 Maret 2024 hanya 3 jam karena DST
+	series := mustSeries(t, "FREQ=DAILY;COUNT=5", "2024-03-08 22:00", newYork, 4*time.Hour)
+
+	tests := []struct {
+		at        string
+		wantStart string
+		wantOk    bool
+	}{
+		{"2024-03-08 21:59", "", false},
+		{"2024-03-08 22:00", "2024-03-08T22:00:00-05:00", true},
+		{"2024-03-09 01:59", "2024-03-08T22:00:00-05:00", true},
+		{"2024-03-09 02:00", "2024-03-08T22:00:00-05:00", true},
+		{"2024-03-09 12:00", "", false},
+		{"2024-03-10 01:30", "2024-03-09T22:00:00-05:00", true},
+		{"2024-03-10 03:30", "", false},
+		{"2024-03-12 23:00", "2024-03-12T22:00:00-04:00", true},
+		{"2024-03-13 23:00", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.at, func(t *testing.T) {
+			occurrence, ok := series.OccurrenceAt(mustTime(t, tt.at, newYork))
+			if ok != tt.wantOk {
+				t.Fatalf("OccurrenceAt() ok = %v, want %v", ok, tt.wantOk)
+			}
+			if ok && occurrence.Start.Format(time.RFC3339) != tt.wantStart {
+				t.Errorf("OccurrenceAt().Start = %s, want %s", occurrence.Start.Format(time.RFC3339), tt.wantStart)
+			}
+		})
+	}
+}
+
+func TestSeriesFirstLast(t *testing.T) {
+	tests := []struct {
+		name      string
+		rule      string
+		start     string
+		wantFirst string
+		wantLast  string
+	}{
+		{"COUNT", "FREQ=MONTHLY;BYDAY=-1SU;COUNT=3", "2024-01-01 08:00", "2024-01-28T08:00:00Z", "2024-03-31T08:00:00Z"},
+		{"UNTIL", "FREQ=MONTHLY;BYMONTHDAY=31;UNTIL=20250630", "2024-01-01 08:00", "2024-01-31T08:00:00Z", "2025-05-31T08:00:00Z"},
+		{"UNTIL after a long empty stretch", "FREQ=YEARLY;UNTIL=20300101", "2024-02-29 08:00", "2024-02-29T08:00:00Z", "2028-02-29T08:00:00Z"},
+		{"unbounded", "FREQ=WEEKLY", "2024-01-01 08:00", "2024-01-01T08:00:00Z", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			series := mustSeries(t, tt.rule, tt.start, time.UTC, 0)
+
+			first, ok := series.First()
+			if !ok || first.Start.Format(time.RFC3339) != tt.wantFirst {
+				t.Errorf("First() = %s, %v, want %s", first.Start.Format(time.RFC3339), ok, tt.wantFirst)
+			}
+			last, ok := series.Last()
+			if ok != (tt.wantLast != "") {
+				t.Fatalf("Last() ok = %v, want %v", ok, tt.wantLast != "")
+			}
+			if ok && last.Start.Format(time.RFC3339) != tt.wantLast {
+				t.Errorf("Last() = %s, want %s", last.Start.Format(time.RFC3339), tt.wantLast)
+			}
+		})
+	}
+}
+
+func TestNewSeriesErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		rule     string
+		duration time.Duration
+	}{
+		{"negative duration", "FREQ=DAILY", -time.Hour},
+		{"UNTIL before start", "FREQ=DAILY;UNTIL=20231231", 0},
+		{"no occurrence", "FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := Parse(tt.rule)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+			start := mustTime(t, "2024-01-01 08:00", time.UTC)
+			if _, err := NewSeries(rule, start, tt.duration, time.UTC); err == nil {
+				t.Errorf("NewSeries() error = nil, want error")
+			}
+		})
+	}
+}
+
+func mustSeries(t *testing.T, value, start string, location *time.Location, duration time.Duration) *Series {
+	t.Helper()
+	rule, err := Parse(value)
+	if err != nil {
+		t.Fatalf("Parse(%q) error = %v", value, err)
+	}
+	series, err := NewSeries(rule, mustTime(t, start, location), duration, location)
+	if err != nil {
+		t.Fatalf("NewSeries(%q) error = %v", value, err)
+	}
+	return series
+}
+
+func mustTime(t *testing.T, value string, location *time.Location) time.Time {
+	t.Helper()
+	at, err := time.ParseInLocation(timeLayout, value, location)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return at
+}
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("timezone %s tidak tersedia: %v", name, err)
+	}
+	return location
+}
+
+func starts(occurrences []Occurrence) []string {
+	result := make([]string, len(occurrences))
+	for i, occurrence := range occurrences {
+		result[i] = occurrence.Start.Format(time.RFC3339)
+	}
+	return result
+}
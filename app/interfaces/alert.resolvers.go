@@ -0,0 +1,105 @@
+package interfaces
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+
+	"github.com/khoirulhasin/untirta_api/app/generated"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/helpers"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/pkg"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// AcknowledgeAlert is the resolver for the AcknowledgeAlert field.
+func (r *mutationResolver) AcknowledgeAlert(ctx context.Context, id int) (*models.Alert, error) {
+	token, err := helpers.GetToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := helpers.GetUserID(token.(string))
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := r.AlertRepository.AcknowledgeAlert(ctx, int32(id), userID)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return response, nil
+}
+
+// ResolveAlert is the resolver for the ResolveAlert field.
+func (r *mutationResolver) ResolveAlert(ctx context.Context, id int) (*models.Alert, error) {
+	token, err := helpers.GetToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := helpers.GetUserID(token.(string))
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := r.AlertRepository.ResolveAlert(ctx, int32(id), userID)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return response, nil
+}
+
+// GetOneAlert is the resolver for the GetOneAlert field.
+func (r *queryResolver) GetOneAlert(ctx context.Context, id int) (*models.Alert, error) {
+	response, err := r.AlertRepository.GetAlertByID(ctx, int32(id))
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return response, nil
+}
+
+// PageAlert is the resolver for the PageAlert field.
+func (r *queryResolver) PageAlert(ctx context.Context, pageInput *models.PageInput) (*models.Pagination, error) {
+	limit, offset, sortField, sortOrder, search, filters := pkg.PageInputIsNil(pageInput)
+
+	var mappedFilters []*models.Filter
+	for _, f := range filters {
+		mappedFilters = append(mappedFilters, &models.Filter{
+			Key:      f.Key,
+			Operator: f.Operator,
+			Value:    f.Value,
+		})
+	}
+
+	pagination := models.Pagination{
+		Limit:     &limit,
+		Offset:    &offset,
+		SortField: &sortField,
+		SortOrder: &sortOrder,
+		Search:    &search,
+		Filters:   mappedFilters,
+	}
+
+	response, err := r.AlertRepository.PageAlert(ctx, pagination)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return &response, nil
+}
+
+// Mutation returns generated.MutationResolver implementation.
+func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
+
+// Query returns generated.QueryResolver implementation.
+func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
+
+type mutationResolver struct{ *Resolver }
+type queryResolver struct{ *Resolver }
@@ -0,0 +1,74 @@
+package interfaces
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/domains/anomalies"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// DetectAisAnomalies is the resolver for the DetectAisAnomalies field.
+func (r *mutationResolver) DetectAisAnomalies(ctx context.Context, durationTimeInput models.DurationTimeInput, mmsiList []int64, options *models.AisAnomalyDetectionInput) (*models.AisAnomalyScan, error) {
+	const maxDetectionWindow = 7 * 24 * time.Hour
+
+	if durationTimeInput.End < durationTimeInput.Start {
+		return nil, gqlerror.Errorf("end harus setelah start")
+	}
+	start := time.Unix(durationTimeInput.Start, 0)
+	end := time.Unix(durationTimeInput.End, 0)
+	if end.Sub(start) > maxDetectionWindow {
+		return nil, gqlerror.Errorf("rentang waktu maksimal %d hari", int(maxDetectionWindow.Hours()/24))
+	}
+
+	opts := anomalies.NewDetectionOptions(options)
+	if err := opts.Validate(); err != nil {
+		return nil, error_handlers.ParseValidationError(ctx, err)
+	}
+
+	response, err := r.AnomalyDetector.Detect(ctx, mmsiList, start, end, opts)
+	if err != nil {
+		if errors.As(err, new(error_handlers.ValidationErrors)) {
+			return nil, error_handlers.ParseValidationError(ctx, err)
+		}
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return response, nil
+}
+
+// GetAisAnomalies is the resolver for the GetAisAnomalies field.
+func (r *queryResolver) GetAisAnomalies(ctx context.Context, durationTimeInput models.DurationTimeInput, mmsi *int64, kinds []models.AisAnomalyKind, limit *int) ([]*anomalies.AisAnomaly, error) {
+	const defaultLimit, maxLimit = 1000, 10000
+
+	if durationTimeInput.End < durationTimeInput.Start {
+		return nil, gqlerror.Errorf("end harus setelah start")
+	}
+	query := anomalies.AnomalyQuery{
+		Mmsi:  mmsi,
+		Kinds: kinds,
+		Start: time.Unix(durationTimeInput.Start, 0),
+		End:   time.Unix(durationTimeInput.End, 0),
+		Limit: defaultLimit,
+	}
+	if limit != nil {
+		if *limit < 1 || *limit > maxLimit {
+			return nil, gqlerror.Errorf("limit harus antara 1 dan %d", maxLimit)
+		}
+		query.Limit = *limit
+	}
+
+	response, err := r.AnomalyMongotory.GetAnomalies(ctx, query)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return response, nil
+}
@@ -9,7 +9,6 @@ import (
 	"fmt"
 
 	"github.com/google/uuid"
-	"github.com/khoirulhasin/untirta_api/app/generated"
 	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
 	"github.com/khoirulhasin/untirta_api/app/infrastructures/pkg"
 	"github.com/khoirulhasin/untirta_api/app/models"
@@ -159,12 +158,3 @@ func (r *queryResolver) PageCam(ctx context.Context, pageInput *models.PageInput
 
 	return &response, nil
 }
-
-// Mutation returns generated.MutationResolver implementation.
-func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
-
-// Query returns generated.QueryResolver implementation.
-func (r *Resolver) Query() generated.QueryResolver { return &queryResolver{r} }
-
-type mutationResolver struct{ *Resolver }
-type queryResolver struct{ *Resolver }
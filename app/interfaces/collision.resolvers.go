@@ -0,0 +1,37 @@
+package interfaces
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+
+	"github.com/khoirulhasin/untirta_api/app/domains/collisions"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// GetCollisionRisks is the resolver for the GetCollisionRisks field.
+func (r *queryResolver) GetCollisionRisks(ctx context.Context, shipID int, limits *models.CollisionLimitsInput) (*collisions.CollisionAssessment, error) {
+	const maxRadiusNm = 50
+
+	collisionLimits := collisions.NewLimits(limits)
+	if collisionLimits.RadiusNm <= 0 || collisionLimits.RadiusNm > maxRadiusNm {
+		return nil, gqlerror.Errorf("radiusNm harus antara 0 dan %d", maxRadiusNm)
+	}
+	if collisionLimits.CpaNm <= 0 || collisionLimits.TcpaMinutes <= 0 {
+		return nil, gqlerror.Errorf("cpaNm dan tcpaMinutes harus lebih dari 0")
+	}
+	if collisionLimits.MaxAge <= 0 {
+		return nil, gqlerror.Errorf("maxAgeSeconds harus lebih dari 0")
+	}
+
+	response, err := r.CollisionAssessor.Assess(ctx, shipID, collisionLimits)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return response, nil
+}
@@ -0,0 +1,46 @@
+package interfaces
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/domains/gaps"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// GetAisGaps is the resolver for the GetAisGaps field.
+func (r *queryResolver) GetAisGaps(ctx context.Context, durationTimeInput models.DurationTimeInput, mmsiList []int64, minGapMinutes *float64) ([]*gaps.AisGap, error) {
+	const maxAllWindow, maxListWindow = 24 * time.Hour, 31 * 24 * time.Hour
+
+	start := time.Unix(durationTimeInput.Start, 0)
+	end := time.Unix(durationTimeInput.End, 0)
+	if end.Before(start) {
+		return nil, gqlerror.Errorf("end harus setelah start")
+	}
+	if len(mmsiList) == 0 && end.Sub(start) > maxAllWindow {
+		return nil, gqlerror.Errorf("rentang waktu tanpa mmsiList maksimal 1 hari")
+	}
+	if end.Sub(start) > maxListWindow {
+		return nil, gqlerror.Errorf("rentang waktu maksimal 31 hari")
+	}
+	var minGap time.Duration
+	if minGapMinutes != nil {
+		if *minGapMinutes < 0 {
+			return nil, gqlerror.Errorf("minGapMinutes tidak boleh negatif")
+		}
+		minGap = time.Duration(*minGapMinutes * float64(time.Minute))
+	}
+
+	response, err := r.GapDetector.Gaps(ctx, mmsiList, start, end, minGap)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return response, nil
+}
@@ -28,17 +28,30 @@ func (r *mutationResolver) CreateGeofence(ctx context.Context, createGeofenceInp
 	if err != nil {
 		return nil, err
 	}
-	return r.GeofenceRepository.CreateGeofence(ctx, &createGeofenceInput, userID)
+
+	response, err := r.GeofenceRepository.CreateGeofence(ctx, &createGeofenceInput, userID)
+	if err != nil {
+		return nil, error_handlers.ParseValidationError(ctx, err)
+	}
+	return response, nil
 }
 
 // UpdateGeofence is the resolver for the UpdateGeofence field.
 func (r *mutationResolver) UpdateGeofence(ctx context.Context, id int, updateGeofenceInput models.UpdateGeofenceInput) (any, error) {
-	return r.GeofenceRepository.UpdateGeofence(ctx, int32(id), &updateGeofenceInput)
+	response, err := r.GeofenceRepository.UpdateGeofence(ctx, int32(id), &updateGeofenceInput)
+	if err != nil {
+		return nil, error_handlers.ParseValidationError(ctx, err)
+	}
+	return response, nil
 }
 
 // UpdateGeofenceByUUID is the resolver for the UpdateGeofenceByUuid field.
 func (r *mutationResolver) UpdateGeofenceByUUID(ctx context.Context, uuid uuid.UUID, updateGeofenceInput models.UpdateGeofenceInput) (any, error) {
-	return r.GeofenceRepository.UpdateGeofenceByUUID(ctx, uuid.String(), &updateGeofenceInput)
+	response, err := r.GeofenceRepository.UpdateGeofenceByUUID(ctx, uuid.String(), &updateGeofenceInput)
+	if err != nil {
+		return nil, error_handlers.ParseValidationError(ctx, err)
+	}
+	return response, nil
 }
 
 // DeleteGeofence is the resolver for the DeleteGeofence field.
@@ -0,0 +1,85 @@
+package interfaces
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/helpers"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/pkg"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// CreateGeofenceAlertRule is the resolver for the CreateGeofenceAlertRule field.
+func (r *mutationResolver) CreateGeofenceAlertRule(ctx context.Context, createGeofenceAlertRuleInput models.CreateGeofenceAlertRuleInput) (any, error) {
+	token, err := helpers.GetToken(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := helpers.GetUserID(token.(string))
+
+	if err != nil {
+		return nil, err
+	}
+	return r.GeofenceAlertRuleRepository.CreateGeofenceAlertRule(ctx, &createGeofenceAlertRuleInput, userID)
+}
+
+// UpdateGeofenceAlertRule is the resolver for the UpdateGeofenceAlertRule field.
+func (r *mutationResolver) UpdateGeofenceAlertRule(ctx context.Context, id int, updateGeofenceAlertRuleInput models.UpdateGeofenceAlertRuleInput) (any, error) {
+	return r.GeofenceAlertRuleRepository.UpdateGeofenceAlertRule(ctx, int32(id), &updateGeofenceAlertRuleInput)
+}
+
+// DeleteGeofenceAlertRule is the resolver for the DeleteGeofenceAlertRule field.
+func (r *mutationResolver) DeleteGeofenceAlertRule(ctx context.Context, id int) (any, error) {
+	err := r.GeofenceAlertRuleRepository.DeleteGeofenceAlertRule(ctx, int32(id))
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+	return nil, nil
+}
+
+// GetAllGeofenceAlertRules is the resolver for the GetAllGeofenceAlertRules field.
+func (r *queryResolver) GetAllGeofenceAlertRules(ctx context.Context, geofenceID *int) (any, error) {
+	var geofence *int32
+	if geofenceID != nil {
+		id := int32(*geofenceID)
+		geofence = &id
+	}
+	return r.GeofenceAlertRuleRepository.GetAllGeofenceAlertRules(ctx, geofence)
+}
+
+// GetOneGeofenceAlertRule is the resolver for the GetOneGeofenceAlertRule field.
+func (r *queryResolver) GetOneGeofenceAlertRule(ctx context.Context, id int) (any, error) {
+	return r.GeofenceAlertRuleRepository.GetGeofenceAlertRuleByID(ctx, int32(id))
+}
+
+// PageGeofenceAlertRule is the resolver for the PageGeofenceAlertRule field.
+func (r *queryResolver) PageGeofenceAlertRule(ctx context.Context, pageInput *models.PageInput) (any, error) {
+	limit, offset, sortField, sortOrder, search, filters := pkg.PageInputIsNil(pageInput)
+
+	var mappedFilters []*models.Filter
+	for _, f := range filters {
+		mappedFilters = append(mappedFilters, &models.Filter{
+			Key:      f.Key,
+			Operator: f.Operator,
+			Value:    f.Value,
+		})
+	}
+
+	pagination := models.Pagination{
+		Limit:     &limit,
+		Offset:    &offset,
+		SortField: &sortField,
+		SortOrder: &sortOrder,
+		Search:    &search,
+		Filters:   mappedFilters,
+	}
+
+	return r.GeofenceAlertRuleRepository.PageGeofenceAlertRule(ctx, pagination)
+}
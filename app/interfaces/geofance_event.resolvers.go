@@ -0,0 +1,58 @@
+package interfaces
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/pkg"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// PageGeofenceEvent is the resolver for the PageGeofenceEvent field.
+func (r *queryResolver) PageGeofenceEvent(ctx context.Context, pageInput *models.PageInput) (*models.Pagination, error) {
+	limit, offset, sortField, sortOrder, search, filters := pkg.PageInputIsNil(pageInput)
+
+	var mappedFilters []*models.Filter
+	for _, f := range filters {
+		mappedFilters = append(mappedFilters, &models.Filter{
+			Key:      f.Key,
+			Operator: f.Operator,
+			Value:    f.Value,
+		})
+	}
+
+	pagination := models.Pagination{
+		Limit:     &limit,
+		Offset:    &offset,
+		SortField: &sortField,
+		SortOrder: &sortOrder,
+		Search:    &search,
+		Filters:   mappedFilters,
+	}
+
+	response, err := r.GeofenceEventRepository.PageGeofenceEvent(ctx, pagination)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return &response, nil
+}
+
+// GetGeofenceEvents is the resolver for the GetGeofenceEvents field.
+func (r *queryResolver) GetGeofenceEvents(ctx context.Context, geofenceID *int, mmsi *int64, durationTimeInput models.DurationTimeInput) ([]*models.GeofenceEvent, error) {
+	if durationTimeInput.End < durationTimeInput.Start {
+		return nil, gqlerror.Errorf("end harus setelah start")
+	}
+
+	response, err := r.GeofenceEventRepository.GetGeofenceEvents(ctx, geofenceID, mmsi, durationTimeInput.Start*1000, durationTimeInput.End*1000)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return response, nil
+}
@@ -0,0 +1,84 @@
+package interfaces
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"time"
+
+	geofences "github.com/khoirulhasin/untirta_api/app/domains/geofances"
+	"github.com/khoirulhasin/untirta_api/app/domains/ships"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// GeofencesContainingPoint is the resolver for the GeofencesContainingPoint field.
+func (r *queryResolver) GeofencesContainingPoint(ctx context.Context, lat float64, lng float64) (any, error) {
+	if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return nil, gqlerror.Errorf("lat/lng di luar rentang")
+	}
+
+	response, err := r.GeofenceRepository.GetGeofencesContainingPoint(ctx, geo.Point{Lat: lat, Lon: lng})
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return response, nil
+}
+
+// VesselsInGeofence is the resolver for the VesselsInGeofence field.
+func (r *queryResolver) VesselsInGeofence(ctx context.Context, id int, at *int64, maxAge *int) ([]*ships.VesselSnapshot, error) {
+	snapshotAt := time.Now()
+	if at != nil {
+		snapshotAt = time.Unix(*at, 0)
+	}
+
+	snapshotMaxAge := 30 * time.Minute
+	if maxAge != nil {
+		if *maxAge <= 0 {
+			return nil, gqlerror.Errorf("maxAge harus lebih dari 0 detik")
+		}
+		snapshotMaxAge = time.Duration(*maxAge) * time.Second
+	}
+
+	snapshots, err := r.GeofenceLocator.VesselsInGeofence(ctx, int32(id), snapshotAt, snapshotMaxAge)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return snapshots, nil
+}
+
+// GeofenceOccupancyHistory is the resolver for the GeofenceOccupancyHistory field.
+func (r *queryResolver) GeofenceOccupancyHistory(ctx context.Context, id int, durationTimeInput models.DurationTimeInput, bucketSeconds *int) (*geofences.GeofenceOccupancy, error) {
+	const maxOccupancyWindow = 31 * 24 * time.Hour
+
+	if durationTimeInput.End <= durationTimeInput.Start {
+		return nil, gqlerror.Errorf("end harus setelah start")
+	}
+
+	start := time.Unix(durationTimeInput.Start, 0)
+	end := time.Unix(durationTimeInput.End, 0)
+	if end.Sub(start) > maxOccupancyWindow {
+		return nil, gqlerror.Errorf("rentang waktu maksimal %d hari", int(maxOccupancyWindow.Hours()/24))
+	}
+
+	bucket := time.Hour
+	if bucketSeconds != nil {
+		if *bucketSeconds <= 0 {
+			return nil, gqlerror.Errorf("bucketSeconds harus lebih dari 0")
+		}
+		bucket = time.Duration(*bucketSeconds) * time.Second
+	}
+
+	response, err := r.GeofenceLocator.OccupancyHistory(ctx, int32(id), durationTimeInput, bucket)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return response, nil
+}
@@ -0,0 +1,55 @@
+package interfaces
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/helpers"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ImportGeofences is the resolver for the ImportGeofences field.
+func (r *mutationResolver) ImportGeofences(ctx context.Context, file graphql.Upload, format *models.GeofenceFileFormat, dryRun bool, onDuplicate *models.GeofenceDuplicateStrategy) (*models.GeofenceImportResult, error) {
+	token, err := helpers.GetToken(ctx)
+
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := helpers.GetUserID(token.(string))
+
+	if err != nil {
+		return nil, err
+	}
+
+	strategy := models.GeofenceDuplicateStrategySkip
+	if onDuplicate != nil {
+		strategy = *onDuplicate
+	}
+
+	response, err := r.GeofenceTransfer.Import(ctx, file.File, file.Filename, format, dryRun, strategy, userID)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+	return response, nil
+}
+
+// ExportGeofences is the resolver for the ExportGeofences field.
+func (r *mutationResolver) ExportGeofences(ctx context.Context, format models.GeofenceFileFormat, ids []int) (*models.GeofenceExport, error) {
+	geofenceIDs := make([]int32, len(ids))
+	for i, id := range ids {
+		geofenceIDs[i] = int32(id)
+	}
+
+	response, err := r.GeofenceTransfer.Export(ctx, format, geofenceIDs)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+	return response, nil
+}
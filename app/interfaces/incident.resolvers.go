@@ -0,0 +1,154 @@
+package interfaces
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"errors"
+
+	"github.com/khoirulhasin/untirta_api/app/generated"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/helpers"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/pkg"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// UpdateIncidentStatus is the resolver for the UpdateIncidentStatus field.
+func (r *mutationResolver) UpdateIncidentStatus(ctx context.Context, id int, status models.IncidentStatus, note *string) (*models.Incident, error) {
+	token, err := helpers.GetToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := helpers.GetUserID(token.(string))
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := r.IncidentRepository.UpdateIncidentStatus(ctx, int32(id), status, note, userID)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return response, nil
+}
+
+// AssignIncident is the resolver for the AssignIncident field.
+func (r *mutationResolver) AssignIncident(ctx context.Context, id int, assigneeID *int, note *string) (*models.Incident, error) {
+	token, err := helpers.GetToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := helpers.GetUserID(token.(string))
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := r.IncidentRepository.AssignIncident(ctx, int32(id), assigneeID, note, userID)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return response, nil
+}
+
+// AddIncidentNote is the resolver for the AddIncidentNote field.
+func (r *mutationResolver) AddIncidentNote(ctx context.Context, id int, note string) (*models.IncidentEvent, error) {
+	token, err := helpers.GetToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := helpers.GetUserID(token.(string))
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := r.IncidentRepository.AddIncidentNote(ctx, int32(id), note, userID)
+	if err != nil {
+		if errors.As(err, new(error_handlers.ValidationErrors)) {
+			return nil, error_handlers.ParseValidationError(ctx, err)
+		}
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return response, nil
+}
+
+// GetOneIncident is the resolver for the GetOneIncident field.
+func (r *queryResolver) GetOneIncident(ctx context.Context, id int) (*models.Incident, error) {
+	response, err := r.IncidentRepository.GetIncidentByID(ctx, int32(id))
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return response, nil
+}
+
+// PageIncident is the resolver for the PageIncident field.
+func (r *queryResolver) PageIncident(ctx context.Context, pageInput *models.PageInput) (*models.Pagination, error) {
+	limit, offset, sortField, sortOrder, search, filters := pkg.PageInputIsNil(pageInput)
+
+	var mappedFilters []*models.Filter
+	for _, f := range filters {
+		mappedFilters = append(mappedFilters, &models.Filter{
+			Key:      f.Key,
+			Operator: f.Operator,
+			Value:    f.Value,
+		})
+	}
+
+	pagination := models.Pagination{
+		Limit:     &limit,
+		Offset:    &offset,
+		SortField: &sortField,
+		SortOrder: &sortOrder,
+		Search:    &search,
+		Filters:   mappedFilters,
+	}
+
+	response, err := r.IncidentRepository.PageIncident(ctx, pagination)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return &response, nil
+}
+
+// IncidentOpened is the resolver for the IncidentOpened field.
+func (r *subscriptionResolver) IncidentOpened(ctx context.Context) (<-chan *models.Incident, error) {
+	opened, unsubscribe := r.IncidentHub.Subscribe(16)
+	incidents := make(chan *models.Incident, 1)
+
+	go func() {
+		defer close(incidents)
+		defer unsubscribe()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case incident, ok := <-opened:
+				if !ok {
+					return
+				}
+				select {
+				case incidents <- incident:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return incidents, nil
+}
+
+// Subscription returns generated.SubscriptionResolver implementation.
+func (r *Resolver) Subscription() generated.SubscriptionResolver { return &subscriptionResolver{r} }
+
+type subscriptionResolver struct{ *Resolver }
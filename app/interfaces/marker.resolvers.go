@@ -6,9 +6,12 @@ package interfaces
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/khoirulhasin/untirta_api/app/domains/markers"
 	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
 	"github.com/khoirulhasin/untirta_api/app/infrastructures/pkg"
 	"github.com/khoirulhasin/untirta_api/app/models"
 	"github.com/vektah/gqlparser/v2/gqlerror"
@@ -24,6 +27,11 @@ func (r *mutationResolver) CreateMarker(ctx context.Context, createMarkerInput m
 		End:          createMarkerInput.Duration.End,
 		MarkerTypeID: createMarkerInput.MarkerTypeID,
 		Description:  createMarkerInput.Description,
+		Rrule:        createMarkerInput.Rrule,
+		Timezone:     createMarkerInput.Timezone,
+	}
+	if err := markers.NormalizeSchedule(marker); err != nil {
+		return nil, error_handlers.ParseValidationError(ctx, err)
 	}
 	response, err := r.MarkerRepository.CreateMarker(ctx, marker)
 
@@ -44,6 +52,11 @@ func (r *mutationResolver) UpdateMarker(ctx context.Context, id int, updateMarke
 		End:          updateMarkerInput.Duration.End,
 		MarkerTypeID: updateMarkerInput.MarkerTypeID,
 		Description:  updateMarkerInput.Description,
+		Rrule:        updateMarkerInput.Rrule,
+		Timezone:     updateMarkerInput.Timezone,
+	}
+	if err := markers.NormalizeSchedule(marker); err != nil {
+		return nil, error_handlers.ParseValidationError(ctx, err)
 	}
 
 	response, err := r.MarkerRepository.UpdateMarker(ctx, int32(id), marker)
@@ -65,6 +78,11 @@ func (r *mutationResolver) UpdateMarkerByUUID(ctx context.Context, uuid uuid.UUI
 		End:          updateMarkerInput.Duration.End,
 		MarkerTypeID: updateMarkerInput.MarkerTypeID,
 		Description:  updateMarkerInput.Description,
+		Rrule:        updateMarkerInput.Rrule,
+		Timezone:     updateMarkerInput.Timezone,
+	}
+	if err := markers.NormalizeSchedule(marker); err != nil {
+		return nil, error_handlers.ParseValidationError(ctx, err)
 	}
 
 	response, err := r.MarkerRepository.UpdateMarkerByUUID(ctx, uuid.String(), marker)
@@ -121,8 +139,14 @@ func (r *queryResolver) GetOneMarkerByUUID(ctx context.Context, uuid uuid.UUID)
 }
 
 // GetAllMarkers is the resolver for the GetAllMarkers field.
-func (r *queryResolver) GetAllMarkers(ctx context.Context) ([]any, error) {
-	markers, err := r.MarkerRepository.GetAllMarkers(ctx)
+func (r *queryResolver) GetAllMarkers(ctx context.Context, activeAt *int64) ([]any, error) {
+	var at *time.Time
+	if activeAt != nil {
+		t := time.Unix(*activeAt, 0)
+		at = &t
+	}
+
+	markers, err := r.MarkerRepository.GetAllMarkers(ctx, at)
 
 	if err != nil {
 		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
@@ -156,3 +180,75 @@ func (r *queryResolver) PageMarker(ctx context.Context, pageInput *models.PageIn
 
 	return &response, nil
 }
+
+// GetMarkerClusters is the resolver for the GetMarkerClusters field.
+func (r *queryResolver) GetMarkerClusters(ctx context.Context, bbox models.BoundingBoxInput, zoom int, activeAt *int64) ([]*geo.Cluster, error) {
+	if zoom < 0 || zoom > 22 {
+		return nil, gqlerror.Errorf("zoom harus di antara 0 dan 22")
+	}
+
+	at := time.Now()
+	if activeAt != nil {
+		at = time.Unix(*activeAt, 0)
+	}
+
+	clusters, err := r.MarkerRedistory.GetMarkerClusters(ctx, geo.BoundingBox(bbox), zoom, at)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return clusters, nil
+}
+
+// GetNearestMarkers is the resolver for the GetNearestMarkers field.
+func (r *queryResolver) GetNearestMarkers(ctx context.Context, imei *string, lat *float64, lng *float64, limit *int, markerTypeIds []int, radiusNm *float64, maxAgeMinutes *int, activeAt *int64) (*markers.NearestMarkers, error) {
+	query := markers.NearestMarkerQuery{
+		Imei:          imei,
+		Lat:           lat,
+		Lng:           lng,
+		MarkerTypeIDs: markerTypeIds,
+		RadiusNm:      radiusNm,
+	}
+	if limit != nil {
+		query.Limit = *limit
+	}
+	if maxAgeMinutes != nil {
+		if *maxAgeMinutes <= 0 {
+			return nil, gqlerror.Errorf("maxAgeMinutes harus lebih dari 0")
+		}
+		query.MaxAge = time.Duration(*maxAgeMinutes) * time.Minute
+	}
+	if activeAt != nil {
+		query.ActiveAt = time.Unix(*activeAt, 0)
+	}
+
+	response, err := r.MarkerLocator.Nearest(ctx, query)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return response, nil
+}
+
+// GetMarkerOccurrences is the resolver for the GetMarkerOccurrences field.
+func (r *queryResolver) GetMarkerOccurrences(ctx context.Context, id int, from *int64, limit *int) ([]*models.MarkerOccurrence, error) {
+	start := time.Now()
+	if from != nil {
+		start = time.Unix(*from, 0)
+	}
+
+	count := 0
+	if limit != nil {
+		if *limit <= 0 {
+			return nil, gqlerror.Errorf("limit harus lebih dari 0")
+		}
+		count = *limit
+	}
+
+	marker, err := r.MarkerRepository.GetMarkerByID(ctx, int32(id))
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return markers.Occurrences(marker, start, count), nil
+}
@@ -16,9 +16,15 @@ import (
 
 // CreateMarkerType is the resolver for the CreateMarkerType field.
 func (r *mutationResolver) CreateMarkerType(ctx context.Context, createMarkerTypeInput models.CreateMarkerTypeInput) (any, error) {
+	if createMarkerTypeInput.AlertRadiusNm != nil && *createMarkerTypeInput.AlertRadiusNm < 0 {
+		return nil, gqlerror.Errorf("alertRadiusNm tidak boleh negatif")
+	}
+
 	role := &models.MarkerType{
-		Name: createMarkerTypeInput.Name,
-		Icon: createMarkerTypeInput.Icon,
+		Name:          createMarkerTypeInput.Name,
+		Icon:          createMarkerTypeInput.Icon,
+		AlertRadiusNm: createMarkerTypeInput.AlertRadiusNm,
+		AlertSeverity: createMarkerTypeInput.AlertSeverity,
 	}
 	response, err := r.MarkerTypeRepository.CreateMarkerType(ctx, role)
 
@@ -31,9 +37,15 @@ func (r *mutationResolver) CreateMarkerType(ctx context.Context, createMarkerTyp
 
 // UpdateMarkerType is the resolver for the UpdateMarkerType field.
 func (r *mutationResolver) UpdateMarkerType(ctx context.Context, id int, updateMarkerTypeInput models.UpdateMarkerTypeInput) (any, error) {
+	if updateMarkerTypeInput.AlertRadiusNm != nil && *updateMarkerTypeInput.AlertRadiusNm < 0 {
+		return nil, gqlerror.Errorf("alertRadiusNm tidak boleh negatif")
+	}
+
 	role := &models.MarkerType{
-		Name: updateMarkerTypeInput.Name,
-		Icon: updateMarkerTypeInput.Icon,
+		Name:          updateMarkerTypeInput.Name,
+		Icon:          updateMarkerTypeInput.Icon,
+		AlertRadiusNm: updateMarkerTypeInput.AlertRadiusNm,
+		AlertSeverity: updateMarkerTypeInput.AlertSeverity,
 	}
 
 	response, err := r.MarkerTypeRepository.UpdateMarkerType(ctx, int32(id), role)
@@ -47,9 +59,15 @@ func (r *mutationResolver) UpdateMarkerType(ctx context.Context, id int, updateM
 
 // UpdateMarkerTypeByUUID is the resolver for the UpdateMarkerTypeByUuid field.
 func (r *mutationResolver) UpdateMarkerTypeByUUID(ctx context.Context, uuid uuid.UUID, updateMarkerTypeInput *models.UpdateMarkerTypeInput) (any, error) {
+	if updateMarkerTypeInput.AlertRadiusNm != nil && *updateMarkerTypeInput.AlertRadiusNm < 0 {
+		return nil, gqlerror.Errorf("alertRadiusNm tidak boleh negatif")
+	}
+
 	role := &models.MarkerType{
-		Name: updateMarkerTypeInput.Name,
-		Icon: updateMarkerTypeInput.Icon,
+		Name:          updateMarkerTypeInput.Name,
+		Icon:          updateMarkerTypeInput.Icon,
+		AlertRadiusNm: updateMarkerTypeInput.AlertRadiusNm,
+		AlertSeverity: updateMarkerTypeInput.AlertSeverity,
 	}
 
 	response, err := r.MarkerTypeRepository.UpdateMarkerTypeByUUID(ctx, uuid.String(), role)
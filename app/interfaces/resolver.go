@@ -1,20 +1,28 @@
 package interfaces
 
 import (
+	"github.com/khoirulhasin/untirta_api/app/domains/alerts"
+	"github.com/khoirulhasin/untirta_api/app/domains/anomalies"
 	"github.com/khoirulhasin/untirta_api/app/domains/cams"
+	"github.com/khoirulhasin/untirta_api/app/domains/collisions"
 	"github.com/khoirulhasin/untirta_api/app/domains/devices"
 	"github.com/khoirulhasin/untirta_api/app/domains/drivers"
 	"github.com/khoirulhasin/untirta_api/app/domains/drives"
+	"github.com/khoirulhasin/untirta_api/app/domains/gaps"
 	geofences "github.com/khoirulhasin/untirta_api/app/domains/geofances"
+	"github.com/khoirulhasin/untirta_api/app/domains/incidents"
 	"github.com/khoirulhasin/untirta_api/app/domains/marker_types"
 	"github.com/khoirulhasin/untirta_api/app/domains/markers"
 	"github.com/khoirulhasin/untirta_api/app/domains/menus"
 	"github.com/khoirulhasin/untirta_api/app/domains/menus2roles"
 	"github.com/khoirulhasin/untirta_api/app/domains/profiles"
 	"github.com/khoirulhasin/untirta_api/app/domains/roles"
+	"github.com/khoirulhasin/untirta_api/app/domains/sar"
 	"github.com/khoirulhasin/untirta_api/app/domains/ships"
 	"github.com/khoirulhasin/untirta_api/app/domains/users"
 	"github.com/khoirulhasin/untirta_api/app/domains/users2roles"
+	"github.com/khoirulhasin/untirta_api/app/domains/voyages"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/ais"
 )
 
 // This file will not be regenerated automatically.
@@ -22,20 +30,40 @@ import (
 // It serves as dependency injection for your app, add any dependencies you require here.
 
 type Resolver struct {
-	ProfileRepository    profiles.ProfileRepository
-	RoleRepository       roles.RoleRepository
-	UserRepository       users.UserRepository
-	Users2roleRepository users2roles.Users2roleRepository
-	MenuRepository       menus.MenuRepository
-	Menus2roleRepository menus2roles.Menus2roleRepository
-	DeviceRepository     devices.DeviceRepository
-	MarkerRepository     markers.MarkerRepository
-	ShipRepository       ships.ShipRepository
-	DriverRepository     drivers.DriverRepository
-	DriveRepository      drives.DriveRepository
-	CamRepository        cams.CamRepository
-	MarkerTypeRepository marker_types.MarkerTypeRepository
-	ShipMongodistory     ships.ShipMongodistory
-	ShipMongotory        ships.ShipMongotory
-	GeofenceRepository   geofences.GeofenceRepository
+	ProfileRepository           profiles.ProfileRepository
+	RoleRepository              roles.RoleRepository
+	UserRepository              users.UserRepository
+	Users2roleRepository        users2roles.Users2roleRepository
+	MenuRepository              menus.MenuRepository
+	Menus2roleRepository        menus2roles.Menus2roleRepository
+	DeviceRepository            devices.DeviceRepository
+	MarkerRepository            markers.MarkerRepository
+	MarkerRedistory             markers.MarkerRedistory
+	MarkerLocator               *markers.Locator
+	ShipRepository              ships.ShipRepository
+	DriverRepository            drivers.DriverRepository
+	DriveRepository             drives.DriveRepository
+	CamRepository               cams.CamRepository
+	MarkerTypeRepository        marker_types.MarkerTypeRepository
+	ShipMongodistory            ships.ShipMongodistory
+	ShipMongotory               ships.ShipMongotory
+	TrackExporter               *ships.TrackExporter
+	ShipAisLinker               *ships.AisLinker
+	GeofenceRepository          geofences.GeofenceRepository
+	GeofenceEventRepository     geofences.GeofenceEventRepository
+	GeofenceAlertRuleRepository geofences.GeofenceAlertRuleRepository
+	GeofenceLocator             *geofences.Locator
+	GeofenceTransfer            *geofences.Transfer
+	VoyageRepository            voyages.VoyageRepository
+	VoyageDetector              *voyages.Detector
+	AlertRepository             alerts.AlertRepository
+	IncidentRepository          incidents.IncidentRepository
+	IncidentHub                 *incidents.Hub
+	SarAssistant                *sar.Assistant
+	AnomalyMongotory            anomalies.AnomalyMongotory
+	AnomalyDetector             *anomalies.Detector
+	GapDetector                 *gaps.Detector
+	CollisionAssessor           *collisions.Assessor
+	AisIngestor                 *ais.Ingestor
+	AisListener                 *ais.Listener
 }
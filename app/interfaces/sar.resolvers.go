@@ -0,0 +1,28 @@
+package interfaces
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"errors"
+
+	"github.com/khoirulhasin/untirta_api/app/domains/sar"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// GetSarAssist is the resolver for the GetSarAssist field.
+func (r *queryResolver) GetSarAssist(ctx context.Context, input models.SarAssistInput) (*sar.SarAssist, error) {
+	response, err := r.SarAssistant.Assist(ctx, input)
+	if err != nil {
+		if errors.As(err, new(error_handlers.ValidationErrors)) {
+			return nil, error_handlers.ParseValidationError(ctx, err)
+		}
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return response, nil
+}
@@ -6,9 +6,15 @@ package interfaces
 
 import (
 	"context"
+	"errors"
+	"sort"
+	"time"
 
 	"github.com/google/uuid"
+	"github.com/khoirulhasin/untirta_api/app/domains/ships"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/ais"
 	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/geo"
 	"github.com/khoirulhasin/untirta_api/app/infrastructures/pkg"
 	"github.com/khoirulhasin/untirta_api/app/models"
 	"github.com/vektah/gqlparser/v2/gqlerror"
@@ -17,9 +23,20 @@ import (
 // CreateShip is the resolver for the CreateShip field.
 func (r *mutationResolver) CreateShip(ctx context.Context, createShipInput models.CreateShipInput) (any, error) {
 	ship := &models.Ship{
-		Name:        createShipInput.Name,
-		Number:      createShipInput.Number,
-		Description: createShipInput.Description,
+		Name:         createShipInput.Name,
+		Number:       createShipInput.Number,
+		Description:  createShipInput.Description,
+		Mmsi:         createShipInput.Mmsi,
+		Imo:          createShipInput.Imo,
+		CallSign:     createShipInput.CallSign,
+		Flag:         createShipInput.Flag,
+		VesselType:   createShipInput.VesselType,
+		Length:       createShipInput.Length,
+		Beam:         createShipInput.Beam,
+		GrossTonnage: createShipInput.GrossTonnage,
+	}
+	if err := ships.NormalizeShip(ship); err != nil {
+		return nil, error_handlers.ParseValidationError(ctx, err)
 	}
 	response, err := r.ShipRepository.CreateShip(ctx, ship)
 
@@ -33,9 +50,20 @@ func (r *mutationResolver) CreateShip(ctx context.Context, createShipInput model
 // UpdateShip is the resolver for the UpdateShip field.
 func (r *mutationResolver) UpdateShip(ctx context.Context, id int, updateShipInput models.UpdateShipInput) (any, error) {
 	ship := &models.Ship{
-		Name:        updateShipInput.Name,
-		Number:      updateShipInput.Number,
-		Description: updateShipInput.Description,
+		Name:         updateShipInput.Name,
+		Number:       updateShipInput.Number,
+		Description:  updateShipInput.Description,
+		Mmsi:         updateShipInput.Mmsi,
+		Imo:          updateShipInput.Imo,
+		CallSign:     updateShipInput.CallSign,
+		Flag:         updateShipInput.Flag,
+		VesselType:   updateShipInput.VesselType,
+		Length:       updateShipInput.Length,
+		Beam:         updateShipInput.Beam,
+		GrossTonnage: updateShipInput.GrossTonnage,
+	}
+	if err := ships.NormalizeShip(ship); err != nil {
+		return nil, error_handlers.ParseValidationError(ctx, err)
 	}
 
 	response, err := r.ShipRepository.UpdateShip(ctx, int32(id), ship)
@@ -50,9 +78,20 @@ func (r *mutationResolver) UpdateShip(ctx context.Context, id int, updateShipInp
 // UpdateShipByUUID is the resolver for the UpdateShipByUuid field.
 func (r *mutationResolver) UpdateShipByUUID(ctx context.Context, uuid uuid.UUID, updateShipInput models.UpdateShipInput) (any, error) {
 	ship := &models.Ship{
-		Name:        updateShipInput.Name,
-		Number:      updateShipInput.Number,
-		Description: updateShipInput.Description,
+		Name:         updateShipInput.Name,
+		Number:       updateShipInput.Number,
+		Description:  updateShipInput.Description,
+		Mmsi:         updateShipInput.Mmsi,
+		Imo:          updateShipInput.Imo,
+		CallSign:     updateShipInput.CallSign,
+		Flag:         updateShipInput.Flag,
+		VesselType:   updateShipInput.VesselType,
+		Length:       updateShipInput.Length,
+		Beam:         updateShipInput.Beam,
+		GrossTonnage: updateShipInput.GrossTonnage,
+	}
+	if err := ships.NormalizeShip(ship); err != nil {
+		return nil, error_handlers.ParseValidationError(ctx, err)
 	}
 
 	response, err := r.ShipRepository.UpdateShipByUUID(ctx, uuid.String(), ship)
@@ -86,6 +125,32 @@ func (r *mutationResolver) DeleteShipByUUID(ctx context.Context, uuid uuid.UUID)
 	return nil, nil
 }
 
+// AutoFillShipFromAis is the resolver for the AutoFillShipFromAis field.
+func (r *mutationResolver) AutoFillShipFromAis(ctx context.Context, id int, overwrite *bool) (*models.Ship, error) {
+	response, err := r.ShipAisLinker.AutoFill(ctx, int32(id), overwrite != nil && *overwrite)
+
+	if err != nil {
+		if errors.As(err, new(error_handlers.ValidationErrors)) {
+			return nil, error_handlers.ParseValidationError(ctx, err)
+		}
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return response, nil
+}
+
+// IngestNmea is the resolver for the IngestNmea field.
+func (r *mutationResolver) IngestNmea(ctx context.Context, sentences []string) (*ais.IngestResult, error) {
+	response, err := r.AisIngestor.IngestLines(ctx, sentences)
+
+	if err != nil {
+		// kegagalan Mongo, bukan Postgres
+		return nil, gqlerror.Errorf("Gagal menyimpan kalimat NMEA: %v", err)
+	}
+
+	return response, nil
+}
+
 // GetOneShip is the resolver for the GetOneShip field.
 func (r *queryResolver) GetOneShip(ctx context.Context, id int) (any, error) {
 	ship, err := r.ShipRepository.GetShipByID(ctx, int32(id))
@@ -94,7 +159,7 @@ func (r *queryResolver) GetOneShip(ctx context.Context, id int) (any, error) {
 		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
 	}
 
-	return ship, nil
+	return r.ShipAisLinker.Detail(ctx, ship), nil
 }
 
 // GetOneShipByUUID is the resolver for the GetOneShipByUuid field.
@@ -105,7 +170,7 @@ func (r *queryResolver) GetOneShipByUUID(ctx context.Context, uuid uuid.UUID) (a
 		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
 	}
 
-	return ship, nil
+	return r.ShipAisLinker.Detail(ctx, ship), nil
 }
 
 // GetAllShips is the resolver for the GetAllShips field.
@@ -126,38 +191,31 @@ func (r *queryResolver) GetAllShips(ctx context.Context) ([]any, error) {
 
 // GetAllBigShips is the resolver for the GetAllBigShips field.
 func (r *queryResolver) GetAllBigShips(ctx context.Context) ([]any, error) {
-	ships, err := r.ShipMongodistory.GetAllBigShips(ctx)
+	docs, err := r.ShipMongodistory.GetAllBigShips(ctx)
 
 	if err != nil {
 		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
 	}
 
-	response := make([]any, len(ships))
-	for i, ship := range ships {
-		response[i] = ship
+	response := make([]any, len(docs))
+	for i, doc := range docs {
+		response[i] = doc
 	}
 
 	return response, nil
 }
 
 // GetShipsByDatetime is the resolver for the GetShipsByDatetime field.
-func (r *queryResolver) GetShipsByDatetime(ctx context.Context, durationTimeInput *models.DurationTimeInput, mmsiList []int64) ([]any, error) {
-	// log.Print(mmsiList)
-	// mmsiList16 := make([]int16, len(mmsiList))
-
-	// for i, mmsi := range mmsiList {
-	// 	mmsiList16[i] = int16(mmsi)
-	// }
-
-	ships, err := r.ShipMongotory.GetShipsByDatetime(*durationTimeInput, mmsiList)
+func (r *queryResolver) GetShipsByDatetime(ctx context.Context, durationTimeInput *models.DurationTimeInput, mmsiList []int64, tolerance *float64, bucketSeconds *int, excludeAnomalies *bool) ([]any, error) {
+	positions, err := r.GetVesselPositionsByDatetime(ctx, durationTimeInput, mmsiList, tolerance, bucketSeconds, excludeAnomalies)
 
 	if err != nil {
-		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+		return nil, err
 	}
 
-	response := make([]any, len(ships))
-	for i, ship := range ships {
-		response[i] = ship
+	response := make([]any, len(positions))
+	for i, position := range positions {
+		response[i] = position.Raw
 	}
 
 	return response, nil
@@ -165,15 +223,15 @@ func (r *queryResolver) GetShipsByDatetime(ctx context.Context, durationTimeInpu
 
 // GetMobShips is the resolver for the GetMobShips field.
 func (r *queryResolver) GetMobShips(ctx context.Context, durationTimeInput *models.DurationTimeInput) ([]any, error) {
-	ships, err := r.ShipMongotory.GetMobShips(*durationTimeInput)
+	docs, err := r.ShipMongotory.GetMobShips(*durationTimeInput)
 
 	if err != nil {
 		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
 	}
 
-	response := make([]any, len(ships))
-	for i, ship := range ships {
-		response[i] = ship
+	response := make([]any, len(docs))
+	for i, doc := range docs {
+		response[i] = doc
 	}
 
 	return response, nil
@@ -199,3 +257,262 @@ func (r *queryResolver) PageShip(ctx context.Context, pageInput *models.PageInpu
 
 	return &response, nil
 }
+
+// GetBigShipStatics is the resolver for the GetBigShipStatics field.
+func (r *queryResolver) GetBigShipStatics(ctx context.Context) ([]*ships.VesselStatic, error) {
+	docs, err := r.ShipMongodistory.GetAllBigShips(ctx)
+
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	response := make([]*ships.VesselStatic, len(docs))
+	for i, doc := range docs {
+		response[i] = ships.NewVesselStatic(doc)
+	}
+
+	return response, nil
+}
+
+// GetVesselPositionsByDatetime is the resolver for the GetVesselPositionsByDatetime field.
+func (r *queryResolver) GetVesselPositionsByDatetime(ctx context.Context, durationTimeInput *models.DurationTimeInput, mmsiList []int64, tolerance *float64, bucketSeconds *int, excludeAnomalies *bool) ([]*ships.VesselPosition, error) {
+	// log.Print(mmsiList)
+	// mmsiList16 := make([]int16, len(mmsiList))
+
+	// for i, mmsi := range mmsiList {
+	// 	mmsiList16[i] = int16(mmsi)
+	// }
+
+	if (tolerance != nil && *tolerance < 0) || (bucketSeconds != nil && *bucketSeconds < 0) {
+		return nil, gqlerror.Errorf("tolerance dan bucketSeconds tidak boleh negatif")
+	}
+
+	tracks, err := r.ShipMongotory.GetShipsByDatetime(*durationTimeInput, mmsiList, ships.TrackOptions{
+		Tolerance:        tolerance,
+		BucketSeconds:    bucketSeconds,
+		ExcludeAnomalies: excludeAnomalies != nil && *excludeAnomalies,
+	})
+
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	// Urutan lama: semua vessel digabung, terbaru dulu
+	response := []*ships.VesselPosition{}
+	for _, track := range ships.NewVesselTrackResult(tracks).Tracks {
+		response = append(response, track.Positions...)
+	}
+	sort.SliceStable(response, func(i, j int) bool {
+		return response[i].Ts > response[j].Ts
+	})
+
+	return response, nil
+}
+
+// GetMobEvents is the resolver for the GetMobEvents field.
+func (r *queryResolver) GetMobEvents(ctx context.Context, durationTimeInput *models.DurationTimeInput) ([]*ships.MobEvent, error) {
+	docs, err := r.ShipMongotory.GetMobShips(*durationTimeInput)
+
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	response := make([]*ships.MobEvent, len(docs))
+	for i, doc := range docs {
+		response[i] = ships.NewMobEvent(doc)
+	}
+
+	return response, nil
+}
+
+// GetShipTracks is the resolver for the GetShipTracks field.
+func (r *queryResolver) GetShipTracks(ctx context.Context, durationTimeInput models.DurationTimeInput, mmsiList []int64, imei *string, tolerance *float64, bucketSeconds *int, excludeAnomalies *bool) (*ships.VesselTrackResult, error) {
+	if (tolerance != nil && *tolerance < 0) || (bucketSeconds != nil && *bucketSeconds < 0) {
+		return nil, gqlerror.Errorf("tolerance dan bucketSeconds tidak boleh negatif")
+	}
+
+	trackOptions := ships.TrackOptions{
+		Tolerance:        tolerance,
+		BucketSeconds:    bucketSeconds,
+		ExcludeAnomalies: excludeAnomalies != nil && *excludeAnomalies,
+	}
+
+	var tracks []*ships.Track
+	var err error
+	if imei != nil && *imei != "" {
+		tracks, err = r.ShipMongotory.GetShipsByImei(*imei, durationTimeInput, trackOptions)
+	} else {
+		tracks, err = r.ShipMongotory.GetShipsByDatetime(durationTimeInput, mmsiList, trackOptions)
+	}
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return ships.NewVesselTrackResult(tracks), nil
+}
+
+// GetTrackReplay is the resolver for the GetTrackReplay field.
+func (r *queryResolver) GetTrackReplay(ctx context.Context, mmsiList []int64, durationTimeInput models.DurationTimeInput, stepSeconds int, maxGapSeconds *int, excludeAnomalies *bool) (*ships.TrackReplay, error) {
+	const maxReplayFrames = 500000
+
+	if len(mmsiList) == 0 {
+		return nil, gqlerror.Errorf("mmsiList tidak boleh kosong")
+	}
+	if stepSeconds <= 0 {
+		return nil, gqlerror.Errorf("stepSeconds harus lebih dari 0")
+	}
+	if durationTimeInput.End < durationTimeInput.Start {
+		return nil, gqlerror.Errorf("end harus setelah start")
+	}
+
+	maxGap := 15 * time.Minute
+	if maxGapSeconds != nil {
+		if *maxGapSeconds <= 0 {
+			return nil, gqlerror.Errorf("maxGapSeconds harus lebih dari 0")
+		}
+		maxGap = time.Duration(*maxGapSeconds) * time.Second
+	}
+
+	steps := (durationTimeInput.End-durationTimeInput.Start)/int64(stepSeconds) + 1
+	if steps*int64(len(mmsiList)) > maxReplayFrames {
+		return nil, gqlerror.Errorf("terlalu banyak frame (%d), perbesar stepSeconds atau perkecil rentang waktu", steps*int64(len(mmsiList)))
+	}
+
+	// Ambil posisi sedikit di luar rentang agar frame di tepi tetap bisa diinterpolasi
+	gapSeconds := int64(maxGap.Seconds())
+	window := models.DurationTimeInput{
+		Start: durationTimeInput.Start - gapSeconds,
+		End:   durationTimeInput.End + gapSeconds,
+	}
+	// Posisi lebih rapat dari 1/4 step tidak menambah akurasi playback
+	trackOptions := ships.TrackOptions{ExcludeAnomalies: excludeAnomalies != nil && *excludeAnomalies}
+	if bucketSeconds := stepSeconds / 4; bucketSeconds >= 1 {
+		trackOptions.BucketSeconds = &bucketSeconds
+	}
+
+	tracks, err := r.ShipMongotory.GetShipsByDatetime(window, mmsiList, trackOptions)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return ships.BuildTrackReplay(
+		mmsiList,
+		tracks,
+		time.Unix(durationTimeInput.Start, 0),
+		time.Unix(durationTimeInput.End, 0),
+		time.Duration(stepSeconds)*time.Second,
+		maxGap,
+	), nil
+}
+
+// GetVesselSnapshot is the resolver for the GetVesselSnapshot field.
+func (r *queryResolver) GetVesselSnapshot(ctx context.Context, bbox models.BoundingBoxInput, at *int64, maxAge *int) ([]*ships.VesselSnapshot, error) {
+	snapshotAt := time.Now()
+	if at != nil {
+		snapshotAt = time.Unix(*at, 0)
+	}
+
+	snapshotMaxAge := 30 * time.Minute
+	if maxAge != nil {
+		if *maxAge <= 0 {
+			return nil, gqlerror.Errorf("maxAge harus lebih dari 0 detik")
+		}
+		snapshotMaxAge = time.Duration(*maxAge) * time.Second
+	}
+
+	snapshots, err := r.ShipMongodistory.GetVesselSnapshot(ctx, geo.BoundingBox(bbox), snapshotAt, snapshotMaxAge)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	if snapshots == nil {
+		snapshots = []*ships.VesselSnapshot{}
+	}
+	return snapshots, nil
+}
+
+// GetVesselClusters is the resolver for the GetVesselClusters field.
+func (r *queryResolver) GetVesselClusters(ctx context.Context, bbox models.BoundingBoxInput, zoom int, at *int64, maxAge *int) ([]*geo.Cluster, error) {
+	if zoom < 0 || zoom > 22 {
+		return nil, gqlerror.Errorf("zoom harus di antara 0 dan 22")
+	}
+
+	snapshotAt := time.Now()
+	if at != nil {
+		snapshotAt = time.Unix(*at, 0)
+	}
+
+	snapshotMaxAge := 30 * time.Minute
+	if maxAge != nil {
+		if *maxAge <= 0 {
+			return nil, gqlerror.Errorf("maxAge harus lebih dari 0 detik")
+		}
+		snapshotMaxAge = time.Duration(*maxAge) * time.Second
+	}
+
+	clusters, err := r.ShipMongodistory.GetVesselClusters(ctx, geo.BoundingBox(bbox), zoom, snapshotAt, snapshotMaxAge)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return clusters, nil
+}
+
+// GetAisStations is the resolver for the GetAisStations field.
+func (r *queryResolver) GetAisStations(ctx context.Context) ([]*ais.StationStatus, error) {
+	return r.AisListener.Statuses(), nil
+}
+
+// ShipPositionUpdated is the resolver for the ShipPositionUpdated field.
+func (r *subscriptionResolver) ShipPositionUpdated(ctx context.Context, filter *models.ShipPositionFilterInput) (<-chan *ships.VesselPosition, error) {
+	positionFilter := &ais.PositionFilter{}
+	if filter != nil {
+		positionFilter.MmsiList = filter.MmsiList
+		if filter.Imei != nil {
+			positionFilter.Imei = *filter.Imei
+		}
+		if filter.BoundingBox != nil {
+			positionFilter.Areas = append(positionFilter.Areas, geo.BoundingBox(*filter.BoundingBox))
+		}
+		if filter.GeofenceID != nil {
+			geofence, err := r.GeofenceRepository.GetGeofenceByID(ctx, int32(*filter.GeofenceID))
+			if err != nil {
+				return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+			}
+			positionFilter.Areas = append(positionFilter.Areas, geofence)
+		}
+	}
+
+	docs, unsubscribe := r.AisIngestor.SubscribePositions(256)
+	positions := make(chan *ships.VesselPosition, 1)
+
+	go func() {
+		defer close(positions)
+		defer unsubscribe()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case doc, ok := <-docs:
+				if !ok {
+					return
+				}
+				if !positionFilter.Match(doc) {
+					continue
+				}
+				position, ok := ships.NewVesselPositionFromDocument(doc)
+				if !ok {
+					continue
+				}
+				select {
+				case positions <- position:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return positions, nil
+}
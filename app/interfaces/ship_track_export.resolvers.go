@@ -0,0 +1,37 @@
+package interfaces
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/domains/ships"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// ExportVesselTrack is the resolver for the ExportVesselTrack field.
+func (r *mutationResolver) ExportVesselTrack(ctx context.Context, mmsi *int64, imei *string, durationTimeInput models.DurationTimeInput, format models.TrackFileFormat) (*models.TrackExport, error) {
+	query := ships.TrackExportQuery{
+		TrackQuery: ships.TrackQuery{
+			Mmsi:  mmsi,
+			Imei:  imei,
+			Start: time.Unix(durationTimeInput.Start, 0),
+			End:   time.Unix(durationTimeInput.End, 0),
+		},
+		Format: format,
+	}
+	if err := query.Validate(); err != nil {
+		return nil, gqlerror.Errorf("%v", err)
+	}
+
+	response, err := r.TrackExporter.Export(ctx, query)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+	return response, nil
+}
@@ -0,0 +1,106 @@
+package interfaces
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.73
+
+import (
+	"context"
+	"time"
+
+	"github.com/khoirulhasin/untirta_api/app/domains/voyages"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/error_handlers"
+	"github.com/khoirulhasin/untirta_api/app/infrastructures/pkg"
+	"github.com/khoirulhasin/untirta_api/app/models"
+	"github.com/vektah/gqlparser/v2/gqlerror"
+)
+
+// DetectVoyages is the resolver for the DetectVoyages field.
+func (r *mutationResolver) DetectVoyages(ctx context.Context, mmsiList []int64, durationTimeInput models.DurationTimeInput, options *models.VoyageDetectionInput) ([]*models.Voyage, error) {
+	const maxDetectionWindow = 31 * 24 * time.Hour
+
+	if len(mmsiList) == 0 {
+		return nil, gqlerror.Errorf("mmsiList tidak boleh kosong")
+	}
+	if durationTimeInput.End < durationTimeInput.Start {
+		return nil, gqlerror.Errorf("end harus setelah start")
+	}
+
+	start := time.Unix(durationTimeInput.Start, 0)
+	end := time.Unix(durationTimeInput.End, 0)
+	if end.Sub(start) > maxDetectionWindow {
+		return nil, gqlerror.Errorf("rentang waktu maksimal %d hari", int(maxDetectionWindow.Hours()/24))
+	}
+
+	opts := voyages.NewDetectionOptions(options)
+	if opts.MovingSpeed <= 0 {
+		return nil, gqlerror.Errorf("movingSpeed harus lebih dari 0")
+	}
+	if opts.MinStop < 0 {
+		return nil, gqlerror.Errorf("minStopSeconds tidak boleh negatif")
+	}
+	if opts.MaxGap <= 0 {
+		return nil, gqlerror.Errorf("maxGapSeconds harus lebih dari 0")
+	}
+
+	response, err := r.VoyageDetector.Detect(ctx, mmsiList, start, end, opts)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return response, nil
+}
+
+// GetOneVoyage is the resolver for the GetOneVoyage field.
+func (r *queryResolver) GetOneVoyage(ctx context.Context, id int) (*models.Voyage, error) {
+	response, err := r.VoyageRepository.GetVoyageByID(ctx, int32(id))
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return response, nil
+}
+
+// GetVoyages is the resolver for the GetVoyages field.
+func (r *queryResolver) GetVoyages(ctx context.Context, mmsi int64, durationTimeInput models.DurationTimeInput) ([]*models.Voyage, error) {
+	if durationTimeInput.End < durationTimeInput.Start {
+		return nil, gqlerror.Errorf("end harus setelah start")
+	}
+
+	response, err := r.VoyageRepository.GetVoyagesByMmsi(ctx, mmsi, durationTimeInput.Start*1000, durationTimeInput.End*1000)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return response, nil
+}
+
+// PageVoyage is the resolver for the PageVoyage field.
+func (r *queryResolver) PageVoyage(ctx context.Context, pageInput *models.PageInput) (*models.Pagination, error) {
+	limit, offset, sortField, sortOrder, search, filters := pkg.PageInputIsNil(pageInput)
+
+	var mappedFilters []*models.Filter
+	for _, f := range filters {
+		mappedFilters = append(mappedFilters, &models.Filter{
+			Key:      f.Key,
+			Operator: f.Operator,
+			Value:    f.Value,
+		})
+	}
+
+	pagination := models.Pagination{
+		Limit:     &limit,
+		Offset:    &offset,
+		SortField: &sortField,
+		SortOrder: &sortOrder,
+		Search:    &search,
+		Filters:   mappedFilters,
+	}
+
+	response, err := r.VoyageRepository.PageVoyage(ctx, pagination)
+	if err != nil {
+		return nil, gqlerror.Errorf(error_handlers.ParsePGError(err))
+	}
+
+	return &response, nil
+}
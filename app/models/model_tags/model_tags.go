@@ -38,6 +38,8 @@ func addGormTags(b *modelgen.ModelBuild) *modelgen.ModelBuild {
 				field.Tag += ` gorm:"foreignKey:OwnerID;references:ID"`
 			} else if field.Name == "imei" {
 				field.Tag += ` gorm:"uniqueIndex:idx_` + strings.ToLower(model.Name) + `_imei,WHERE:deleted_at=0;column:imei"`
+			} else if (field.Name == "mmsi" || field.Name == "imo") && model.Name == "Ship" {
+				field.Tag += ` gorm:"uniqueIndex:idx_` + strings.ToLower(model.Name) + `_` + field.Name + `,WHERE:deleted_at=0;column:` + field.Name + `"`
 			} else if field.Name == "code" {
 				field.Tag += ` gorm:"uniqueIndex:idx_` + strings.ToLower(model.Name) + `_code,WHERE:deleted_at=0;column:code"`
 			} else if field.Name == "username" {
@@ -46,7 +48,7 @@ func addGormTags(b *modelgen.ModelBuild) *modelgen.ModelBuild {
 				field.Tag += ` gorm:"uniqueIndex:idx_` + strings.ToLower(model.Name) + `_email,WHERE:deleted_at=0;column:email"`
 			} else if field.Name == "name" {
 				field.Tag += ` gorm:"index:idx_` + strings.ToLower(model.Name) + `_` + helpers.ToSnakeCase(field.Name) + `;column:name"`
-			} else if helpers.Contains(models, strings.ReplaceAll(strings.ReplaceAll(field.Type.String(), "*", ""), helpers.GetModuleRootName()+"/app/models.", "")) == false && field.Name != "menus" {
+			} else if helpers.Contains(models, strings.ReplaceAll(strings.ReplaceAll(field.Type.String(), "*", ""), helpers.GetModuleRootName()+"/app/models.", "")) == false && field.Name != "menus" && field.Name != "events" {
 				field.Tag += ` gorm:"column:` + helpers.ToSnakeCase(field.Name) + `"`
 			}
 		}
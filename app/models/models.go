@@ -12,6 +12,60 @@ import (
 	"gorm.io/plugin/soft_delete"
 )
 
+type AisAnomalyDetectionInput struct {
+	MaxSpeedKnots     *float64 `json:"maxSpeedKnots,omitempty" gorm:"column:max_speed_knots"`
+	MinJumpNm         *float64 `json:"minJumpNm,omitempty" gorm:"column:min_jump_nm"`
+	MinDuplicateFixes *int     `json:"minDuplicateFixes,omitempty" gorm:"column:min_duplicate_fixes"`
+}
+
+type AisAnomalyScan struct {
+	VesselCount     int `json:"vesselCount" gorm:"column:vessel_count"`
+	PositionCount   int `json:"positionCount" gorm:"column:position_count"`
+	AnomalyCount    int `json:"anomalyCount" gorm:"column:anomaly_count"`
+	NewAnomalyCount int `json:"newAnomalyCount" gorm:"column:new_anomaly_count"`
+}
+
+type Alert struct {
+	ID                int                    `json:"id" gorm:"column:id;uniqueIndex;primaryKey;autoIcrement"`
+	UUID              uuid.UUID              `json:"uuid" gorm:"column:uuid;uniqueIndex;type:uuid;default:uuid_generate_v4()"`
+	Kind              AlertKind              `json:"kind" gorm:"column:kind"`
+	Severity          AlertSeverity          `json:"severity" gorm:"column:severity"`
+	Status            AlertStatus            `json:"status" gorm:"column:status"`
+	DedupKey          string                 `json:"dedupKey" gorm:"column:dedup_key"`
+	Message           string                 `json:"message" gorm:"column:message"`
+	ShipID            *int                   `json:"shipId,omitempty" gorm:"column:ship_id"`
+	Mmsi              *int64                 `json:"mmsi,omitempty" gorm:"column:mmsi"`
+	DeviceImei        *string                `json:"deviceImei,omitempty" gorm:"column:device_imei"`
+	TargetMmsi        *int64                 `json:"targetMmsi,omitempty" gorm:"column:target_mmsi"`
+	GeofenceID        *int                   `json:"geofenceId,omitempty" gorm:"column:geofence_id"`
+	RuleID            *int                   `json:"ruleId,omitempty" gorm:"column:rule_id"`
+	MarkerID          *int                   `json:"markerId,omitempty" gorm:"column:marker_id"`
+	Latitude          *float64               `json:"latitude,omitempty" gorm:"column:latitude"`
+	Longitude         *float64               `json:"longitude,omitempty" gorm:"column:longitude"`
+	CpaNm             *float64               `json:"cpaNm,omitempty" gorm:"column:cpa_nm"`
+	TcpaMinutes       *float64               `json:"tcpaMinutes,omitempty" gorm:"column:tcpa_minutes"`
+	ClosestApproachAt *int64                 `json:"closestApproachAt,omitempty" gorm:"column:closest_approach_at"`
+	FirstSeenAt       int64                  `json:"firstSeenAt" gorm:"column:first_seen_at"`
+	LastSeenAt        int64                  `json:"lastSeenAt" gorm:"column:last_seen_at"`
+	AcknowledgedAt    *int64                 `json:"acknowledgedAt,omitempty" gorm:"column:acknowledged_at"`
+	AcknowledgedBy    *int                   `json:"acknowledgedBy,omitempty" gorm:"column:acknowledged_by"`
+	ResolvedAt        *int64                 `json:"resolvedAt,omitempty" gorm:"column:resolved_at"`
+	ResolvedBy        *int                   `json:"resolvedBy,omitempty" gorm:"column:resolved_by"`
+	CreatedAt         int64                  `json:"createdAt" gorm:"column:created_at;type:bigint;autoCreateTime:milli"`
+	UpdatedAt         int64                  `json:"updatedAt" gorm:"column:updated_at;type:bigint;autoUpdateTime:milli"`
+	DeletedAt         *soft_delete.DeletedAt `json:"deletedAt,omitempty" gorm:"column:deleted_at;type:bigint;softDelete:milli;default:0"`
+	CreatedBy         int                    `json:"createdBy" gorm:"column:created_by"`
+	UpdatedBy         *int                   `json:"updatedBy,omitempty" gorm:"column:updated_by"`
+	DeletedBy         *int                   `json:"deletedBy,omitempty" gorm:"column:deleted_by"`
+}
+
+type BoundingBoxInput struct {
+	MinLat float64 `json:"minLat" gorm:"column:min_lat"`
+	MinLon float64 `json:"minLon" gorm:"column:min_lon"`
+	MaxLat float64 `json:"maxLat" gorm:"column:max_lat"`
+	MaxLon float64 `json:"maxLon" gorm:"column:max_lon"`
+}
+
 type Cam struct {
 	ID        int                    `json:"id" gorm:"column:id;uniqueIndex;primaryKey;autoIcrement"`
 	UUID      uuid.UUID              `json:"uuid" gorm:"column:uuid;uniqueIndex;type:uuid;default:uuid_generate_v4()"`
@@ -32,6 +86,13 @@ type ChangePasswordInput struct {
 	OldPassword string `json:"oldPassword" gorm:"column:old_password"`
 }
 
+type CollisionLimitsInput struct {
+	RadiusNm      *float64 `json:"radiusNm,omitempty" gorm:"column:radius_nm"`
+	CpaNm         *float64 `json:"cpaNm,omitempty" gorm:"column:cpa_nm"`
+	TcpaMinutes   *float64 `json:"tcpaMinutes,omitempty" gorm:"column:tcpa_minutes"`
+	MaxAgeSeconds *int     `json:"maxAgeSeconds,omitempty" gorm:"column:max_age_seconds"`
+}
+
 type CreateCamInput struct {
 	Name      string  `json:"name" gorm:"index:idx_createcaminput_name;column:name"`
 	Code      string  `json:"code" gorm:"uniqueIndex:idx_createcaminput_code,WHERE:deleted_at=0;column:code"`
@@ -58,6 +119,25 @@ type CreateDriverInput struct {
 	Address          *string `json:"address,omitempty" gorm:"column:address"`
 }
 
+type CreateGeofenceAlertRuleInput struct {
+	Name            string              `json:"name" gorm:"index:idx_creategeofencealertruleinput_name;column:name"`
+	Description     *string             `json:"description,omitempty" gorm:"column:description"`
+	GeofenceID      int                 `json:"geofenceId" gorm:"column:geofence_id"`
+	Trigger         GeofenceRuleTrigger `json:"trigger" gorm:"column:trigger"`
+	DwellMinutes    *int                `json:"dwellMinutes,omitempty" gorm:"column:dwell_minutes"`
+	SpeedKnots      *float64            `json:"speedKnots,omitempty" gorm:"column:speed_knots"`
+	Target          GeofenceRuleTarget  `json:"target" gorm:"column:target"`
+	MmsiList        []int64             `json:"mmsiList,omitempty" gorm:"column:mmsi_list"`
+	OwnerID         *int                `json:"ownerId,omitempty" gorm:"column:owner_id"`
+	ActiveFrom      *string             `json:"activeFrom,omitempty" gorm:"column:active_from"`
+	ActiveTo        *string             `json:"activeTo,omitempty" gorm:"column:active_to"`
+	ActiveDays      []int               `json:"activeDays,omitempty" gorm:"column:active_days"`
+	Timezone        *string             `json:"timezone,omitempty" gorm:"column:timezone"`
+	CooldownMinutes *int                `json:"cooldownMinutes,omitempty" gorm:"column:cooldown_minutes"`
+	Severity        *AlertSeverity      `json:"severity,omitempty" gorm:"column:severity"`
+	IsActive        *bool               `json:"isActive,omitempty" gorm:"column:is_active"`
+}
+
 type CreateGeofenceInput struct {
 	Name        string   `json:"name" gorm:"index:idx_creategeofenceinput_name;column:name"`
 	Description *string  `json:"description,omitempty" gorm:"column:description"`
@@ -78,11 +158,15 @@ type CreateMarkerInput struct {
 	Duration     *DurationTimeInput `json:"duration"`
 	MarkerTypeID int                `json:"markerTypeId" gorm:"column:marker_type_id"`
 	Description  *string            `json:"description,omitempty" gorm:"column:description"`
+	Rrule        *string            `json:"rrule,omitempty" gorm:"column:rrule"`
+	Timezone     *string            `json:"timezone,omitempty" gorm:"column:timezone"`
 }
 
 type CreateMarkerTypeInput struct {
-	Name string `json:"name" gorm:"index:idx_createmarkertypeinput_name;column:name"`
-	Icon string `json:"icon" gorm:"column:icon"`
+	Name          string         `json:"name" gorm:"index:idx_createmarkertypeinput_name;column:name"`
+	Icon          string         `json:"icon" gorm:"column:icon"`
+	AlertRadiusNm *float64       `json:"alertRadiusNm,omitempty" gorm:"column:alert_radius_nm"`
+	AlertSeverity *AlertSeverity `json:"alertSeverity,omitempty" gorm:"column:alert_severity"`
 }
 
 type CreateMenuInput struct {
@@ -113,9 +197,17 @@ type CreateRoleInput struct {
 }
 
 type CreateShipInput struct {
-	Name        string  `json:"name" gorm:"index:idx_createshipinput_name;column:name"`
-	Number      *string `json:"number,omitempty" gorm:"column:number"`
-	Description *string `json:"description,omitempty" gorm:"column:description"`
+	Name         string   `json:"name" gorm:"index:idx_createshipinput_name;column:name"`
+	Number       *string  `json:"number,omitempty" gorm:"column:number"`
+	Description  *string  `json:"description,omitempty" gorm:"column:description"`
+	Mmsi         *int64   `json:"mmsi,omitempty" gorm:"column:mmsi"`
+	Imo          *int64   `json:"imo,omitempty" gorm:"column:imo"`
+	CallSign     *string  `json:"callSign,omitempty" gorm:"column:call_sign"`
+	Flag         *string  `json:"flag,omitempty" gorm:"column:flag"`
+	VesselType   *string  `json:"vesselType,omitempty" gorm:"column:vessel_type"`
+	Length       *float64 `json:"length,omitempty" gorm:"column:length"`
+	Beam         *float64 `json:"beam,omitempty" gorm:"column:beam"`
+	GrossTonnage *float64 `json:"grossTonnage,omitempty" gorm:"column:gross_tonnage"`
 }
 
 type CreateUserInput struct {
@@ -202,22 +294,18 @@ type FilterInput struct {
 	Operator string `json:"operator" gorm:"column:operator"`
 }
 
-type LoginInput struct {
-	Account  string `json:"account" gorm:"column:account"`
-	Password string `json:"password" gorm:"column:password"`
-}
-
-type Marker struct {
+type GeofenceEvent struct {
 	ID           int                    `json:"id" gorm:"column:id;uniqueIndex;primaryKey;autoIcrement"`
 	UUID         uuid.UUID              `json:"uuid" gorm:"column:uuid;uniqueIndex;type:uuid;default:uuid_generate_v4()"`
-	Title        string                 `json:"title" gorm:"column:title"`
-	Lat          float64                `json:"lat" gorm:"column:lat"`
-	Lng          float64                `json:"lng" gorm:"column:lng"`
-	Start        int64                  `json:"start" gorm:"column:start"`
-	End          int64                  `json:"end" gorm:"column:end"`
-	MarkerTypeID int                    `json:"markerTypeId" gorm:"column:marker_type_id"`
-	MarkerType   *MarkerType            `json:"markerType"`
-	Description  *string                `json:"description,omitempty" gorm:"column:description"`
+	GeofenceID   int                    `json:"geofenceId" gorm:"column:geofence_id"`
+	GeofenceName string                 `json:"geofenceName" gorm:"column:geofence_name"`
+	Kind         GeofenceEventKind      `json:"kind" gorm:"column:kind"`
+	Mmsi         *int64                 `json:"mmsi,omitempty" gorm:"column:mmsi"`
+	DeviceImei   *string                `json:"deviceImei,omitempty" gorm:"column:device_imei"`
+	Latitude     float64                `json:"latitude" gorm:"column:latitude"`
+	Longitude    float64                `json:"longitude" gorm:"column:longitude"`
+	OccurredAt   int64                  `json:"occurredAt" gorm:"column:occurred_at"`
+	DwellSeconds *int                   `json:"dwellSeconds,omitempty" gorm:"column:dwell_seconds"`
 	CreatedAt    int64                  `json:"createdAt" gorm:"column:created_at;type:bigint;autoCreateTime:milli"`
 	UpdatedAt    int64                  `json:"updatedAt" gorm:"column:updated_at;type:bigint;autoUpdateTime:milli"`
 	DeletedAt    *soft_delete.DeletedAt `json:"deletedAt,omitempty" gorm:"column:deleted_at;type:bigint;softDelete:milli;default:0"`
@@ -226,17 +314,128 @@ type Marker struct {
 	DeletedBy    *int                   `json:"deletedBy,omitempty" gorm:"column:deleted_by"`
 }
 
+type GeofenceExport struct {
+	Format      GeofenceFileFormat `json:"format" gorm:"column:format"`
+	Filename    string             `json:"filename" gorm:"column:filename"`
+	ContentType string             `json:"contentType" gorm:"column:content_type"`
+	Encoding    string             `json:"encoding" gorm:"column:encoding"`
+	Content     string             `json:"content" gorm:"column:content"`
+}
+
+type GeofenceImportItem struct {
+	Index         int                  `json:"index" gorm:"column:index"`
+	Name          string               `json:"name" gorm:"index:idx_geofenceimportitem_name;column:name"`
+	Type          *string              `json:"type,omitempty" gorm:"column:type"`
+	GeoType       *string              `json:"geoType,omitempty" gorm:"column:geo_type"`
+	Action        GeofenceImportAction `json:"action" gorm:"column:action"`
+	DuplicateOfID *int                 `json:"duplicateOfId,omitempty" gorm:"column:duplicate_of_id"`
+	Errors        []string             `json:"errors" gorm:"column:errors"`
+	Geofence      any                  `json:"geofence,omitempty" gorm:"column:geofence"`
+}
+
+type GeofenceImportResult struct {
+	DryRun  bool                  `json:"dryRun" gorm:"column:dry_run"`
+	Format  GeofenceFileFormat    `json:"format" gorm:"column:format"`
+	Total   int                   `json:"total" gorm:"column:total"`
+	Created int                   `json:"created" gorm:"column:created"`
+	Updated int                   `json:"updated" gorm:"column:updated"`
+	Skipped int                   `json:"skipped" gorm:"column:skipped"`
+	Invalid int                   `json:"invalid" gorm:"column:invalid"`
+	Items   []*GeofenceImportItem `json:"items" gorm:"column:items"`
+}
+
+type Incident struct {
+	ID             int                    `json:"id" gorm:"column:id;uniqueIndex;primaryKey;autoIcrement"`
+	UUID           uuid.UUID              `json:"uuid" gorm:"column:uuid;uniqueIndex;type:uuid;default:uuid_generate_v4()"`
+	Kind           MobKind                `json:"kind" gorm:"column:kind"`
+	Status         IncidentStatus         `json:"status" gorm:"column:status"`
+	DedupKey       string                 `json:"dedupKey" gorm:"column:dedup_key"`
+	Mmsi           int64                  `json:"mmsi" gorm:"column:mmsi"`
+	MessageType    int                    `json:"messageType" gorm:"column:message_type"`
+	Text           *string                `json:"text,omitempty" gorm:"column:text"`
+	Latitude       *float64               `json:"latitude,omitempty" gorm:"column:latitude"`
+	Longitude      *float64               `json:"longitude,omitempty" gorm:"column:longitude"`
+	DetectionCount int                    `json:"detectionCount" gorm:"column:detection_count"`
+	FirstSeenAt    int64                  `json:"firstSeenAt" gorm:"column:first_seen_at"`
+	LastSeenAt     int64                  `json:"lastSeenAt" gorm:"column:last_seen_at"`
+	AssigneeID     *int                   `json:"assigneeId,omitempty" gorm:"column:assignee_id"`
+	Assignee       *User                  `json:"assignee,omitempty"`
+	AcknowledgedAt *int64                 `json:"acknowledgedAt,omitempty" gorm:"column:acknowledged_at"`
+	AcknowledgedBy *int                   `json:"acknowledgedBy,omitempty" gorm:"column:acknowledged_by"`
+	ResolvedAt     *int64                 `json:"resolvedAt,omitempty" gorm:"column:resolved_at"`
+	ResolvedBy     *int                   `json:"resolvedBy,omitempty" gorm:"column:resolved_by"`
+	Events         []*IncidentEvent       `json:"events,omitempty"`
+	CreatedAt      int64                  `json:"createdAt" gorm:"column:created_at;type:bigint;autoCreateTime:milli"`
+	UpdatedAt      int64                  `json:"updatedAt" gorm:"column:updated_at;type:bigint;autoUpdateTime:milli"`
+	DeletedAt      *soft_delete.DeletedAt `json:"deletedAt,omitempty" gorm:"column:deleted_at;type:bigint;softDelete:milli;default:0"`
+	CreatedBy      int                    `json:"createdBy" gorm:"column:created_by"`
+	UpdatedBy      *int                   `json:"updatedBy,omitempty" gorm:"column:updated_by"`
+	DeletedBy      *int                   `json:"deletedBy,omitempty" gorm:"column:deleted_by"`
+}
+
+type IncidentEvent struct {
+	ID         int                    `json:"id" gorm:"column:id;uniqueIndex;primaryKey;autoIcrement"`
+	UUID       uuid.UUID              `json:"uuid" gorm:"column:uuid;uniqueIndex;type:uuid;default:uuid_generate_v4()"`
+	IncidentID int                    `json:"incidentId" gorm:"column:incident_id"`
+	Kind       IncidentEventKind      `json:"kind" gorm:"column:kind"`
+	FromStatus *IncidentStatus        `json:"fromStatus,omitempty" gorm:"column:from_status"`
+	ToStatus   *IncidentStatus        `json:"toStatus,omitempty" gorm:"column:to_status"`
+	AssigneeID *int                   `json:"assigneeId,omitempty" gorm:"column:assignee_id"`
+	Note       *string                `json:"note,omitempty" gorm:"column:note"`
+	UserID     *int                   `json:"userId,omitempty" gorm:"column:user_id"`
+	CreatedAt  int64                  `json:"createdAt" gorm:"column:created_at;type:bigint;autoCreateTime:milli"`
+	UpdatedAt  int64                  `json:"updatedAt" gorm:"column:updated_at;type:bigint;autoUpdateTime:milli"`
+	DeletedAt  *soft_delete.DeletedAt `json:"deletedAt,omitempty" gorm:"column:deleted_at;type:bigint;softDelete:milli;default:0"`
+	CreatedBy  int                    `json:"createdBy" gorm:"column:created_by"`
+	UpdatedBy  *int                   `json:"updatedBy,omitempty" gorm:"column:updated_by"`
+	DeletedBy  *int                   `json:"deletedBy,omitempty" gorm:"column:deleted_by"`
+}
+
+type LoginInput struct {
+	Account  string `json:"account" gorm:"column:account"`
+	Password string `json:"password" gorm:"column:password"`
+}
+
+type Marker struct {
+	ID            int                    `json:"id" gorm:"column:id;uniqueIndex;primaryKey;autoIcrement"`
+	UUID          uuid.UUID              `json:"uuid" gorm:"column:uuid;uniqueIndex;type:uuid;default:uuid_generate_v4()"`
+	Title         string                 `json:"title" gorm:"column:title"`
+	Lat           float64                `json:"lat" gorm:"column:lat"`
+	Lng           float64                `json:"lng" gorm:"column:lng"`
+	Start         int64                  `json:"start" gorm:"column:start"`
+	End           int64                  `json:"end" gorm:"column:end"`
+	MarkerTypeID  int                    `json:"markerTypeId" gorm:"column:marker_type_id"`
+	MarkerType    *MarkerType            `json:"markerType"`
+	Description   *string                `json:"description,omitempty" gorm:"column:description"`
+	Rrule         *string                `json:"rrule,omitempty" gorm:"column:rrule"`
+	Timezone      *string                `json:"timezone,omitempty" gorm:"column:timezone"`
+	RecurrenceEnd *int64                 `json:"recurrenceEnd,omitempty" gorm:"column:recurrence_end"`
+	CreatedAt     int64                  `json:"createdAt" gorm:"column:created_at;type:bigint;autoCreateTime:milli"`
+	UpdatedAt     int64                  `json:"updatedAt" gorm:"column:updated_at;type:bigint;autoUpdateTime:milli"`
+	DeletedAt     *soft_delete.DeletedAt `json:"deletedAt,omitempty" gorm:"column:deleted_at;type:bigint;softDelete:milli;default:0"`
+	CreatedBy     int                    `json:"createdBy" gorm:"column:created_by"`
+	UpdatedBy     *int                   `json:"updatedBy,omitempty" gorm:"column:updated_by"`
+	DeletedBy     *int                   `json:"deletedBy,omitempty" gorm:"column:deleted_by"`
+}
+
+type MarkerOccurrence struct {
+	Start int64 `json:"start" gorm:"column:start"`
+	End   int64 `json:"end" gorm:"column:end"`
+}
+
 type MarkerType struct {
-	ID        int                    `json:"id" gorm:"column:id;uniqueIndex;primaryKey;autoIcrement"`
-	UUID      uuid.UUID              `json:"uuid" gorm:"column:uuid;uniqueIndex;type:uuid;default:uuid_generate_v4()"`
-	Name      string                 `json:"name" gorm:"index:idx_markertype_name;column:name"`
-	Icon      string                 `json:"icon" gorm:"column:icon"`
-	CreatedAt int64                  `json:"createdAt" gorm:"column:created_at;type:bigint;autoCreateTime:milli"`
-	UpdatedAt int64                  `json:"updatedAt" gorm:"column:updated_at;type:bigint;autoUpdateTime:milli"`
-	DeletedAt *soft_delete.DeletedAt `json:"deletedAt,omitempty" gorm:"column:deleted_at;type:bigint;softDelete:milli;default:0"`
-	CreatedBy int                    `json:"createdBy" gorm:"column:created_by"`
-	UpdatedBy *int                   `json:"updatedBy,omitempty" gorm:"column:updated_by"`
-	DeletedBy *int                   `json:"deletedBy,omitempty" gorm:"column:deleted_by"`
+	ID            int                    `json:"id" gorm:"column:id;uniqueIndex;primaryKey;autoIcrement"`
+	UUID          uuid.UUID              `json:"uuid" gorm:"column:uuid;uniqueIndex;type:uuid;default:uuid_generate_v4()"`
+	Name          string                 `json:"name" gorm:"index:idx_markertype_name;column:name"`
+	Icon          string                 `json:"icon" gorm:"column:icon"`
+	AlertRadiusNm *float64               `json:"alertRadiusNm,omitempty" gorm:"column:alert_radius_nm"`
+	AlertSeverity *AlertSeverity         `json:"alertSeverity,omitempty" gorm:"column:alert_severity"`
+	CreatedAt     int64                  `json:"createdAt" gorm:"column:created_at;type:bigint;autoCreateTime:milli"`
+	UpdatedAt     int64                  `json:"updatedAt" gorm:"column:updated_at;type:bigint;autoUpdateTime:milli"`
+	DeletedAt     *soft_delete.DeletedAt `json:"deletedAt,omitempty" gorm:"column:deleted_at;type:bigint;softDelete:milli;default:0"`
+	CreatedBy     int                    `json:"createdBy" gorm:"column:created_by"`
+	UpdatedBy     *int                   `json:"updatedBy,omitempty" gorm:"column:updated_by"`
+	DeletedBy     *int                   `json:"deletedBy,omitempty" gorm:"column:deleted_by"`
 }
 
 type Menu struct {
@@ -341,18 +540,65 @@ type Role struct {
 	DeletedBy *int                   `json:"deletedBy,omitempty" gorm:"column:deleted_by"`
 }
 
+type SarAssistInput struct {
+	IncidentID    *int                `json:"incidentId,omitempty" gorm:"column:incident_id"`
+	Latitude      *float64            `json:"latitude,omitempty" gorm:"column:latitude"`
+	Longitude     *float64            `json:"longitude,omitempty" gorm:"column:longitude"`
+	RadiusNm      *float64            `json:"radiusNm,omitempty" gorm:"column:radius_nm"`
+	MaxAgeSeconds *int                `json:"maxAgeSeconds,omitempty" gorm:"column:max_age_seconds"`
+	MinSpeedKnots *float64            `json:"minSpeedKnots,omitempty" gorm:"column:min_speed_knots"`
+	Limit         *int                `json:"limit,omitempty" gorm:"column:limit"`
+	Pattern       *SearchPatternInput `json:"pattern,omitempty"`
+}
+
+type SearchPatternInput struct {
+	Type           SearchPatternType `json:"type" gorm:"column:type"`
+	TrackSpacingNm *float64          `json:"trackSpacingNm,omitempty" gorm:"column:track_spacing_nm"`
+	RadiusNm       *float64          `json:"radiusNm,omitempty" gorm:"column:radius_nm"`
+	InitialCourse  *float64          `json:"initialCourse,omitempty" gorm:"column:initial_course"`
+	SpeedKnots     *float64          `json:"speedKnots,omitempty" gorm:"column:speed_knots"`
+}
+
 type Ship struct {
-	ID          int                    `json:"id" gorm:"column:id;uniqueIndex;primaryKey;autoIcrement"`
-	UUID        uuid.UUID              `json:"uuid" gorm:"column:uuid;uniqueIndex;type:uuid;default:uuid_generate_v4()"`
-	Name        string                 `json:"name" gorm:"index:idx_ship_name;column:name"`
-	Number      *string                `json:"number,omitempty" gorm:"column:number"`
-	Description *string                `json:"description,omitempty" gorm:"column:description"`
-	CreatedAt   int64                  `json:"createdAt" gorm:"column:created_at;type:bigint;autoCreateTime:milli"`
-	UpdatedAt   int64                  `json:"updatedAt" gorm:"column:updated_at;type:bigint;autoUpdateTime:milli"`
-	DeletedAt   *soft_delete.DeletedAt `json:"deletedAt,omitempty" gorm:"column:deleted_at;type:bigint;softDelete:milli;default:0"`
-	CreatedBy   int                    `json:"createdBy" gorm:"column:created_by"`
-	UpdatedBy   *int                   `json:"updatedBy,omitempty" gorm:"column:updated_by"`
-	DeletedBy   *int                   `json:"deletedBy,omitempty" gorm:"column:deleted_by"`
+	ID           int                    `json:"id" gorm:"column:id;uniqueIndex;primaryKey;autoIcrement"`
+	UUID         uuid.UUID              `json:"uuid" gorm:"column:uuid;uniqueIndex;type:uuid;default:uuid_generate_v4()"`
+	Name         string                 `json:"name" gorm:"index:idx_ship_name;column:name"`
+	Number       *string                `json:"number,omitempty" gorm:"column:number"`
+	Description  *string                `json:"description,omitempty" gorm:"column:description"`
+	Mmsi         *int64                 `json:"mmsi,omitempty" gorm:"uniqueIndex:idx_ship_mmsi,WHERE:deleted_at=0;column:mmsi"`
+	Imo          *int64                 `json:"imo,omitempty" gorm:"uniqueIndex:idx_ship_imo,WHERE:deleted_at=0;column:imo"`
+	CallSign     *string                `json:"callSign,omitempty" gorm:"column:call_sign"`
+	Flag         *string                `json:"flag,omitempty" gorm:"column:flag"`
+	VesselType   *string                `json:"vesselType,omitempty" gorm:"column:vessel_type"`
+	Length       *float64               `json:"length,omitempty" gorm:"column:length"`
+	Beam         *float64               `json:"beam,omitempty" gorm:"column:beam"`
+	GrossTonnage *float64               `json:"grossTonnage,omitempty" gorm:"column:gross_tonnage"`
+	AisSyncedAt  *int64                 `json:"aisSyncedAt,omitempty" gorm:"column:ais_synced_at"`
+	CreatedAt    int64                  `json:"createdAt" gorm:"column:created_at;type:bigint;autoCreateTime:milli"`
+	UpdatedAt    int64                  `json:"updatedAt" gorm:"column:updated_at;type:bigint;autoUpdateTime:milli"`
+	DeletedAt    *soft_delete.DeletedAt `json:"deletedAt,omitempty" gorm:"column:deleted_at;type:bigint;softDelete:milli;default:0"`
+	CreatedBy    int                    `json:"createdBy" gorm:"column:created_by"`
+	UpdatedBy    *int                   `json:"updatedBy,omitempty" gorm:"column:updated_by"`
+	DeletedBy    *int                   `json:"deletedBy,omitempty" gorm:"column:deleted_by"`
+}
+
+type ShipPositionFilterInput struct {
+	MmsiList    []int64           `json:"mmsiList,omitempty" gorm:"column:mmsi_list"`
+	Imei        *string           `json:"imei,omitempty" gorm:"uniqueIndex:idx_shippositionfilterinput_imei,WHERE:deleted_at=0;column:imei"`
+	BoundingBox *BoundingBoxInput `json:"boundingBox,omitempty"`
+	GeofenceID  *int              `json:"geofenceId,omitempty" gorm:"column:geofence_id"`
+}
+
+type Subscription struct {
+}
+
+type TrackExport struct {
+	Format      TrackFileFormat `json:"format" gorm:"column:format"`
+	Filename    string          `json:"filename" gorm:"column:filename"`
+	ContentType string          `json:"contentType" gorm:"column:content_type"`
+	Encoding    string          `json:"encoding" gorm:"column:encoding"`
+	PointCount  int             `json:"pointCount" gorm:"column:point_count"`
+	Content     string          `json:"content" gorm:"column:content"`
 }
 
 type UpdateCamInput struct {
@@ -381,6 +627,25 @@ type UpdateDriverInput struct {
 	Address          *string `json:"address,omitempty" gorm:"column:address"`
 }
 
+type UpdateGeofenceAlertRuleInput struct {
+	Name            *string              `json:"name,omitempty" gorm:"index:idx_updategeofencealertruleinput_name;column:name"`
+	Description     *string              `json:"description,omitempty" gorm:"column:description"`
+	GeofenceID      *int                 `json:"geofenceId,omitempty" gorm:"column:geofence_id"`
+	Trigger         *GeofenceRuleTrigger `json:"trigger,omitempty" gorm:"column:trigger"`
+	DwellMinutes    *int                 `json:"dwellMinutes,omitempty" gorm:"column:dwell_minutes"`
+	SpeedKnots      *float64             `json:"speedKnots,omitempty" gorm:"column:speed_knots"`
+	Target          *GeofenceRuleTarget  `json:"target,omitempty" gorm:"column:target"`
+	MmsiList        []int64              `json:"mmsiList,omitempty" gorm:"column:mmsi_list"`
+	OwnerID         *int                 `json:"ownerId,omitempty" gorm:"column:owner_id"`
+	ActiveFrom      *string              `json:"activeFrom,omitempty" gorm:"column:active_from"`
+	ActiveTo        *string              `json:"activeTo,omitempty" gorm:"column:active_to"`
+	ActiveDays      []int                `json:"activeDays,omitempty" gorm:"column:active_days"`
+	Timezone        *string              `json:"timezone,omitempty" gorm:"column:timezone"`
+	CooldownMinutes *int                 `json:"cooldownMinutes,omitempty" gorm:"column:cooldown_minutes"`
+	Severity        *AlertSeverity       `json:"severity,omitempty" gorm:"column:severity"`
+	IsActive        *bool                `json:"isActive,omitempty" gorm:"column:is_active"`
+}
+
 type UpdateGeofenceInput struct {
 	Name        *string  `json:"name,omitempty" gorm:"index:idx_updategeofenceinput_name;column:name"`
 	Description *string  `json:"description,omitempty" gorm:"column:description"`
@@ -401,11 +666,15 @@ type UpdateMarkerInput struct {
 	Duration     *DurationTimeInput `json:"duration"`
 	MarkerTypeID int                `json:"markerTypeId" gorm:"column:marker_type_id"`
 	Description  *string            `json:"description,omitempty" gorm:"column:description"`
+	Rrule        *string            `json:"rrule,omitempty" gorm:"column:rrule"`
+	Timezone     *string            `json:"timezone,omitempty" gorm:"column:timezone"`
 }
 
 type UpdateMarkerTypeInput struct {
-	Name string `json:"name" gorm:"index:idx_updatemarkertypeinput_name;column:name"`
-	Icon string `json:"icon" gorm:"column:icon"`
+	Name          string         `json:"name" gorm:"index:idx_updatemarkertypeinput_name;column:name"`
+	Icon          string         `json:"icon" gorm:"column:icon"`
+	AlertRadiusNm *float64       `json:"alertRadiusNm,omitempty" gorm:"column:alert_radius_nm"`
+	AlertSeverity *AlertSeverity `json:"alertSeverity,omitempty" gorm:"column:alert_severity"`
 }
 
 type UpdateMenuInput struct {
@@ -436,9 +705,17 @@ type UpdateRoleInput struct {
 }
 
 type UpdateShipInput struct {
-	Name        string  `json:"name" gorm:"index:idx_updateshipinput_name;column:name"`
-	Number      *string `json:"number,omitempty" gorm:"column:number"`
-	Description *string `json:"description,omitempty" gorm:"column:description"`
+	Name         string   `json:"name" gorm:"index:idx_updateshipinput_name;column:name"`
+	Number       *string  `json:"number,omitempty" gorm:"column:number"`
+	Description  *string  `json:"description,omitempty" gorm:"column:description"`
+	Mmsi         *int64   `json:"mmsi,omitempty" gorm:"column:mmsi"`
+	Imo          *int64   `json:"imo,omitempty" gorm:"column:imo"`
+	CallSign     *string  `json:"callSign,omitempty" gorm:"column:call_sign"`
+	Flag         *string  `json:"flag,omitempty" gorm:"column:flag"`
+	VesselType   *string  `json:"vesselType,omitempty" gorm:"column:vessel_type"`
+	Length       *float64 `json:"length,omitempty" gorm:"column:length"`
+	Beam         *float64 `json:"beam,omitempty" gorm:"column:beam"`
+	GrossTonnage *float64 `json:"grossTonnage,omitempty" gorm:"column:gross_tonnage"`
 }
 
 type UpdateUserInput struct {
@@ -498,52 +775,87 @@ type Users2role struct {
 	DeletedBy *int                   `json:"deletedBy,omitempty" gorm:"column:deleted_by"`
 }
 
-type RoleEnum string
+type Voyage struct {
+	ID              int                    `json:"id" gorm:"column:id;uniqueIndex;primaryKey;autoIcrement"`
+	UUID            uuid.UUID              `json:"uuid" gorm:"column:uuid;uniqueIndex;type:uuid;default:uuid_generate_v4()"`
+	Mmsi            int64                  `json:"mmsi" gorm:"column:mmsi"`
+	Kind            VoyageKind             `json:"kind" gorm:"column:kind"`
+	StartTime       int64                  `json:"startTime" gorm:"column:start_time"`
+	EndTime         int64                  `json:"endTime" gorm:"column:end_time"`
+	StartLatitude   float64                `json:"startLatitude" gorm:"column:start_latitude"`
+	StartLongitude  float64                `json:"startLongitude" gorm:"column:start_longitude"`
+	EndLatitude     float64                `json:"endLatitude" gorm:"column:end_latitude"`
+	EndLongitude    float64                `json:"endLongitude" gorm:"column:end_longitude"`
+	StartGeofenceID *int                   `json:"startGeofenceId,omitempty" gorm:"column:start_geofence_id"`
+	EndGeofenceID   *int                   `json:"endGeofenceId,omitempty" gorm:"column:end_geofence_id"`
+	DistanceNm      float64                `json:"distanceNm" gorm:"column:distance_nm"`
+	MaxSpeed        float64                `json:"maxSpeed" gorm:"column:max_speed"`
+	AverageSpeed    float64                `json:"averageSpeed" gorm:"column:average_speed"`
+	PositionCount   int                    `json:"positionCount" gorm:"column:position_count"`
+	Ongoing         bool                   `json:"ongoing" gorm:"column:ongoing"`
+	CreatedAt       int64                  `json:"createdAt" gorm:"column:created_at;type:bigint;autoCreateTime:milli"`
+	UpdatedAt       int64                  `json:"updatedAt" gorm:"column:updated_at;type:bigint;autoUpdateTime:milli"`
+	DeletedAt       *soft_delete.DeletedAt `json:"deletedAt,omitempty" gorm:"column:deleted_at;type:bigint;softDelete:milli;default:0"`
+	CreatedBy       int                    `json:"createdBy" gorm:"column:created_by"`
+	UpdatedBy       *int                   `json:"updatedBy,omitempty" gorm:"column:updated_by"`
+	DeletedBy       *int                   `json:"deletedBy,omitempty" gorm:"column:deleted_by"`
+}
+
+type VoyageDetectionInput struct {
+	MovingSpeed     *float64 `json:"movingSpeed,omitempty" gorm:"column:moving_speed"`
+	MinStopSeconds  *int     `json:"minStopSeconds,omitempty" gorm:"column:min_stop_seconds"`
+	MaxGapSeconds   *int     `json:"maxGapSeconds,omitempty" gorm:"column:max_gap_seconds"`
+	PortGeofenceIds []int    `json:"portGeofenceIds,omitempty" gorm:"column:port_geofence_ids"`
+}
+
+type AisAnomalyKind string
 
 const (
-	RoleEnumAdmin    RoleEnum = "ADMIN"
-	RoleEnumOperator RoleEnum = "OPERATOR"
-	RoleEnumUser     RoleEnum = "USER"
-	RoleEnumDriver   RoleEnum = "DRIVER"
+	AisAnomalyKindPositionJump         AisAnomalyKind = "POSITION_JUMP"
+	AisAnomalyKindDuplicateMmsi        AisAnomalyKind = "DUPLICATE_MMSI"
+	AisAnomalyKindInvalidMmsi          AisAnomalyKind = "INVALID_MMSI"
+	AisAnomalyKindPositionNotAvailable AisAnomalyKind = "POSITION_NOT_AVAILABLE"
+	AisAnomalyKindSogNotAvailable      AisAnomalyKind = "SOG_NOT_AVAILABLE"
 )
 
-var AllRoleEnum = []RoleEnum{
-	RoleEnumAdmin,
-	RoleEnumOperator,
-	RoleEnumUser,
-	RoleEnumDriver,
+var AllAisAnomalyKind = []AisAnomalyKind{
+	AisAnomalyKindPositionJump,
+	AisAnomalyKindDuplicateMmsi,
+	AisAnomalyKindInvalidMmsi,
+	AisAnomalyKindPositionNotAvailable,
+	AisAnomalyKindSogNotAvailable,
 }
 
-func (e RoleEnum) IsValid() bool {
+func (e AisAnomalyKind) IsValid() bool {
 	switch e {
-	case RoleEnumAdmin, RoleEnumOperator, RoleEnumUser, RoleEnumDriver:
+	case AisAnomalyKindPositionJump, AisAnomalyKindDuplicateMmsi, AisAnomalyKindInvalidMmsi, AisAnomalyKindPositionNotAvailable, AisAnomalyKindSogNotAvailable:
 		return true
 	}
 	return false
 }
 
-func (e RoleEnum) String() string {
+func (e AisAnomalyKind) String() string {
 	return string(e)
 }
 
-func (e *RoleEnum) UnmarshalGQL(v any) error {
+func (e *AisAnomalyKind) UnmarshalGQL(v any) error {
 	str, ok := v.(string)
 	if !ok {
 		return fmt.Errorf("enums must be strings")
 	}
 
-	*e = RoleEnum(str)
+	*e = AisAnomalyKind(str)
 	if !e.IsValid() {
-		return fmt.Errorf("%s is not a valid RoleEnum", str)
+		return fmt.Errorf("%s is not a valid AisAnomalyKind", str)
 	}
 	return nil
 }
 
-func (e RoleEnum) MarshalGQL(w io.Writer) {
+func (e AisAnomalyKind) MarshalGQL(w io.Writer) {
 	fmt.Fprint(w, strconv.Quote(e.String()))
 }
 
-func (e *RoleEnum) UnmarshalJSON(b []byte) error {
+func (e *AisAnomalyKind) UnmarshalJSON(b []byte) error {
 	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
@@ -551,7 +863,1172 @@ func (e *RoleEnum) UnmarshalJSON(b []byte) error {
 	return e.UnmarshalGQL(s)
 }
 
-func (e RoleEnum) MarshalJSON() ([]byte, error) {
+func (e AisAnomalyKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type AlertKind string
+
+const (
+	AlertKindCollisionRisk   AlertKind = "COLLISION_RISK"
+	AlertKindGeofence        AlertKind = "GEOFENCE"
+	AlertKindHazardProximity AlertKind = "HAZARD_PROXIMITY"
+	AlertKindAisGap          AlertKind = "AIS_GAP"
+)
+
+var AllAlertKind = []AlertKind{
+	AlertKindCollisionRisk,
+	AlertKindGeofence,
+	AlertKindHazardProximity,
+	AlertKindAisGap,
+}
+
+func (e AlertKind) IsValid() bool {
+	switch e {
+	case AlertKindCollisionRisk, AlertKindGeofence, AlertKindHazardProximity, AlertKindAisGap:
+		return true
+	}
+	return false
+}
+
+func (e AlertKind) String() string {
+	return string(e)
+}
+
+func (e *AlertKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AlertKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AlertKind", str)
+	}
+	return nil
+}
+
+func (e AlertKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *AlertKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e AlertKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type AlertSeverity string
+
+const (
+	AlertSeverityInfo     AlertSeverity = "INFO"
+	AlertSeverityWarning  AlertSeverity = "WARNING"
+	AlertSeverityCritical AlertSeverity = "CRITICAL"
+)
+
+var AllAlertSeverity = []AlertSeverity{
+	AlertSeverityInfo,
+	AlertSeverityWarning,
+	AlertSeverityCritical,
+}
+
+func (e AlertSeverity) IsValid() bool {
+	switch e {
+	case AlertSeverityInfo, AlertSeverityWarning, AlertSeverityCritical:
+		return true
+	}
+	return false
+}
+
+func (e AlertSeverity) String() string {
+	return string(e)
+}
+
+func (e *AlertSeverity) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AlertSeverity(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AlertSeverity", str)
+	}
+	return nil
+}
+
+func (e AlertSeverity) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *AlertSeverity) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e AlertSeverity) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type AlertStatus string
+
+const (
+	AlertStatusOpen         AlertStatus = "OPEN"
+	AlertStatusAcknowledged AlertStatus = "ACKNOWLEDGED"
+	AlertStatusResolved     AlertStatus = "RESOLVED"
+)
+
+var AllAlertStatus = []AlertStatus{
+	AlertStatusOpen,
+	AlertStatusAcknowledged,
+	AlertStatusResolved,
+}
+
+func (e AlertStatus) IsValid() bool {
+	switch e {
+	case AlertStatusOpen, AlertStatusAcknowledged, AlertStatusResolved:
+		return true
+	}
+	return false
+}
+
+func (e AlertStatus) String() string {
+	return string(e)
+}
+
+func (e *AlertStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = AlertStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid AlertStatus", str)
+	}
+	return nil
+}
+
+func (e AlertStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *AlertStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e AlertStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type GeofenceDuplicateStrategy string
+
+const (
+	GeofenceDuplicateStrategySkip      GeofenceDuplicateStrategy = "SKIP"
+	GeofenceDuplicateStrategyOverwrite GeofenceDuplicateStrategy = "OVERWRITE"
+)
+
+var AllGeofenceDuplicateStrategy = []GeofenceDuplicateStrategy{
+	GeofenceDuplicateStrategySkip,
+	GeofenceDuplicateStrategyOverwrite,
+}
+
+func (e GeofenceDuplicateStrategy) IsValid() bool {
+	switch e {
+	case GeofenceDuplicateStrategySkip, GeofenceDuplicateStrategyOverwrite:
+		return true
+	}
+	return false
+}
+
+func (e GeofenceDuplicateStrategy) String() string {
+	return string(e)
+}
+
+func (e *GeofenceDuplicateStrategy) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GeofenceDuplicateStrategy(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GeofenceDuplicateStrategy", str)
+	}
+	return nil
+}
+
+func (e GeofenceDuplicateStrategy) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *GeofenceDuplicateStrategy) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e GeofenceDuplicateStrategy) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type GeofenceEventKind string
+
+const (
+	GeofenceEventKindEnter GeofenceEventKind = "ENTER"
+	GeofenceEventKindExit  GeofenceEventKind = "EXIT"
+	GeofenceEventKindDwell GeofenceEventKind = "DWELL"
+)
+
+var AllGeofenceEventKind = []GeofenceEventKind{
+	GeofenceEventKindEnter,
+	GeofenceEventKindExit,
+	GeofenceEventKindDwell,
+}
+
+func (e GeofenceEventKind) IsValid() bool {
+	switch e {
+	case GeofenceEventKindEnter, GeofenceEventKindExit, GeofenceEventKindDwell:
+		return true
+	}
+	return false
+}
+
+func (e GeofenceEventKind) String() string {
+	return string(e)
+}
+
+func (e *GeofenceEventKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GeofenceEventKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GeofenceEventKind", str)
+	}
+	return nil
+}
+
+func (e GeofenceEventKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *GeofenceEventKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e GeofenceEventKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type GeofenceFileFormat string
+
+const (
+	GeofenceFileFormatGeojson GeofenceFileFormat = "GEOJSON"
+	GeofenceFileFormatKml     GeofenceFileFormat = "KML"
+	GeofenceFileFormatKmz     GeofenceFileFormat = "KMZ"
+)
+
+var AllGeofenceFileFormat = []GeofenceFileFormat{
+	GeofenceFileFormatGeojson,
+	GeofenceFileFormatKml,
+	GeofenceFileFormatKmz,
+}
+
+func (e GeofenceFileFormat) IsValid() bool {
+	switch e {
+	case GeofenceFileFormatGeojson, GeofenceFileFormatKml, GeofenceFileFormatKmz:
+		return true
+	}
+	return false
+}
+
+func (e GeofenceFileFormat) String() string {
+	return string(e)
+}
+
+func (e *GeofenceFileFormat) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GeofenceFileFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GeofenceFileFormat", str)
+	}
+	return nil
+}
+
+func (e GeofenceFileFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *GeofenceFileFormat) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e GeofenceFileFormat) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type GeofenceImportAction string
+
+const (
+	GeofenceImportActionCreate  GeofenceImportAction = "CREATE"
+	GeofenceImportActionUpdate  GeofenceImportAction = "UPDATE"
+	GeofenceImportActionSkip    GeofenceImportAction = "SKIP"
+	GeofenceImportActionInvalid GeofenceImportAction = "INVALID"
+)
+
+var AllGeofenceImportAction = []GeofenceImportAction{
+	GeofenceImportActionCreate,
+	GeofenceImportActionUpdate,
+	GeofenceImportActionSkip,
+	GeofenceImportActionInvalid,
+}
+
+func (e GeofenceImportAction) IsValid() bool {
+	switch e {
+	case GeofenceImportActionCreate, GeofenceImportActionUpdate, GeofenceImportActionSkip, GeofenceImportActionInvalid:
+		return true
+	}
+	return false
+}
+
+func (e GeofenceImportAction) String() string {
+	return string(e)
+}
+
+func (e *GeofenceImportAction) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GeofenceImportAction(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GeofenceImportAction", str)
+	}
+	return nil
+}
+
+func (e GeofenceImportAction) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *GeofenceImportAction) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e GeofenceImportAction) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type GeofenceRuleTarget string
+
+const (
+	GeofenceRuleTargetAll      GeofenceRuleTarget = "ALL"
+	GeofenceRuleTargetMmsiList GeofenceRuleTarget = "MMSI_LIST"
+	GeofenceRuleTargetOwner    GeofenceRuleTarget = "OWNER"
+)
+
+var AllGeofenceRuleTarget = []GeofenceRuleTarget{
+	GeofenceRuleTargetAll,
+	GeofenceRuleTargetMmsiList,
+	GeofenceRuleTargetOwner,
+}
+
+func (e GeofenceRuleTarget) IsValid() bool {
+	switch e {
+	case GeofenceRuleTargetAll, GeofenceRuleTargetMmsiList, GeofenceRuleTargetOwner:
+		return true
+	}
+	return false
+}
+
+func (e GeofenceRuleTarget) String() string {
+	return string(e)
+}
+
+func (e *GeofenceRuleTarget) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GeofenceRuleTarget(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GeofenceRuleTarget", str)
+	}
+	return nil
+}
+
+func (e GeofenceRuleTarget) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *GeofenceRuleTarget) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e GeofenceRuleTarget) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type GeofenceRuleTrigger string
+
+const (
+	GeofenceRuleTriggerEnter    GeofenceRuleTrigger = "ENTER"
+	GeofenceRuleTriggerExit     GeofenceRuleTrigger = "EXIT"
+	GeofenceRuleTriggerDwell    GeofenceRuleTrigger = "DWELL"
+	GeofenceRuleTriggerSpeeding GeofenceRuleTrigger = "SPEEDING"
+)
+
+var AllGeofenceRuleTrigger = []GeofenceRuleTrigger{
+	GeofenceRuleTriggerEnter,
+	GeofenceRuleTriggerExit,
+	GeofenceRuleTriggerDwell,
+	GeofenceRuleTriggerSpeeding,
+}
+
+func (e GeofenceRuleTrigger) IsValid() bool {
+	switch e {
+	case GeofenceRuleTriggerEnter, GeofenceRuleTriggerExit, GeofenceRuleTriggerDwell, GeofenceRuleTriggerSpeeding:
+		return true
+	}
+	return false
+}
+
+func (e GeofenceRuleTrigger) String() string {
+	return string(e)
+}
+
+func (e *GeofenceRuleTrigger) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = GeofenceRuleTrigger(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid GeofenceRuleTrigger", str)
+	}
+	return nil
+}
+
+func (e GeofenceRuleTrigger) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *GeofenceRuleTrigger) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e GeofenceRuleTrigger) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type IncidentEventKind string
+
+const (
+	IncidentEventKindOpened        IncidentEventKind = "OPENED"
+	IncidentEventKindStatusChanged IncidentEventKind = "STATUS_CHANGED"
+	IncidentEventKindAssigned      IncidentEventKind = "ASSIGNED"
+	IncidentEventKindNote          IncidentEventKind = "NOTE"
+)
+
+var AllIncidentEventKind = []IncidentEventKind{
+	IncidentEventKindOpened,
+	IncidentEventKindStatusChanged,
+	IncidentEventKindAssigned,
+	IncidentEventKindNote,
+}
+
+func (e IncidentEventKind) IsValid() bool {
+	switch e {
+	case IncidentEventKindOpened, IncidentEventKindStatusChanged, IncidentEventKindAssigned, IncidentEventKindNote:
+		return true
+	}
+	return false
+}
+
+func (e IncidentEventKind) String() string {
+	return string(e)
+}
+
+func (e *IncidentEventKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = IncidentEventKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid IncidentEventKind", str)
+	}
+	return nil
+}
+
+func (e IncidentEventKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *IncidentEventKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e IncidentEventKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type IncidentStatus string
+
+const (
+	IncidentStatusNew          IncidentStatus = "NEW"
+	IncidentStatusAcknowledged IncidentStatus = "ACKNOWLEDGED"
+	IncidentStatusInProgress   IncidentStatus = "IN_PROGRESS"
+	IncidentStatusResolved     IncidentStatus = "RESOLVED"
+	IncidentStatusFalseAlarm   IncidentStatus = "FALSE_ALARM"
+)
+
+var AllIncidentStatus = []IncidentStatus{
+	IncidentStatusNew,
+	IncidentStatusAcknowledged,
+	IncidentStatusInProgress,
+	IncidentStatusResolved,
+	IncidentStatusFalseAlarm,
+}
+
+func (e IncidentStatus) IsValid() bool {
+	switch e {
+	case IncidentStatusNew, IncidentStatusAcknowledged, IncidentStatusInProgress, IncidentStatusResolved, IncidentStatusFalseAlarm:
+		return true
+	}
+	return false
+}
+
+func (e IncidentStatus) String() string {
+	return string(e)
+}
+
+func (e *IncidentStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = IncidentStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid IncidentStatus", str)
+	}
+	return nil
+}
+
+func (e IncidentStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *IncidentStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e IncidentStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type MobKind string
+
+const (
+	MobKindAisSart       MobKind = "AIS_SART"
+	MobKindMob           MobKind = "MOB"
+	MobKindEpirb         MobKind = "EPIRB"
+	MobKindSafetyMessage MobKind = "SAFETY_MESSAGE"
+)
+
+var AllMobKind = []MobKind{
+	MobKindAisSart,
+	MobKindMob,
+	MobKindEpirb,
+	MobKindSafetyMessage,
+}
+
+func (e MobKind) IsValid() bool {
+	switch e {
+	case MobKindAisSart, MobKindMob, MobKindEpirb, MobKindSafetyMessage:
+		return true
+	}
+	return false
+}
+
+func (e MobKind) String() string {
+	return string(e)
+}
+
+func (e *MobKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = MobKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid MobKind", str)
+	}
+	return nil
+}
+
+func (e MobKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *MobKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e MobKind) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type NavigationalStatus string
+
+const (
+	NavigationalStatusUnderWayUsingEngine       NavigationalStatus = "UNDER_WAY_USING_ENGINE"
+	NavigationalStatusAtAnchor                  NavigationalStatus = "AT_ANCHOR"
+	NavigationalStatusNotUnderCommand           NavigationalStatus = "NOT_UNDER_COMMAND"
+	NavigationalStatusRestrictedManoeuvrability NavigationalStatus = "RESTRICTED_MANOEUVRABILITY"
+	NavigationalStatusConstrainedByDraught      NavigationalStatus = "CONSTRAINED_BY_DRAUGHT"
+	NavigationalStatusMoored                    NavigationalStatus = "MOORED"
+	NavigationalStatusAground                   NavigationalStatus = "AGROUND"
+	NavigationalStatusEngagedInFishing          NavigationalStatus = "ENGAGED_IN_FISHING"
+	NavigationalStatusUnderWaySailing           NavigationalStatus = "UNDER_WAY_SAILING"
+	NavigationalStatusReservedHsc               NavigationalStatus = "RESERVED_HSC"
+	NavigationalStatusReservedWig               NavigationalStatus = "RESERVED_WIG"
+	NavigationalStatusPowerDrivenTowingAstern   NavigationalStatus = "POWER_DRIVEN_TOWING_ASTERN"
+	NavigationalStatusPowerDrivenPushingAhead   NavigationalStatus = "POWER_DRIVEN_PUSHING_AHEAD"
+	NavigationalStatusReserved                  NavigationalStatus = "RESERVED"
+	NavigationalStatusAisSartActive             NavigationalStatus = "AIS_SART_ACTIVE"
+	NavigationalStatusNotDefined                NavigationalStatus = "NOT_DEFINED"
+)
+
+var AllNavigationalStatus = []NavigationalStatus{
+	NavigationalStatusUnderWayUsingEngine,
+	NavigationalStatusAtAnchor,
+	NavigationalStatusNotUnderCommand,
+	NavigationalStatusRestrictedManoeuvrability,
+	NavigationalStatusConstrainedByDraught,
+	NavigationalStatusMoored,
+	NavigationalStatusAground,
+	NavigationalStatusEngagedInFishing,
+	NavigationalStatusUnderWaySailing,
+	NavigationalStatusReservedHsc,
+	NavigationalStatusReservedWig,
+	NavigationalStatusPowerDrivenTowingAstern,
+	NavigationalStatusPowerDrivenPushingAhead,
+	NavigationalStatusReserved,
+	NavigationalStatusAisSartActive,
+	NavigationalStatusNotDefined,
+}
+
+func (e NavigationalStatus) IsValid() bool {
+	switch e {
+	case NavigationalStatusUnderWayUsingEngine, NavigationalStatusAtAnchor, NavigationalStatusNotUnderCommand, NavigationalStatusRestrictedManoeuvrability, NavigationalStatusConstrainedByDraught, NavigationalStatusMoored, NavigationalStatusAground, NavigationalStatusEngagedInFishing, NavigationalStatusUnderWaySailing, NavigationalStatusReservedHsc, NavigationalStatusReservedWig, NavigationalStatusPowerDrivenTowingAstern, NavigationalStatusPowerDrivenPushingAhead, NavigationalStatusReserved, NavigationalStatusAisSartActive, NavigationalStatusNotDefined:
+		return true
+	}
+	return false
+}
+
+func (e NavigationalStatus) String() string {
+	return string(e)
+}
+
+func (e *NavigationalStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = NavigationalStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid NavigationalStatus", str)
+	}
+	return nil
+}
+
+func (e NavigationalStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *NavigationalStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e NavigationalStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ReplayFrameStatus string
+
+const (
+	ReplayFrameStatusObserved     ReplayFrameStatus = "OBSERVED"
+	ReplayFrameStatusInterpolated ReplayFrameStatus = "INTERPOLATED"
+	ReplayFrameStatusGap          ReplayFrameStatus = "GAP"
+	ReplayFrameStatusNoData       ReplayFrameStatus = "NO_DATA"
+)
+
+var AllReplayFrameStatus = []ReplayFrameStatus{
+	ReplayFrameStatusObserved,
+	ReplayFrameStatusInterpolated,
+	ReplayFrameStatusGap,
+	ReplayFrameStatusNoData,
+}
+
+func (e ReplayFrameStatus) IsValid() bool {
+	switch e {
+	case ReplayFrameStatusObserved, ReplayFrameStatusInterpolated, ReplayFrameStatusGap, ReplayFrameStatusNoData:
+		return true
+	}
+	return false
+}
+
+func (e ReplayFrameStatus) String() string {
+	return string(e)
+}
+
+func (e *ReplayFrameStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ReplayFrameStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ReplayFrameStatus", str)
+	}
+	return nil
+}
+
+func (e ReplayFrameStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ReplayFrameStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ReplayFrameStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type RoleEnum string
+
+const (
+	RoleEnumAdmin    RoleEnum = "ADMIN"
+	RoleEnumOperator RoleEnum = "OPERATOR"
+	RoleEnumUser     RoleEnum = "USER"
+	RoleEnumDriver   RoleEnum = "DRIVER"
+)
+
+var AllRoleEnum = []RoleEnum{
+	RoleEnumAdmin,
+	RoleEnumOperator,
+	RoleEnumUser,
+	RoleEnumDriver,
+}
+
+func (e RoleEnum) IsValid() bool {
+	switch e {
+	case RoleEnumAdmin, RoleEnumOperator, RoleEnumUser, RoleEnumDriver:
+		return true
+	}
+	return false
+}
+
+func (e RoleEnum) String() string {
+	return string(e)
+}
+
+func (e *RoleEnum) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = RoleEnum(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid RoleEnum", str)
+	}
+	return nil
+}
+
+func (e RoleEnum) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *RoleEnum) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e RoleEnum) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type SearchPatternType string
+
+const (
+	SearchPatternTypeExpandingSquare SearchPatternType = "EXPANDING_SQUARE"
+	SearchPatternTypeSector          SearchPatternType = "SECTOR"
+)
+
+var AllSearchPatternType = []SearchPatternType{
+	SearchPatternTypeExpandingSquare,
+	SearchPatternTypeSector,
+}
+
+func (e SearchPatternType) IsValid() bool {
+	switch e {
+	case SearchPatternTypeExpandingSquare, SearchPatternTypeSector:
+		return true
+	}
+	return false
+}
+
+func (e SearchPatternType) String() string {
+	return string(e)
+}
+
+func (e *SearchPatternType) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = SearchPatternType(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid SearchPatternType", str)
+	}
+	return nil
+}
+
+func (e SearchPatternType) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *SearchPatternType) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e SearchPatternType) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ShipTypeCategory string
+
+const (
+	ShipTypeCategoryNotAvailable    ShipTypeCategory = "NOT_AVAILABLE"
+	ShipTypeCategoryReserved        ShipTypeCategory = "RESERVED"
+	ShipTypeCategoryWig             ShipTypeCategory = "WIG"
+	ShipTypeCategoryFishing         ShipTypeCategory = "FISHING"
+	ShipTypeCategoryTowing          ShipTypeCategory = "TOWING"
+	ShipTypeCategoryDredging        ShipTypeCategory = "DREDGING"
+	ShipTypeCategoryDiving          ShipTypeCategory = "DIVING"
+	ShipTypeCategoryMilitary        ShipTypeCategory = "MILITARY"
+	ShipTypeCategorySailing         ShipTypeCategory = "SAILING"
+	ShipTypeCategoryPleasureCraft   ShipTypeCategory = "PLEASURE_CRAFT"
+	ShipTypeCategoryHighSpeedCraft  ShipTypeCategory = "HIGH_SPEED_CRAFT"
+	ShipTypeCategoryPilot           ShipTypeCategory = "PILOT"
+	ShipTypeCategorySearchAndRescue ShipTypeCategory = "SEARCH_AND_RESCUE"
+	ShipTypeCategoryTug             ShipTypeCategory = "TUG"
+	ShipTypeCategoryPortTender      ShipTypeCategory = "PORT_TENDER"
+	ShipTypeCategoryAntiPollution   ShipTypeCategory = "ANTI_POLLUTION"
+	ShipTypeCategoryLawEnforcement  ShipTypeCategory = "LAW_ENFORCEMENT"
+	ShipTypeCategoryLocal           ShipTypeCategory = "LOCAL"
+	ShipTypeCategoryMedical         ShipTypeCategory = "MEDICAL"
+	ShipTypeCategoryNoncombatant    ShipTypeCategory = "NONCOMBATANT"
+	ShipTypeCategoryPassenger       ShipTypeCategory = "PASSENGER"
+	ShipTypeCategoryCargo           ShipTypeCategory = "CARGO"
+	ShipTypeCategoryTanker          ShipTypeCategory = "TANKER"
+	ShipTypeCategoryOther           ShipTypeCategory = "OTHER"
+)
+
+var AllShipTypeCategory = []ShipTypeCategory{
+	ShipTypeCategoryNotAvailable,
+	ShipTypeCategoryReserved,
+	ShipTypeCategoryWig,
+	ShipTypeCategoryFishing,
+	ShipTypeCategoryTowing,
+	ShipTypeCategoryDredging,
+	ShipTypeCategoryDiving,
+	ShipTypeCategoryMilitary,
+	ShipTypeCategorySailing,
+	ShipTypeCategoryPleasureCraft,
+	ShipTypeCategoryHighSpeedCraft,
+	ShipTypeCategoryPilot,
+	ShipTypeCategorySearchAndRescue,
+	ShipTypeCategoryTug,
+	ShipTypeCategoryPortTender,
+	ShipTypeCategoryAntiPollution,
+	ShipTypeCategoryLawEnforcement,
+	ShipTypeCategoryLocal,
+	ShipTypeCategoryMedical,
+	ShipTypeCategoryNoncombatant,
+	ShipTypeCategoryPassenger,
+	ShipTypeCategoryCargo,
+	ShipTypeCategoryTanker,
+	ShipTypeCategoryOther,
+}
+
+func (e ShipTypeCategory) IsValid() bool {
+	switch e {
+	case ShipTypeCategoryNotAvailable, ShipTypeCategoryReserved, ShipTypeCategoryWig, ShipTypeCategoryFishing, ShipTypeCategoryTowing, ShipTypeCategoryDredging, ShipTypeCategoryDiving, ShipTypeCategoryMilitary, ShipTypeCategorySailing, ShipTypeCategoryPleasureCraft, ShipTypeCategoryHighSpeedCraft, ShipTypeCategoryPilot, ShipTypeCategorySearchAndRescue, ShipTypeCategoryTug, ShipTypeCategoryPortTender, ShipTypeCategoryAntiPollution, ShipTypeCategoryLawEnforcement, ShipTypeCategoryLocal, ShipTypeCategoryMedical, ShipTypeCategoryNoncombatant, ShipTypeCategoryPassenger, ShipTypeCategoryCargo, ShipTypeCategoryTanker, ShipTypeCategoryOther:
+		return true
+	}
+	return false
+}
+
+func (e ShipTypeCategory) String() string {
+	return string(e)
+}
+
+func (e *ShipTypeCategory) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ShipTypeCategory(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ShipTypeCategory", str)
+	}
+	return nil
+}
+
+func (e ShipTypeCategory) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ShipTypeCategory) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ShipTypeCategory) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type TrackFileFormat string
+
+const (
+	TrackFileFormatGpx     TrackFileFormat = "GPX"
+	TrackFileFormatKml     TrackFileFormat = "KML"
+	TrackFileFormatGeojson TrackFileFormat = "GEOJSON"
+	TrackFileFormatCSV     TrackFileFormat = "CSV"
+)
+
+var AllTrackFileFormat = []TrackFileFormat{
+	TrackFileFormatGpx,
+	TrackFileFormatKml,
+	TrackFileFormatGeojson,
+	TrackFileFormatCSV,
+}
+
+func (e TrackFileFormat) IsValid() bool {
+	switch e {
+	case TrackFileFormatGpx, TrackFileFormatKml, TrackFileFormatGeojson, TrackFileFormatCSV:
+		return true
+	}
+	return false
+}
+
+func (e TrackFileFormat) String() string {
+	return string(e)
+}
+
+func (e *TrackFileFormat) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = TrackFileFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid TrackFileFormat", str)
+	}
+	return nil
+}
+
+func (e TrackFileFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *TrackFileFormat) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e TrackFileFormat) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type VoyageKind string
+
+const (
+	VoyageKindVoyage VoyageKind = "VOYAGE"
+	VoyageKindStop   VoyageKind = "STOP"
+)
+
+var AllVoyageKind = []VoyageKind{
+	VoyageKindVoyage,
+	VoyageKindStop,
+}
+
+func (e VoyageKind) IsValid() bool {
+	switch e {
+	case VoyageKindVoyage, VoyageKindStop:
+		return true
+	}
+	return false
+}
+
+func (e VoyageKind) String() string {
+	return string(e)
+}
+
+func (e *VoyageKind) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = VoyageKind(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid VoyageKind", str)
+	}
+	return nil
+}
+
+func (e VoyageKind) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *VoyageKind) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e VoyageKind) MarshalJSON() ([]byte, error) {
 	var buf bytes.Buffer
 	e.MarshalGQL(&buf)
 	return buf.Bytes(), nil
@@ -1,6 +1,7 @@
 package main
 
 import (
+	"context"
 	"log"
 
 	"github.com/gin-gonic/gin"
@@ -26,7 +27,10 @@ func main() {
 	// _ = dependencies.GraphqlHandler(r)
 
 	// GraphQL endpoints
-	r.POST("/query", middlewares.HeaderToContextMiddleware(), dependencies.GraphqlHandler(r))
+	graphqlHandler := dependencies.GraphqlHandler(r)
+	r.POST("/query", middlewares.HeaderToContextMiddleware(), graphqlHandler)
+	// GET untuk upgrade websocket (subscription)
+	r.GET("/query", middlewares.HeaderToContextMiddleware(), graphqlHandler)
 	r.GET("/", dependencies.PlaygroundHandler())
 
 	// Setup REST API routes
@@ -35,5 +39,10 @@ func main() {
 		routes.SetupAllRoutes(r, handlers)
 	}
 
+	// Background workers (AIS listener, dll)
+	for _, worker := range dependencies.GetWorkers() {
+		go worker.Start(context.Background())
+	}
+
 	r.Run()
 }